@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"go.temporal.io/cloud-sdk/cloudclient"
+	"google.golang.org/grpc"
 )
 
 type Client struct {
@@ -16,6 +17,9 @@ func NewConnectionWithAPIKey(apikey string) (*Client, error) {
 	var err error
 	cClient, err = cloudclient.New(cloudclient.Options{
 		APIKey: apikey,
+		GRPCDialOptions: []grpc.DialOption{
+			grpc.WithChainUnaryInterceptor(tracingInterceptor),
+		},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect : %v", err)
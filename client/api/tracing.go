@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"path"
+
+	"bechols/temcp/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// tracingInterceptor starts a client span for every CloudService RPC
+func tracingInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply any,
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	ctx, span := telemetry.Tracer().Start(ctx, "CloudService/"+path.Base(method),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.method", method),
+		),
+	)
+	defer span.End()
+
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if err != nil {
+		s, _ := status.FromError(err)
+		span.SetAttributes(attribute.String("rpc.grpc.status_code", s.Code().String()))
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, s.Message())
+	}
+	return err
+}
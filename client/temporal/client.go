@@ -10,6 +10,7 @@ import (
 	"bechols/temcp/internal/validator"
 	cloudservicev1 "go.temporal.io/cloud-sdk/api/cloudservice/v1"
 	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/interceptor"
 	"go.temporal.io/sdk/log"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
@@ -43,6 +44,9 @@ type (
 
 		// The logger to use for the client, defaults to no logging
 		Logger log.Logger
+
+		// The interceptors to use for the client, e.g. for tracing
+		Interceptors []interceptor.ClientInterceptor
 	}
 
 	AuthType interface {
@@ -120,8 +124,9 @@ func GetTemporalCloudNamespaceClient(ctx context.Context, input *GetTemporalClou
 	}
 
 	opts := client.Options{
-		Namespace: input.Namespace,
-		Logger:    input.Logger,
+		Namespace:    input.Namespace,
+		Logger:       input.Logger,
+		Interceptors: input.Interceptors,
 	}
 	err = input.Auth.apply(&opts)
 	if err != nil {
@@ -2,19 +2,31 @@ package clients
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"bechols/temcp/client/api"
 	"bechols/temcp/cmd/mcp-server/config"
+	"bechols/temcp/internal/telemetry"
 	"bechols/temcp/workflows"
 	"bechols/temcp/workflows/activities"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/interceptor"
 	"go.temporal.io/sdk/worker"
+	"go.temporal.io/server/common/log"
+	"go.uber.org/zap"
 )
 
+// ErrWorkflowRunning is returned by StartWorkflow when a workflow with the same ID is still running
+var ErrWorkflowRunning = errors.New("a workflow with this ID is already running")
+
 // ClientManager manages Temporal Cloud API and workflow clients
 type ClientManager struct {
 	config         *config.Config
+	logger         *zap.Logger
 	cloudClient    *api.Client
 	temporalClient client.Client
 	worker         worker.Worker
@@ -23,9 +35,10 @@ type ClientManager struct {
 }
 
 // NewClientManager creates a new client manager with the given configuration
-func NewClientManager(cfg *config.Config) (*ClientManager, error) {
+func NewClientManager(cfg *config.Config, logger *zap.Logger) (*ClientManager, error) {
 	cm := &ClientManager{
 		config: cfg,
+		logger: logger,
 	}
 
 	// Initialize Cloud API client
@@ -43,9 +56,15 @@ func NewClientManager(cfg *config.Config) (*ClientManager, error) {
 	if cfg.HasNamespaceAuth() {
 		// Create a simple local Temporal client for workflow execution
 		// In production, this would connect to Temporal Cloud
+		tracingInterceptor, err := telemetry.NewTemporalInterceptor()
+		if err != nil {
+			return nil, err
+		}
 		temporalClient, err := client.Dial(client.Options{
 			// For now, use local development setup
 			// TODO: Add proper Temporal Cloud connection
+			Interceptors: []interceptor.ClientInterceptor{tracingInterceptor},
+			Logger:       log.NewSdkLogger(log.NewZapLogger(logger)),
 		})
 		if err != nil {
 			return nil, err
@@ -60,7 +79,7 @@ func NewClientManager(cfg *config.Config) (*ClientManager, error) {
 		go func() {
 			err := cm.worker.Run(worker.InterruptCh())
 			if err != nil {
-				// TODO: Add proper error handling
+				cm.logger.Error("Workflow worker stopped", zap.Error(err))
 			}
 		}()
 	}
@@ -117,9 +136,82 @@ func (cm *ClientManager) ExecuteWorkflow(ctx context.Context, workflowType strin
 	return result, err
 }
 
+// ExecuteWorkflowInto executes a Temporal workflow and decodes its result into result, which must be a pointer
+func (cm *ClientManager) ExecuteWorkflowInto(ctx context.Context, workflowType string, args interface{}, result interface{}) error {
+	if cm.temporalClient == nil {
+		return fmt.Errorf("no Temporal client to execute workflow %s", workflowType)
+	}
+	workflowRun, err := cm.temporalClient.ExecuteWorkflow(ctx, client.StartWorkflowOptions{
+		TaskQueue: "mcp-task-queue",
+	}, workflowType, args)
+	if err != nil {
+		return err
+	}
+	return workflowRun.Get(ctx, result)
+}
+
 // ExecuteWorkflowWithTimeout executes a workflow with a timeout
 func (cm *ClientManager) ExecuteWorkflowWithTimeout(ctx context.Context, workflowType string, args interface{}, timeout time.Duration) (interface{}, error) {
 	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 	return cm.ExecuteWorkflow(timeoutCtx, workflowType, args)
 }
+
+// StartWorkflow starts a workflow with the given ID without waiting for it, for jobs that outlive a tool call.
+// It fails with ErrWorkflowRunning rather than attaching to a run of the same ID that is still going, since that
+// run was started with other inputs.
+func (cm *ClientManager) StartWorkflow(ctx context.Context, workflowID, workflowType string, args interface{}) (client.WorkflowRun, error) {
+	if cm.temporalClient == nil {
+		return nil, fmt.Errorf("no Temporal client to start workflow %s", workflowType)
+	}
+	run, err := cm.temporalClient.ExecuteWorkflow(ctx, client.StartWorkflowOptions{
+		ID:                                       workflowID,
+		TaskQueue:                                "mcp-task-queue",
+		WorkflowExecutionErrorWhenAlreadyStarted: true,
+	}, workflowType, args)
+	var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+	if errors.As(err, &alreadyStarted) {
+		return nil, fmt.Errorf("%w: %s", ErrWorkflowRunning, workflowID)
+	}
+	return run, err
+}
+
+// SignalWorkflow sends a signal to the latest run of the workflow
+func (cm *ClientManager) SignalWorkflow(ctx context.Context, workflowID, signalName string, arg interface{}) error {
+	if cm.temporalClient == nil {
+		return fmt.Errorf("no Temporal client to signal workflow %s", workflowID)
+	}
+	return cm.temporalClient.SignalWorkflow(ctx, workflowID, "", signalName, arg)
+}
+
+// QueryWorkflowInto queries the latest run of the workflow and decodes the answer into result, which must be a pointer
+func (cm *ClientManager) QueryWorkflowInto(ctx context.Context, workflowID, queryType string, result interface{}) error {
+	if cm.temporalClient == nil {
+		return fmt.Errorf("no Temporal client to query workflow %s", workflowID)
+	}
+	value, err := cm.temporalClient.QueryWorkflow(ctx, workflowID, "", queryType)
+	if err != nil {
+		return err
+	}
+	return value.Get(result)
+}
+
+// WorkflowRunning reports whether the latest run of the workflow is still running
+func (cm *ClientManager) WorkflowRunning(ctx context.Context, workflowID string) (bool, error) {
+	if cm.temporalClient == nil {
+		return false, fmt.Errorf("no Temporal client to describe workflow %s", workflowID)
+	}
+	resp, err := cm.temporalClient.DescribeWorkflowExecution(ctx, workflowID, "")
+	if err != nil {
+		return false, err
+	}
+	return resp.GetWorkflowExecutionInfo().GetStatus() == enums.WORKFLOW_EXECUTION_STATUS_RUNNING, nil
+}
+
+// WorkflowResultInto waits for the latest run of the workflow and decodes its result into result, which must be a pointer
+func (cm *ClientManager) WorkflowResultInto(ctx context.Context, workflowID string, result interface{}) error {
+	if cm.temporalClient == nil {
+		return fmt.Errorf("no Temporal client to get workflow %s", workflowID)
+	}
+	return cm.temporalClient.GetWorkflow(ctx, workflowID, "").Get(ctx, result)
+}
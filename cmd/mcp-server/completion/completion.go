@@ -0,0 +1,266 @@
+package completion
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"bechols/temcp/cmd/mcp-server/clients"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+)
+
+const (
+	// maxValues is the most values a completion result may carry per the MCP spec
+	maxValues = 100
+	cacheTTL  = time.Minute
+)
+
+var (
+	permissions  = []string{"admin", "write", "read"}
+	accountRoles = []string{"owner", "admin", "developer", "finance_admin", "read"}
+)
+
+type (
+	// candidate is a completion value and the strings a partial argument is matched against
+	candidate struct {
+		value   string
+		matches []string
+	}
+
+	cacheEntry struct {
+		candidates []candidate
+		fetchedAt  time.Time
+	}
+
+	// Provider completes prompt and resource template arguments from cached Cloud API list calls.
+	// It dispatches on the argument name, so any prompt or template using the same argument names
+	// gets the same suggestions.
+	Provider struct {
+		clientManager *clients.ClientManager
+
+		mu    sync.Mutex
+		cache map[string]*cacheEntry
+	}
+)
+
+func NewProvider(clientManager *clients.ClientManager) *Provider {
+	return &Provider{
+		clientManager: clientManager,
+		cache:         make(map[string]*cacheEntry),
+	}
+}
+
+// CompletePromptArgument implements server.PromptCompletionProvider
+func (p *Provider) CompletePromptArgument(ctx context.Context, _ string, argument mcp.CompleteArgument, _ mcp.CompleteContext) (*mcp.Completion, error) {
+	return p.complete(ctx, argument)
+}
+
+// CompleteResourceArgument implements server.ResourceCompletionProvider
+func (p *Provider) CompleteResourceArgument(ctx context.Context, _ string, argument mcp.CompleteArgument, _ mcp.CompleteContext) (*mcp.Completion, error) {
+	return p.complete(ctx, argument)
+}
+
+func (p *Provider) complete(ctx context.Context, argument mcp.CompleteArgument) (*mcp.Completion, error) {
+	var (
+		candidates []candidate
+		err        error
+	)
+	switch argument.Name {
+	case "namespace":
+		candidates, err = p.cached(ctx, "namespaces", p.namespaces)
+	case "user_id":
+		candidates, err = p.cached(ctx, "user_ids", p.userIDs)
+	case "user", "email":
+		candidates, err = p.cached(ctx, "user_emails", p.userEmails)
+	case "service_account_id":
+		candidates, err = p.cached(ctx, "service_account_ids", p.serviceAccountIDs)
+	case "service_account":
+		candidates, err = p.cached(ctx, "service_account_names", p.serviceAccountNames)
+	case "region", "region_id":
+		candidates, err = p.cached(ctx, "regions", p.regions)
+	case "permission":
+		candidates = staticCandidates(permissions)
+	case "role", "account_role":
+		candidates = staticCandidates(accountRoles)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return filter(candidates, argument.Value), nil
+}
+
+func (p *Provider) cached(ctx context.Context, key string, fetch func(context.Context) ([]candidate, error)) ([]candidate, error) {
+	p.mu.Lock()
+	entry, ok := p.cache[key]
+	p.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < cacheTTL {
+		return entry.candidates, nil
+	}
+
+	candidates, err := fetch(ctx)
+	if err != nil {
+		if ok {
+			// serve stale suggestions rather than none
+			return entry.candidates, nil
+		}
+		return nil, err
+	}
+	p.mu.Lock()
+	p.cache[key] = &cacheEntry{candidates: candidates, fetchedAt: time.Now()}
+	p.mu.Unlock()
+	return candidates, nil
+}
+
+func (p *Provider) namespaces(ctx context.Context) ([]candidate, error) {
+	var (
+		out       []candidate
+		pageToken string
+	)
+	for {
+		resp, err := p.clientManager.GetCloudClient().CloudService().GetNamespaces(ctx, &cloudservice.GetNamespacesRequest{
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range resp.Namespaces {
+			out = append(out, candidate{value: ns.Namespace, matches: []string{ns.Namespace, ns.GetSpec().GetName()}})
+		}
+		if resp.NextPageToken == "" {
+			return out, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+func (p *Provider) users(ctx context.Context, value func(id, email string) string) ([]candidate, error) {
+	var (
+		out       []candidate
+		pageToken string
+	)
+	for {
+		resp, err := p.clientManager.GetCloudClient().CloudService().GetUsers(ctx, &cloudservice.GetUsersRequest{
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range resp.Users {
+			email := u.GetSpec().GetEmail()
+			out = append(out, candidate{value: value(u.Id, email), matches: []string{email, u.Id}})
+		}
+		if resp.NextPageToken == "" {
+			return out, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// userIDs suggests user IDs, matched by email so callers can type what they know
+func (p *Provider) userIDs(ctx context.Context) ([]candidate, error) {
+	return p.users(ctx, func(id, _ string) string { return id })
+}
+
+func (p *Provider) userEmails(ctx context.Context) ([]candidate, error) {
+	return p.users(ctx, func(_, email string) string { return email })
+}
+
+func (p *Provider) serviceAccounts(ctx context.Context, value func(id, name string) string) ([]candidate, error) {
+	var (
+		out       []candidate
+		pageToken string
+	)
+	for {
+		resp, err := p.clientManager.GetCloudClient().CloudService().GetServiceAccounts(ctx, &cloudservice.GetServiceAccountsRequest{
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, sa := range resp.ServiceAccount {
+			name := sa.GetSpec().GetName()
+			out = append(out, candidate{value: value(sa.Id, name), matches: []string{name, sa.Id}})
+		}
+		if resp.NextPageToken == "" {
+			return out, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// serviceAccountIDs suggests service account IDs, matched by name
+func (p *Provider) serviceAccountIDs(ctx context.Context) ([]candidate, error) {
+	return p.serviceAccounts(ctx, func(id, _ string) string { return id })
+}
+
+func (p *Provider) serviceAccountNames(ctx context.Context) ([]candidate, error) {
+	return p.serviceAccounts(ctx, func(_, name string) string { return name })
+}
+
+func (p *Provider) regions(ctx context.Context) ([]candidate, error) {
+	resp, err := p.clientManager.GetCloudClient().CloudService().GetRegions(ctx, &cloudservice.GetRegionsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]candidate, 0, len(resp.Regions))
+	for _, r := range resp.Regions {
+		out = append(out, candidate{value: r.Id, matches: []string{r.Id, r.CloudProviderRegion, r.Location}})
+	}
+	return out, nil
+}
+
+func staticCandidates(values []string) []candidate {
+	out := make([]candidate, 0, len(values))
+	for _, v := range values {
+		out = append(out, candidate{value: v, matches: []string{v}})
+	}
+	return out
+}
+
+// filter returns the candidates matching the partial value, prefix matches first
+func filter(candidates []candidate, partial string) *mcp.Completion {
+	partial = strings.ToLower(partial)
+	var prefixed, contained []string
+	seen := make(map[string]bool)
+	for _, c := range candidates {
+		if c.value == "" || seen[c.value] {
+			continue
+		}
+		rank := -1
+		for _, m := range c.matches {
+			m = strings.ToLower(m)
+			if strings.HasPrefix(m, partial) {
+				rank = 0
+				break
+			}
+			if strings.Contains(m, partial) {
+				rank = 1
+			}
+		}
+		switch rank {
+		case 0:
+			prefixed = append(prefixed, c.value)
+		case 1:
+			contained = append(contained, c.value)
+		default:
+			continue
+		}
+		seen[c.value] = true
+	}
+	sort.Strings(prefixed)
+	sort.Strings(contained)
+	values := append(prefixed, contained...)
+
+	completion := &mcp.Completion{Values: values, Total: len(values)}
+	if len(values) > maxValues {
+		completion.Values = values[:maxValues]
+		completion.HasMore = true
+	}
+	if completion.Values == nil {
+		completion.Values = []string{}
+	}
+	return completion
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+
+	"bechols/temcp/internal/telemetry"
 )
 
 // Config holds the configuration for the MCP server
@@ -19,6 +21,13 @@ type Config struct {
 	// MCP server configuration
 	ServerName    string
 	ServerVersion string
+
+	// Logging configuration
+	LogLevel string
+
+	// Tracing configuration
+	TracesExporter string
+	TracesFile     string
 }
 
 // LoadFromEnv loads configuration from environment variables
@@ -31,6 +40,9 @@ func LoadFromEnv() (*Config, error) {
 		NamespaceTLSKey:  os.Getenv("TEMPORAL_CLOUD_NAMESPACE_TLS_KEY"),
 		ServerName:       getEnvOrDefault("MCP_SERVER_NAME", "temporal-cloud-mcp-server"),
 		ServerVersion:    getEnvOrDefault("MCP_SERVER_VERSION", "1.0.0"),
+		LogLevel:         getEnvOrDefault("TEMCP_LOG_LEVEL", "info"),
+		TracesExporter:   getEnvOrDefault("OTEL_TRACES_EXPORTER", "none"),
+		TracesFile:       getEnvOrDefault("TEMCP_TRACES_FILE", telemetry.DefaultFilePath),
 	}
 
 	// Validate required configuration
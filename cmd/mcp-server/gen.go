@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"bechols/temcp/internal/certgen"
+)
+
+const genUsage = `Usage: mcp-server gen <ca|leaf> [flags]
+
+Generates mTLS certificates locally, without calling Temporal Cloud.
+
+  mcp-server gen ca -org example -validity 1y -ca-cert ca.pem -ca-key ca.key
+  mcp-server gen leaf -org example -validity 30d -ca-cert ca.pem -ca-key ca.key -cert client.pem -key client.key
+
+Point TEMPORAL_CLOUD_NAMESPACE_TLS_CERT and TEMPORAL_CLOUD_NAMESPACE_TLS_KEY at the client certificate
+and key, and add the CA to the namespace with the temporal_generate_mtls_certificates or
+temporal_start_namespace_ca_rotation tools.
+`
+
+// runGen runs the gen subcommand and returns the exit code
+func runGen(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || (args[0] != "ca" && args[0] != "leaf") {
+		fmt.Fprint(stderr, genUsage)
+		return 2
+	}
+	kind := args[0]
+
+	flags := flag.NewFlagSet("gen "+kind, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	org := flags.String("org", "", "Organization of the certificate subject (required)")
+	commonName := flags.String("common-name", "", "Common name of the certificate subject (default the organization)")
+	validity := flags.String("validity", "", "How long the certificate is valid, like 365d, 1y or 720h (default 1y for ca, 30d for leaf)")
+	keyAlgorithm := flags.String("key-algorithm", certgen.DefaultKeyAlgorithm, "Key algorithm, one of "+strings.Join(certgen.KeyAlgorithms, ", "))
+	caCert := flags.String("ca-cert", certgen.CACertFile, "CA certificate file, written for ca and read for leaf")
+	caKey := flags.String("ca-key", certgen.CAKeyFile, "CA private key file, written for ca and read for leaf")
+	cert := flags.String("cert", certgen.ClientCertFile, "Client certificate file to write (leaf only)")
+	key := flags.String("key", certgen.ClientKeyFile, "Client private key file to write (leaf only)")
+	overwrite := flags.Bool("overwrite", false, "Replace existing files")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	opts := certgen.Options{Organization: *org, CommonName: *commonName, KeyAlgorithm: *keyAlgorithm}
+	if *validity != "" {
+		d, err := certgen.ParseValidity(*validity)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return 1
+		}
+		opts.Validity = d
+	}
+
+	var (
+		pair              *certgen.Pair
+		certPath, keyPath string
+		err               error
+	)
+	if kind == "ca" {
+		certPath, keyPath = *caCert, *caKey
+		pair, err = certgen.GenerateCA(opts)
+	} else {
+		certPath, keyPath = *cert, *key
+		var ca *certgen.Pair
+		if ca, err = certgen.LoadPair(*caCert, *caKey); err == nil {
+			pair, err = certgen.GenerateLeaf(ca, opts)
+		}
+	}
+	if err == nil {
+		err = certgen.Write(certPath, keyPath, pair, *overwrite)
+	}
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && kind == "leaf" {
+			err = fmt.Errorf("%w (generate a CA first with mcp-server gen ca)", err)
+		}
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "certificate: %s\nprivate key: %s\nfingerprint: %s\nexpires:     %s\n",
+		certPath, keyPath, pair.Fingerprint(), pair.Cert.NotAfter.Format(time.RFC3339))
+	return 0
+}
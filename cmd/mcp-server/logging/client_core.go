@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap/zapcore"
+)
+
+const loggerName = "temcp"
+
+// mcpLevels orders the MCP logging levels by severity
+var mcpLevels = map[mcp.LoggingLevel]int{
+	mcp.LoggingLevelDebug:     0,
+	mcp.LoggingLevelInfo:      1,
+	mcp.LoggingLevelNotice:    2,
+	mcp.LoggingLevelWarning:   3,
+	mcp.LoggingLevelError:     4,
+	mcp.LoggingLevelCritical:  5,
+	mcp.LoggingLevelAlert:     6,
+	mcp.LoggingLevelEmergency: 7,
+}
+
+// clientCore forwards log entries to an MCP client session as notifications/message,
+// honouring the minimum level the client set with logging/setLevel.
+type clientCore struct {
+	session server.SessionWithLogging
+	fields  []zapcore.Field
+}
+
+func newClientCore(session server.SessionWithLogging, fields []zapcore.Field) zapcore.Core {
+	return &clientCore{session: session, fields: fields}
+}
+
+func (c *clientCore) Enabled(level zapcore.Level) bool {
+	return mcpLevels[toMCPLevel(level)] >= mcpLevels[c.session.GetLogLevel()]
+}
+
+func (c *clientCore) With(fields []zapcore.Field) zapcore.Core {
+	return &clientCore{
+		session: c.session,
+		fields:  append(append([]zapcore.Field{}, c.fields...), fields...),
+	}
+}
+
+func (c *clientCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *clientCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if !c.session.Initialized() {
+		return nil
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	data := map[string]any{"message": entry.Message}
+	for k, v := range enc.Fields {
+		data[k] = v
+	}
+
+	notification := mcp.NewLoggingMessageNotification(toMCPLevel(entry.Level), loggerName, data)
+	select {
+	case c.session.NotificationChannel() <- mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: notification.Method,
+			Params: mcp.NotificationParams{
+				AdditionalFields: map[string]any{
+					"level":  notification.Params.Level,
+					"logger": notification.Params.Logger,
+					"data":   notification.Params.Data,
+				},
+			},
+		},
+	}:
+	default:
+		// never block a tool call on a slow client, the entry is still in the stderr log
+	}
+	return nil
+}
+
+func (c *clientCore) Sync() error {
+	return nil
+}
+
+func toMCPLevel(level zapcore.Level) mcp.LoggingLevel {
+	switch level {
+	case zapcore.DebugLevel:
+		return mcp.LoggingLevelDebug
+	case zapcore.InfoLevel:
+		return mcp.LoggingLevelInfo
+	case zapcore.WarnLevel:
+		return mcp.LoggingLevelWarning
+	case zapcore.ErrorLevel:
+		return mcp.LoggingLevelError
+	case zapcore.DPanicLevel:
+		return mcp.LoggingLevelCritical
+	case zapcore.PanicLevel:
+		return mcp.LoggingLevelAlert
+	default:
+		return mcp.LoggingLevelEmergency
+	}
+}
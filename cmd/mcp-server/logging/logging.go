@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"context"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type loggerKey struct{}
+
+// New creates the server logger at info level, the returned level can be changed once configuration is loaded.
+// Logs are written to stderr as JSON since stdout carries the MCP protocol.
+func New() (*zap.Logger, zap.AtomicLevel) {
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(encoderConfig),
+		zapcore.Lock(os.Stderr),
+		level,
+	)
+	return zap.New(core, zap.AddCaller()), level
+}
+
+// WithLogger returns a context carrying the logger
+func WithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the request-scoped logger, or a no-op logger if there is none
+func FromContext(ctx context.Context) *zap.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+		return logger
+	}
+	return zap.NewNop()
+}
+
+// ToolMiddleware attaches a request-scoped logger to every tool call. The logger carries the tool name,
+// a call ID and the namespace argument if present, and forwards entries at or above the level the client
+// chose with logging/setLevel to the client as notifications/message.
+func ToolMiddleware(base *zap.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			fields := []zap.Field{
+				zap.String("tool", request.Params.Name),
+				zap.String("call_id", uuid.NewString()),
+			}
+			if ns, ok := request.GetArguments()["namespace"].(string); ok && ns != "" {
+				fields = append(fields, zap.String("namespace", ns))
+			}
+			logger := base.With(fields...)
+			if session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithLogging); ok {
+				logger = logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+					return zapcore.NewTee(core, newClientCore(session, fields))
+				}))
+			}
+
+			logger.Debug("tool call started")
+			result, err := next(WithLogger(ctx, logger), request)
+			switch {
+			case err != nil:
+				logger.Error("tool call failed", zap.Error(err))
+			case result != nil && result.IsError:
+				logger.Warn("tool call returned an error", zap.String("error", resultText(result)))
+			default:
+				logger.Debug("tool call completed")
+			}
+			return result, err
+		}
+	}
+}
+
+func resultText(result *mcp.CallToolResult) string {
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
@@ -1,36 +1,78 @@
 package main
 
 import (
-	"log"
+	"context"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"bechols/temcp/cmd/mcp-server/clients"
+	"bechols/temcp/cmd/mcp-server/completion"
 	"bechols/temcp/cmd/mcp-server/config"
+	"bechols/temcp/cmd/mcp-server/logging"
 	"bechols/temcp/cmd/mcp-server/tools"
+	"bechols/temcp/internal/telemetry"
 	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
 )
 
 func main() {
-	log.Println("Temporal Cloud MCP Server starting...")
+	// The gen subcommand runs locally and needs no configuration
+	if len(os.Args) > 1 && os.Args[1] == "gen" {
+		os.Exit(runGen(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
+	logger, logLevel := logging.New()
+	defer logger.Sync()
+	logger.Info("Temporal Cloud MCP Server starting...")
 
 	// Load configuration from environment
 	cfg, err := config.LoadFromEnv()
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		logger.Fatal("Failed to load configuration", zap.Error(err))
+	}
+	if err := logLevel.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+		logger.Fatal("Invalid log level", zap.String("level", cfg.LogLevel), zap.Error(err))
 	}
 
+	// Set up tracing before any clients are created so they pick up the tracer provider.
+	// Spans never go to stdout, which carries the MCP protocol.
+	shutdownTracing, err := telemetry.Setup(context.Background(), telemetry.Options{
+		Exporter:      cfg.TracesExporter,
+		FilePath:      cfg.TracesFile,
+		ConsoleWriter: os.Stderr,
+		ServiceName:   cfg.ServerName,
+	})
+	if err != nil {
+		logger.Fatal("Failed to set up tracing", zap.Error(err))
+	}
+	defer flushTraces(logger, shutdownTracing)
+
+	clientManager, err := clients.NewClientManager(cfg, logger)
+	if err != nil {
+		logger.Fatal("Failed to create clients", zap.Error(err))
+	}
+	completionProvider := completion.NewProvider(clientManager)
+
 	// Create MCP server
 	mcpServer := server.NewMCPServer(
 		cfg.ServerName,
 		cfg.ServerVersion,
 		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(false, false),
+		server.WithPromptCapabilities(false),
+		server.WithCompletions(),
+		server.WithPromptCompletionProvider(completionProvider),
+		server.WithResourceCompletionProvider(completionProvider),
 		server.WithLogging(),
+		server.WithToolHandlerMiddleware(tools.TracingMiddleware),
+		server.WithToolHandlerMiddleware(logging.ToolMiddleware(logger)),
 	)
 
 	// Register all tool handlers
-	if err := tools.RegisterAllTools(mcpServer, cfg); err != nil {
-		log.Fatalf("Failed to register tools: %v", err)
+	if err := tools.RegisterAllTools(mcpServer, cfg, clientManager); err != nil {
+		logger.Fatal("Failed to register tools", zap.Error(err))
 	}
 
 	// Set up graceful shutdown
@@ -38,10 +80,23 @@ func main() {
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-c
-		log.Println("Shutting down...")
+		logger.Info("Shutting down...")
+		flushTraces(logger, shutdownTracing)
+		logger.Sync()
 		os.Exit(0)
 	}()
 
-	log.Printf("Starting MCP server '%s' v%s on stdio...", cfg.ServerName, cfg.ServerVersion)
+	logger.Info("Starting MCP server on stdio...",
+		zap.String("name", cfg.ServerName),
+		zap.String("version", cfg.ServerVersion),
+	)
 	server.ServeStdio(mcpServer)
 }
+
+func flushTraces(logger *zap.Logger, shutdown telemetry.ShutdownFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdown(ctx); err != nil {
+		logger.Warn("Failed to flush traces", zap.Error(err))
+	}
+}
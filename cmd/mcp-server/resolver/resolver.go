@@ -0,0 +1,317 @@
+package resolver
+
+import (
+	"context"
+	"strings"
+
+	"bechols/temcp/client/api"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+	"go.temporal.io/cloud-sdk/api/identity/v1"
+	"go.temporal.io/cloud-sdk/api/namespace/v1"
+	"go.temporal.io/cloud-sdk/api/nexus/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxListedMatches caps how many candidates an ambiguity error lists
+const maxListedMatches = 5
+
+// Resolver turns the identifiers people use (emails, names, short namespace names) into
+// the opaque IDs the Cloud API expects. Values that are already IDs pass through.
+//
+// Failures are returned as gRPC status errors, NotFound when nothing matches and
+// InvalidArgument when the value is ambiguous, so they translate like any other Cloud API error.
+type Resolver struct {
+	client *api.Client
+}
+
+func New(client *api.Client) *Resolver {
+	return &Resolver{client: client}
+}
+
+// UserID resolves a user ID or email to a user ID
+func (r *Resolver) UserID(ctx context.Context, ref string) (string, error) {
+	if !strings.Contains(ref, "@") {
+		return ref, nil
+	}
+	user, err := r.UserWithEmail(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	return user.Id, nil
+}
+
+// UserWithEmail returns the user with the email, the same lookup as the GetUserWithEmail workflow
+func (r *Resolver) UserWithEmail(ctx context.Context, email string) (*identity.User, error) {
+	var (
+		users     []*identity.User
+		pageToken string
+	)
+	for {
+		resp, err := r.client.CloudService().GetUsers(ctx, &cloudservice.GetUsersRequest{
+			Email:     email,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, resp.Users...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	switch len(users) {
+	case 0:
+		return nil, status.Errorf(codes.NotFound, "no user found with email %q", email)
+	case 1:
+		return users[0], nil
+	default:
+		ids := make([]string, 0, len(users))
+		for _, u := range users {
+			ids = append(ids, u.Id)
+		}
+		return nil, ambiguous("users", email, ids)
+	}
+}
+
+// ServiceAccountID resolves a service account ID or name to a service account ID
+func (r *Resolver) ServiceAccountID(ctx context.Context, ref string) (string, error) {
+	sa, err := r.ServiceAccount(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	return sa.Id, nil
+}
+
+// ServiceAccount returns the service account with the ID or name. IDs are looked up directly; only names
+// page through the account's service accounts.
+func (r *Resolver) ServiceAccount(ctx context.Context, ref string) (*identity.ServiceAccount, error) {
+	resp, err := r.client.CloudService().GetServiceAccount(ctx, &cloudservice.GetServiceAccountRequest{ServiceAccountId: ref})
+	if err == nil {
+		return resp.GetServiceAccount(), nil
+	}
+	if !notAnID(err) {
+		return nil, err
+	}
+	var (
+		byName    []*identity.ServiceAccount
+		pageToken string
+	)
+	for {
+		resp, err := r.client.CloudService().GetServiceAccounts(ctx, &cloudservice.GetServiceAccountsRequest{
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, sa := range resp.ServiceAccount {
+			if sa.GetSpec().GetName() == ref {
+				byName = append(byName, sa)
+			}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	switch len(byName) {
+	case 0:
+		return nil, status.Errorf(codes.NotFound, "no service account found with ID or name %q", ref)
+	case 1:
+		return byName[0], nil
+	default:
+		ids := make([]string, 0, len(byName))
+		for _, sa := range byName {
+			ids = append(ids, sa.Id)
+		}
+		return nil, ambiguous("service accounts", ref, ids)
+	}
+}
+
+// ApiKeyID resolves an API key ID or display name to an API key ID
+func (r *Resolver) ApiKeyID(ctx context.Context, ref string) (string, error) {
+	key, err := r.ApiKey(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	return key.Id, nil
+}
+
+// ApiKey returns the API key with the ID or display name. IDs are looked up directly; only display names
+// page through the account's API keys.
+func (r *Resolver) ApiKey(ctx context.Context, ref string) (*identity.ApiKey, error) {
+	resp, err := r.client.CloudService().GetApiKey(ctx, &cloudservice.GetApiKeyRequest{KeyId: ref})
+	if err == nil {
+		return resp.GetApiKey(), nil
+	}
+	if !notAnID(err) {
+		return nil, err
+	}
+	var (
+		byName    []*identity.ApiKey
+		pageToken string
+	)
+	for {
+		resp, err := r.client.CloudService().GetApiKeys(ctx, &cloudservice.GetApiKeysRequest{
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range resp.ApiKeys {
+			if key.GetSpec().GetDisplayName() == ref {
+				byName = append(byName, key)
+			}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	switch len(byName) {
+	case 0:
+		return nil, status.Errorf(codes.NotFound, "no API key found with ID or display name %q", ref)
+	case 1:
+		return byName[0], nil
+	default:
+		ids := make([]string, 0, len(byName))
+		for _, key := range byName {
+			ids = append(ids, key.Id)
+		}
+		return nil, ambiguous("API keys", ref, ids)
+	}
+}
+
+// UserGroup returns the user group with the ID or display name. IDs are looked up directly; only display
+// names page through the account's groups.
+func (r *Resolver) UserGroup(ctx context.Context, ref string) (*identity.UserGroup, error) {
+	resp, err := r.client.CloudService().GetUserGroup(ctx, &cloudservice.GetUserGroupRequest{GroupId: ref})
+	if err == nil {
+		return resp.GetGroup(), nil
+	}
+	if !notAnID(err) {
+		return nil, err
+	}
+	var (
+		byName    []*identity.UserGroup
+		pageToken string
+	)
+	for {
+		resp, err := r.client.CloudService().GetUserGroups(ctx, &cloudservice.GetUserGroupsRequest{
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range resp.Groups {
+			if group.GetSpec().GetDisplayName() == ref {
+				byName = append(byName, group)
+			}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	switch len(byName) {
+	case 0:
+		return nil, status.Errorf(codes.NotFound, "no user group found with ID or display name %q", ref)
+	case 1:
+		return byName[0], nil
+	default:
+		ids := make([]string, 0, len(byName))
+		for _, group := range byName {
+			ids = append(ids, group.Id)
+		}
+		return nil, ambiguous("user groups", ref, ids)
+	}
+}
+
+// Namespace resolves a full namespace ID (name.account) or a short namespace name to the full namespace ID
+func (r *Resolver) Namespace(ctx context.Context, ref string) (string, error) {
+	if strings.Contains(ref, ".") {
+		// namespace names can't contain dots, so this is already a full namespace ID
+		return ref, nil
+	}
+	var (
+		matches   []*namespace.Namespace
+		pageToken string
+	)
+	for {
+		resp, err := r.client.CloudService().GetNamespaces(ctx, &cloudservice.GetNamespacesRequest{
+			Name:      ref,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return "", err
+		}
+		for _, ns := range resp.Namespaces {
+			if ns.GetSpec().GetName() == ref {
+				matches = append(matches, ns)
+			}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	switch len(matches) {
+	case 0:
+		return "", status.Errorf(codes.NotFound, "no namespace found with name %q", ref)
+	case 1:
+		return matches[0].Namespace, nil
+	default:
+		ids := make([]string, 0, len(matches))
+		for _, ns := range matches {
+			ids = append(ids, ns.Namespace)
+		}
+		return "", ambiguous("namespaces", ref, ids)
+	}
+}
+
+// NexusEndpoint returns the Nexus endpoint with the ID or name. Endpoint names are unique in an account,
+// so a name never matches more than one endpoint.
+func (r *Resolver) NexusEndpoint(ctx context.Context, ref string) (*nexus.Endpoint, error) {
+	resp, err := r.client.CloudService().GetNexusEndpoints(ctx, &cloudservice.GetNexusEndpointsRequest{Name: ref})
+	if err != nil {
+		return nil, err
+	}
+	for _, endpoint := range resp.Endpoints {
+		if endpoint.GetSpec().GetName() == ref {
+			return endpoint, nil
+		}
+	}
+	getResp, err := r.client.CloudService().GetNexusEndpoint(ctx, &cloudservice.GetNexusEndpointRequest{EndpointId: ref})
+	if err != nil {
+		if notAnID(err) {
+			return nil, status.Errorf(codes.NotFound, "no Nexus endpoint found with ID or name %q", ref)
+		}
+		return nil, err
+	}
+	return getResp.Endpoint, nil
+}
+
+// Owner resolves an API key owner reference for the owner type: a user ID or email, or a service account ID or name
+func (r *Resolver) Owner(ctx context.Context, ownerType identity.OwnerType, ref string) (string, error) {
+	if ownerType == identity.OwnerType_OWNER_TYPE_SERVICE_ACCOUNT {
+		return r.ServiceAccountID(ctx, ref)
+	}
+	return r.UserID(ctx, ref)
+}
+
+// notAnID reports whether a lookup by ID failed because no resource has that ID, or the value isn't shaped
+// like an ID, so it may still be a name
+func notAnID(err error) bool {
+	code := status.Code(err)
+	return code == codes.NotFound || code == codes.InvalidArgument
+}
+
+func ambiguous(kind, ref string, ids []string) error {
+	listed := ids
+	if len(listed) > maxListedMatches {
+		listed = listed[:maxListedMatches]
+	}
+	return status.Errorf(codes.InvalidArgument, "%q matches %d %s (%s), pass the ID instead",
+		ref, len(ids), kind, strings.Join(listed, ", "))
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"bechols/temcp/cmd/mcp-server/clients"
 	"bechols/temcp/cmd/mcp-server/config"
+	"bechols/temcp/internal/access"
 	"bechols/temcp/workflows"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -20,12 +22,28 @@ func RegisterAccountAccessTools(mcpServer *server.MCPServer, cfg *config.Config,
 	mcpServer.AddTool(
 		mcp.NewTool("temporal_get_account_access",
 			mcp.WithDescription("Get a user's account-level access role (owner, admin, developer, finance_admin, read) - for users only, not service accounts"),
-			mcp.WithString("user_id", mcp.Description("User ID"), mcp.Required()),
+			mcp.WithString("user_id", mcp.Description("User ID or email"), mcp.Required()),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return handleGetAccountAccess(ctx, request, clientManager)
 		},
 	)
+
+	// Register temporal_export_access_matrix tool
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_export_access_matrix",
+			mcp.WithDescription("Export the effective permission of every user and service account on every namespace, flagging risky patterns such as many account admins and service accounts with admin on production namespaces"),
+			mcp.WithString("format", mcp.Description("Output format: json, csv or markdown (optional, default markdown)")),
+			mcp.WithString("output_file", mcp.Description("Local file to write the matrix to instead of returning it (optional)")),
+			mcp.WithBoolean("overwrite", mcp.Description("Replace output_file if it exists (optional, default: false)")),
+			mcp.WithNumber("max_account_admins", mcp.Description("Flag the account when more users than this are owners or admins (optional, default 3)")),
+			mcp.WithString("production_patterns", mcp.Description("Comma-separated substrings of namespace IDs that mark production namespaces (optional, default prod)")),
+			mcp.WithNumber("concurrency", mcp.Description("How many group member lists to read at once (optional, default 4)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleExportAccessMatrix(ctx, request, clientManager)
+		},
+	)
 }
 
 func handleGetAccountAccess(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
@@ -44,6 +62,11 @@ func handleGetAccountAccess(ctx context.Context, request mcp.CallToolRequest, cl
 		}, nil
 	}
 
+	userID, errResult := resolveUserID(ctx, clientManager, userID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	var accountAccess interface{}
 	var err error
 
@@ -52,15 +75,7 @@ func handleGetAccountAccess(ctx context.Context, request mcp.CallToolRequest, cl
 		// Use the GetAccountAccess workflow
 		accountAccess, err = clientManager.ExecuteWorkflow(ctx, workflows.GetAccountAccessWorkflowType, userID)
 		if err != nil {
-			return &mcp.CallToolResult{
-				IsError: true,
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: fmt.Sprintf("Error getting account access: %v", err),
-					},
-				},
-			}, nil
+			return cloudAPIErrorResult("Error getting account access", err, "temporal_get_user"), nil
 		}
 	} else {
 		// Call GetUser directly and extract account access
@@ -70,15 +85,7 @@ func handleGetAccountAccess(ctx context.Context, request mcp.CallToolRequest, cl
 		}
 		userResp, err := cloudClient.CloudService().GetUser(ctx, getUserReq)
 		if err != nil {
-			return &mcp.CallToolResult{
-				IsError: true,
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: fmt.Sprintf("Error getting user: %v", err),
-					},
-				},
-			}, nil
+			return cloudAPIErrorResult("Error getting user", err, "temporal_get_user"), nil
 		}
 
 		if userResp.User == nil || userResp.User.Spec == nil || userResp.User.Spec.Access == nil {
@@ -157,3 +164,93 @@ func getRoleDescription(role identity.AccountAccess_Role) string {
 		return "Unknown role"
 	}
 }
+
+// parseAccountRole parses an account role name such as "admin" or "finance_admin", ignoring case
+func parseAccountRole(role string) (identity.AccountAccess_Role, bool) {
+	switch strings.ToLower(role) {
+	case "owner":
+		return identity.AccountAccess_ROLE_OWNER, true
+	case "admin":
+		return identity.AccountAccess_ROLE_ADMIN, true
+	case "developer":
+		return identity.AccountAccess_ROLE_DEVELOPER, true
+	case "finance_admin", "financeadmin":
+		return identity.AccountAccess_ROLE_FINANCE_ADMIN, true
+	case "read":
+		return identity.AccountAccess_ROLE_READ, true
+	default:
+		return identity.AccountAccess_ROLE_UNSPECIFIED, false
+	}
+}
+
+func handleExportAccessMatrix(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+
+	format := strings.ToLower(optionalString(arguments, "format"))
+	if format == "" {
+		format = "markdown"
+	}
+	if format != "json" && format != "csv" && format != "markdown" {
+		return errorResult("Error: format must be json, csv or markdown"), nil
+	}
+	input := &workflows.AccountAccessMatrixInput{}
+	if n, ok := arguments["max_account_admins"].(float64); ok {
+		input.Options.MaxAccountAdmins = int(n)
+	}
+	for _, pattern := range strings.Split(optionalString(arguments, "production_patterns"), ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			input.Options.ProductionPatterns = append(input.Options.ProductionPatterns, pattern)
+		}
+	}
+	if n, ok := arguments["concurrency"].(float64); ok {
+		input.Concurrency = int(n)
+	}
+
+	// Use workflow if Temporal client is available, otherwise read the account directly
+	var (
+		matrix *access.Matrix
+		err    error
+	)
+	if clientManager.GetTemporalClient() != nil {
+		matrix = &access.Matrix{}
+		err = clientManager.ExecuteWorkflowInto(ctx, workflows.AccountAccessMatrixWorkflowType, input, matrix)
+	} else {
+		matrix, err = access.New(clientManager.GetCloudClient()).Matrix(ctx, input.Options, input.Concurrency)
+	}
+	if err != nil {
+		return cloudAPIErrorResult("Error building access matrix", err, ""), nil
+	}
+
+	var rendered string
+	switch format {
+	case "csv":
+		if rendered, err = matrix.CSV(); err != nil {
+			return errorResult("Error rendering access matrix: %v", err), nil
+		}
+	case "markdown":
+		rendered = matrix.Markdown()
+	default:
+		matrixJSON, err := json.MarshalIndent(matrix, "", "  ")
+		if err != nil {
+			return errorResult("Error serializing access matrix: %v", err), nil
+		}
+		rendered = string(matrixJSON)
+	}
+
+	outputFile := optionalString(arguments, "output_file")
+	if outputFile == "" {
+		return textResult(rendered), nil
+	}
+	// The matrix lists who can reach what, so the file is private to the user
+	overwrite, _ := arguments["overwrite"].(bool)
+	if err := writeOutputFile(outputFile, []byte(rendered), overwrite); err != nil {
+		return errorResult("Error writing access matrix to %s: %v", outputFile, err), nil
+	}
+	return jsonResult(map[string]interface{}{
+		"output_file": outputFile,
+		"format":      format,
+		"principals":  len(matrix.Rows),
+		"namespaces":  len(matrix.Namespaces),
+		"risks":       matrix.Risks,
+	}), nil
+}
@@ -0,0 +1,270 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"bechols/temcp/cmd/mcp-server/clients"
+	"bechols/temcp/cmd/mcp-server/config"
+	"bechols/temcp/cmd/mcp-server/logging"
+	"bechols/temcp/internal/cabundle"
+	"bechols/temcp/internal/preflight"
+	"bechols/temcp/workflows"
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.temporal.io/cloud-sdk/api/account/v1"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+	"go.uber.org/zap"
+)
+
+// RegisterAccountTools registers the account settings tools with the MCP server
+func RegisterAccountTools(mcpServer *server.MCPServer, cfg *config.Config, clientManager *clients.ClientManager) {
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_get_account",
+			mcp.WithDescription("Get the account's settings: whether the metrics endpoint is enabled, its URI and the CA certificates its clients authenticate with"),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetAccount(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_update_account_metrics",
+			mcp.WithDescription("Enable or disable the account's metrics endpoint, or replace the CA its clients authenticate with. Checks the CA first, then waits for the update. To swap CAs without locking clients out, use temporal_start_metrics_ca_rotation."),
+			mcp.WithBoolean("enabled", mcp.Description("Whether the metrics endpoint is enabled"), mcp.Required()),
+			mcp.WithString("accepted_client_ca", mcp.Description("CA bundle as PEM or base64-encoded PEM (required to enable the endpoint, optional if it is enabled already)")),
+			mcp.WithString("resource_version", mcp.Description("Resource version of the account (optional, defaults to the current version)")),
+			mcp.WithBoolean("validate_only", mcp.Description("Only run the checks, don't update the account, whether enabling or disabling (optional, default: false)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the update in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleUpdateAccountMetrics(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_start_metrics_ca_rotation",
+			mcp.WithDescription("Start rotating the metrics endpoint's client CA: append the new CA, wait for the overlap so clients can switch certificates, then remove the old CA. Returns right away with the rotation's workflow ID; follow it with temporal_get_metrics_ca_rotation."),
+			mcp.WithString("new_ca", mcp.Description("New CA bundle as PEM or base64-encoded PEM"), mcp.Required()),
+			mcp.WithString("remove_fingerprints", mcp.Description("Comma-separated SHA-256 fingerprints of the certificates to remove after the overlap (optional, default every certificate not in the new CA)")),
+			mcp.WithNumber("overlap_minutes", mcp.Description("How long both CAs are accepted (optional, default 60)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for each account update in seconds (optional, default 1800)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleStartMetricsCARotation(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_get_metrics_ca_rotation",
+			mcp.WithDescription("Get the phase and report of the latest metrics endpoint client CA rotation"),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetMetricsCARotation(ctx, request, clientManager)
+		},
+	)
+}
+
+// accountSummary is how the account tools present the account, with the CA bundle as parsed certificates
+type accountSummary struct {
+	ID               string                 `json:"id"`
+	State            string                 `json:"state"`
+	ResourceVersion  string                 `json:"resource_version"`
+	AsyncOperationID string                 `json:"async_operation_id,omitempty"`
+	MetricsEnabled   bool                   `json:"metrics_enabled"`
+	MetricsURI       string                 `json:"metrics_uri,omitempty"`
+	MetricsClientCA  []cabundle.Certificate `json:"metrics_client_ca,omitempty"`
+	Notes            []string               `json:"notes,omitempty"`
+}
+
+func summarizeAccount(a *account.Account) accountSummary {
+	summary := accountSummary{
+		ID:               a.GetId(),
+		State:            a.GetState().String(),
+		ResourceVersion:  a.GetResourceVersion(),
+		AsyncOperationID: a.GetAsyncOperationId(),
+		MetricsEnabled:   a.GetSpec().GetMetrics() != nil,
+		MetricsURI:       a.GetMetrics().GetUri(),
+	}
+	if bundle := a.GetSpec().GetMetrics().GetAcceptedClientCa(); len(bundle) > 0 {
+		certificates, err := cabundle.Parse(bundle)
+		if err != nil {
+			summary.Notes = append(summary.Notes, "The metrics client CA bundle can't be parsed: "+err.Error())
+		}
+		summary.MetricsClientCA = certificates
+	}
+	return summary
+}
+
+func getAccount(ctx context.Context, clientManager *clients.ClientManager) (*account.Account, error) {
+	resp, err := callCloudService(ctx, clientManager, workflows.GetAccountWorkflowType, &cloudservice.GetAccountRequest{},
+		clientManager.GetCloudClient().CloudService().GetAccount)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetAccount(), nil
+}
+
+func handleGetAccount(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	a, err := getAccount(ctx, clientManager)
+	if err != nil {
+		return cloudAPIErrorResult("Error getting account", err, ""), nil
+	}
+	return jsonResult(summarizeAccount(a)), nil
+}
+
+func handleUpdateAccountMetrics(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	enabled, ok := arguments["enabled"].(bool)
+	if !ok {
+		return errorResult("Error: enabled is required and must be a boolean"), nil
+	}
+	current, err := getAccount(ctx, clientManager)
+	if err != nil {
+		return cloudAPIErrorResult("Error getting account", err, ""), nil
+	}
+
+	// Disabling the endpoint has nothing to check, so its report is always valid
+	spec := &account.AccountSpec{}
+	report := &preflight.Report{Valid: true, Issues: []preflight.Issue{}}
+	if enabled {
+		bundle := current.GetSpec().GetMetrics().GetAcceptedClientCa()
+		if value := optionalString(arguments, "accepted_client_ca"); value != "" {
+			if bundle, err = cabundle.Decode(value); err != nil {
+				return errorResult("Error: accepted_client_ca: %v", err), nil
+			}
+		}
+		report = preflight.CheckCABundle("accepted_client_ca", bundle, time.Now())
+		spec.Metrics = &account.MetricsSpec{AcceptedClientCa: bundle}
+	}
+	validateOnly, _ := arguments["validate_only"].(bool)
+	if errResult := invalidReportResult(report, validateOnly); errResult != nil {
+		return errResult, nil
+	}
+	resourceVersion := optionalString(arguments, "resource_version")
+	if resourceVersion == "" {
+		resourceVersion = current.GetResourceVersion()
+	}
+
+	updateReq := &cloudservice.UpdateAccountRequest{
+		Spec:             spec,
+		ResourceVersion:  resourceVersion,
+		AsyncOperationId: uuid.New().String(),
+	}
+	updateResp, err := callCloudService(ctx, clientManager, workflows.UpdateAccountWorkflowType, updateReq,
+		clientManager.GetCloudClient().CloudService().UpdateAccount)
+	if err != nil {
+		return cloudAPIErrorResult("Error updating account", err, "temporal_get_account"), nil
+	}
+	operation, err := waitForAsyncOperation(ctx, clientManager, updateResp.GetAsyncOperation().GetId(), asyncOperationTimeout(arguments))
+	if err != nil {
+		return cloudAPIErrorResult("Error waiting for account update", err, "temporal_get_account"), nil
+	}
+	updated, err := getAccount(ctx, clientManager)
+	if err != nil {
+		return cloudAPIErrorResult("Error getting account", err, "temporal_get_account"), nil
+	}
+	return jsonResult(map[string]interface{}{
+		"async_operation": operation,
+		"account":         summarizeAccount(updated),
+	}), nil
+}
+
+// metricsCARotationWorkflowID is the workflow ID of an account's rotation, so only one rotation runs at a time
+func metricsCARotationWorkflowID(accountID string) string {
+	return "metrics-ca-rotation-" + accountID
+}
+
+// metricsCARotationAccount checks that rotations can run and fetches the account
+func metricsCARotationAccount(ctx context.Context, clientManager *clients.ClientManager) (*account.Account, *mcp.CallToolResult) {
+	if clientManager.GetTemporalClient() == nil {
+		return nil, errorResult("Error: CA rotations run as Temporal workflows; configure a Temporal namespace for the MCP server's worker")
+	}
+	a, err := getAccount(ctx, clientManager)
+	if err != nil {
+		return nil, cloudAPIErrorResult("Error getting account", err, "")
+	}
+	return a, nil
+}
+
+func handleStartMetricsCARotation(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	a, errResult := metricsCARotationAccount(ctx, clientManager)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if a.GetSpec().GetMetrics() == nil {
+		return errorResult("The account's metrics endpoint isn't enabled; enable it with temporal_update_account_metrics"), nil
+	}
+	arguments := request.GetArguments()
+	value, errResult := requiredString(arguments, "new_ca")
+	if errResult != nil {
+		return errResult, nil
+	}
+	newCA, err := cabundle.Decode(value)
+	if err != nil {
+		return errorResult("Error: new_ca: %v", err), nil
+	}
+	if errResult := invalidReportResult(preflight.CheckCABundle("new_ca", newCA, time.Now()), false); errResult != nil {
+		return errResult, nil
+	}
+
+	in := &workflows.RotateMetricsClientCAInput{NewCA: newCA}
+	for _, fingerprint := range strings.Split(optionalString(arguments, "remove_fingerprints"), ",") {
+		if fingerprint = strings.TrimSpace(fingerprint); fingerprint != "" {
+			in.RemoveFingerprints = append(in.RemoveFingerprints, fingerprint)
+		}
+	}
+	if minutes, ok := arguments["overlap_minutes"].(float64); ok && minutes > 0 {
+		in.Overlap = time.Duration(minutes * float64(time.Minute))
+	}
+	if seconds, ok := arguments["timeout_seconds"].(float64); ok && seconds > 0 {
+		in.OperationTimeout = time.Duration(seconds) * time.Second
+	}
+
+	workflowID := metricsCARotationWorkflowID(a.GetId())
+	run, err := clientManager.StartWorkflow(ctx, workflowID, workflows.RotateMetricsClientCAWorkflowType, in)
+	if err != nil {
+		return startJobErrorResult("metrics CA rotation", workflowID, err, "temporal_get_metrics_ca_rotation"), nil
+	}
+	logging.FromContext(ctx).Info("started metrics CA rotation", zap.String("workflow_id", run.GetID()))
+	return jsonResult(map[string]interface{}{
+		"workflow_id": run.GetID(),
+		"run_id":      run.GetRunID(),
+		"current_ca":  summarizeAccount(a).MetricsClientCA,
+	}), nil
+}
+
+func handleGetMetricsCARotation(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	a, errResult := metricsCARotationAccount(ctx, clientManager)
+	if errResult != nil {
+		return errResult, nil
+	}
+	return caRotationResult(ctx, clientManager, metricsCARotationWorkflowID(a.GetId()))
+}
+
+// caRotationResult returns whether the rotation is running and its report. The report query also answers
+// for closed rotations, so failed rotations keep their report.
+func caRotationResult(ctx context.Context, clientManager *clients.ClientManager, workflowID string) (*mcp.CallToolResult, error) {
+	running, err := clientManager.WorkflowRunning(ctx, workflowID)
+	if err != nil {
+		return errorResult("Error getting CA rotation %s: %v", workflowID, err), nil
+	}
+	var report workflows.CARotationReport
+	if err := clientManager.QueryWorkflowInto(ctx, workflowID, workflows.CARotationReportQuery, &report); err != nil {
+		return errorResult("Error getting CA rotation report %s: %v", workflowID, err), nil
+	}
+	result := map[string]interface{}{
+		"workflow_id": workflowID,
+		"running":     running,
+		"report":      &report,
+	}
+	if !running {
+		var final workflows.CARotationReport
+		if err := clientManager.WorkflowResultInto(ctx, workflowID, &final); err != nil {
+			result["error"] = err.Error()
+		}
+	}
+	return jsonResult(result), nil
+}
@@ -4,16 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"strings"
 	"time"
 
 	"bechols/temcp/cmd/mcp-server/clients"
 	"bechols/temcp/cmd/mcp-server/config"
+	"bechols/temcp/cmd/mcp-server/logging"
+	"bechols/temcp/workflows"
 	"github.com/google/uuid"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	cloudservicev1 "go.temporal.io/cloud-sdk/api/cloudservice/v1"
 	identityv1 "go.temporal.io/cloud-sdk/api/identity/v1"
+	resourcev1 "go.temporal.io/cloud-sdk/api/resource/v1"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -24,7 +29,7 @@ func RegisterApiKeyTools(mcpServer *server.MCPServer, cfg *config.Config, client
 		mcp.NewTool("temporal_create_api_key",
 			mcp.WithDescription("Create a new Temporal Cloud API key"),
 			mcp.WithString("owner_type", mcp.Description("Type of owner (user, service-account)"), mcp.Required()),
-			mcp.WithString("owner_id", mcp.Description("ID of the owner"), mcp.Required()),
+			mcp.WithString("owner_id", mcp.Description("ID of the owner, or the user's email or service account's name"), mcp.Required()),
 			mcp.WithString("display_name", mcp.Description("Display name for the API key"), mcp.Required()),
 			mcp.WithString("expiry_time", mcp.Description("Expiry time in ISO 8601 format (e.g., 2024-12-31T23:59:59Z)"), mcp.Required()),
 			mcp.WithString("description", mcp.Description("Description for the API key (optional)")),
@@ -34,6 +39,85 @@ func RegisterApiKeyTools(mcpServer *server.MCPServer, cfg *config.Config, client
 			return handleCreateApiKey(ctx, request, clientManager)
 		},
 	)
+
+	// Register temporal_list_api_keys tool
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_list_api_keys",
+			mcp.WithDescription("List Temporal Cloud API keys with their owners and time until expiry"),
+			mcp.WithString("owner", mcp.Description("Only list keys of this owner: a user ID or email, or a service account ID or name (optional)")),
+			mcp.WithString("owner_type", mcp.Description("Type of owner (user, service-account) (optional, default: user)")),
+			mcp.WithString("state", mcp.Description("Only list keys in this state: active, disabled, expired or all (optional, default: all)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleListApiKeys(ctx, request, clientManager)
+		},
+	)
+
+	// Register temporal_get_api_key tool
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_get_api_key",
+			mcp.WithDescription("Get a Temporal Cloud API key by ID or display name"),
+			mcp.WithString("api_key", mcp.Description("API key ID or display name"), mcp.Required()),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetApiKey(ctx, request, clientManager)
+		},
+	)
+
+	// Register temporal_update_api_key tool
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_update_api_key",
+			mcp.WithDescription("Update an API key's display name, description or expiry time and wait for the update to complete"),
+			mcp.WithString("api_key", mcp.Description("API key ID or display name"), mcp.Required()),
+			mcp.WithString("display_name", mcp.Description("New display name (optional)")),
+			mcp.WithString("description", mcp.Description("New description (optional)")),
+			mcp.WithString("expiry_time", mcp.Description("New expiry time in ISO 8601 format (optional)")),
+			mcp.WithString("resource_version", mcp.Description("Resource version for optimistic concurrency (optional, default: current version)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the update in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleUpdateApiKey(ctx, request, clientManager)
+		},
+	)
+
+	// Register temporal_enable_api_key tool
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_enable_api_key",
+			mcp.WithDescription("Enable a disabled API key and wait for the update to complete"),
+			mcp.WithString("api_key", mcp.Description("API key ID or display name"), mcp.Required()),
+			mcp.WithString("resource_version", mcp.Description("Resource version for optimistic concurrency (optional, default: current version)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the update in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleSetApiKeyDisabled(ctx, request, clientManager, false)
+		},
+	)
+
+	// Register temporal_disable_api_key tool
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_disable_api_key",
+			mcp.WithDescription("Disable an API key so it can no longer authenticate, and wait for the update to complete"),
+			mcp.WithString("api_key", mcp.Description("API key ID or display name"), mcp.Required()),
+			mcp.WithString("resource_version", mcp.Description("Resource version for optimistic concurrency (optional, default: current version)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the update in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleSetApiKeyDisabled(ctx, request, clientManager, true)
+		},
+	)
+
+	// Register temporal_delete_api_key tool
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_delete_api_key",
+			mcp.WithDescription("Delete an API key and wait for the deletion to complete"),
+			mcp.WithString("api_key", mcp.Description("API key ID or display name"), mcp.Required()),
+			mcp.WithString("resource_version", mcp.Description("Resource version for optimistic concurrency (optional, default: current version)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the deletion in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleDeleteApiKey(ctx, request, clientManager)
+		},
+	)
 }
 
 func handleCreateApiKey(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
@@ -118,13 +202,8 @@ func handleCreateApiKey(ctx context.Context, request mcp.CallToolRequest, client
 	}
 
 	// Convert owner type to enum - using the constants the backend expects
-	var ownerTypeEnum identityv1.OwnerType
-	switch ownerType {
-	case "user":
-		ownerTypeEnum = identityv1.OwnerType_OWNER_TYPE_USER
-	case "service-account":
-		ownerTypeEnum = identityv1.OwnerType_OWNER_TYPE_SERVICE_ACCOUNT
-	default:
+	ownerTypeEnum, ok := parseOwnerType(ownerType)
+	if !ok {
 		return &mcp.CallToolResult{
 			IsError: true,
 			Content: []mcp.Content{
@@ -136,6 +215,27 @@ func handleCreateApiKey(ctx context.Context, request mcp.CallToolRequest, client
 		}, nil
 	}
 
+	ownerID, errResult := resolveOwnerID(ctx, clientManager, ownerTypeEnum, ownerID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	// Keys are identified by owner and display name, refuse to create a second key with the same display name
+	// so that a retried call doesn't leave a duplicate key behind
+	existing, err := listAllApiKeys(ctx, clientManager, &cloudservicev1.GetApiKeysRequest{
+		OwnerId:   ownerID,
+		OwnerType: ownerTypeEnum,
+	})
+	if err != nil {
+		return cloudAPIErrorResult("Error listing the owner's API keys", err, ""), nil
+	}
+	for _, key := range existing {
+		if key.GetSpec().GetDisplayName() == displayName {
+			return errorResult("Error: the owner already has an API key named %q (id %s). The token of an existing key can't be retrieved; "+
+				"use it, rotate it by deleting it first, or pick another display_name.", displayName, key.Id), nil
+		}
+	}
+
 	// Create the API key request
 	createReq := &cloudservicev1.CreateApiKeyRequest{
 		Spec: &identityv1.ApiKeySpec{
@@ -146,25 +246,20 @@ func handleCreateApiKey(ctx context.Context, request mcp.CallToolRequest, client
 			ExpiryTime:  timestamppb.New(expiryTime),
 			Disabled:    disabled,
 		},
-		AsyncOperationId: uuid.New().String(),
+		// Derived from the key's identity, so a retried call is the same operation to the Cloud API even if it
+		// races the duplicate check above
+		AsyncOperationId: workflows.ApiKeyCreateOperationID(ownerTypeEnum, ownerID, displayName),
 	}
 
-	// Debug: log the enum value being sent (to stderr, not stdout which corrupts JSON)
-	log.Printf("DEBUG: OwnerType enum value: %v (%d)", ownerTypeEnum, int32(ownerTypeEnum))
+	logging.FromContext(ctx).Debug("creating API key",
+		zap.String("owner_type", ownerTypeEnum.String()),
+		zap.String("owner_id", ownerID),
+	)
 
-	// Call the Cloud API directly (API keys don't typically have workflows)
-	cloudClient := clientManager.GetCloudClient()
-	resp, err := cloudClient.CloudService().CreateApiKey(ctx, createReq)
+	// Always call the API directly: the token would be stored in plaintext in a workflow's history
+	resp, err := clientManager.GetCloudClient().CloudService().CreateApiKey(ctx, createReq)
 	if err != nil {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error creating API key: %v", err),
-				},
-			},
-		}, nil
+		return cloudAPIErrorResult("Error creating API key", err, ""), nil
 	}
 
 	// Create result structure
@@ -205,3 +300,339 @@ func handleCreateApiKey(ctx context.Context, request mcp.CallToolRequest, client
 		},
 	}, nil
 }
+
+// apiKeySummary is how the API key tools present a key
+type apiKeySummary struct {
+	ID              string `json:"id"`
+	DisplayName     string `json:"display_name"`
+	Description     string `json:"description,omitempty"`
+	OwnerType       string `json:"owner_type"`
+	OwnerID         string `json:"owner_id"`
+	Owner           string `json:"owner,omitempty"`
+	State           string `json:"state"`
+	Disabled        bool   `json:"disabled"`
+	ExpiryTime      string `json:"expiry_time,omitempty"`
+	ExpiresIn       string `json:"expires_in,omitempty"`
+	CreatedTime     string `json:"created_time,omitempty"`
+	ResourceVersion string `json:"resource_version"`
+}
+
+func handleListApiKeys(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+
+	state := optionalString(arguments, "state")
+	switch state {
+	case "":
+		state = "all"
+	case "active", "disabled", "expired", "all":
+	default:
+		return errorResult("Error: state must be one of active, disabled, expired or all"), nil
+	}
+
+	listReq := &cloudservicev1.GetApiKeysRequest{}
+	if owner := optionalString(arguments, "owner"); owner != "" {
+		ownerType := optionalString(arguments, "owner_type")
+		if ownerType == "" {
+			ownerType = "user"
+		}
+		ownerTypeEnum, ok := parseOwnerType(ownerType)
+		if !ok {
+			return errorResult("Error: owner_type must be either 'user' or 'service-account'"), nil
+		}
+		ownerID, errResult := resolveOwnerID(ctx, clientManager, ownerTypeEnum, owner)
+		if errResult != nil {
+			return errResult, nil
+		}
+		listReq.OwnerId = ownerID
+		listReq.OwnerType = ownerTypeEnum
+	}
+
+	keys, err := listAllApiKeys(ctx, clientManager, listReq)
+	if err != nil {
+		return cloudAPIErrorResult("Error listing API keys", err, ""), nil
+	}
+
+	now := time.Now()
+	owners := newApiKeyOwnerNames(clientManager)
+	summaries := make([]apiKeySummary, 0, len(keys))
+	for _, key := range keys {
+		if state != "all" && apiKeyState(key, now) != state {
+			continue
+		}
+		summaries = append(summaries, summarizeApiKey(ctx, key, owners, now))
+	}
+
+	return jsonResult(map[string]interface{}{
+		"api_keys": summaries,
+		"count":    len(summaries),
+	}), nil
+}
+
+func handleGetApiKey(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	ref, errResult := requiredString(request.GetArguments(), "api_key")
+	if errResult != nil {
+		return errResult, nil
+	}
+	key, errResult := resolveApiKey(ctx, clientManager, ref)
+	if errResult != nil {
+		return errResult, nil
+	}
+	return jsonResult(summarizeApiKey(ctx, key, newApiKeyOwnerNames(clientManager), time.Now())), nil
+}
+
+func handleUpdateApiKey(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+
+	displayName := optionalString(arguments, "display_name")
+	description, setDescription := arguments["description"].(string)
+	expiryTimeStr := optionalString(arguments, "expiry_time")
+	if displayName == "" && !setDescription && expiryTimeStr == "" {
+		return errorResult("Error: at least one of display_name, description or expiry_time is required"), nil
+	}
+
+	var expiryTime time.Time
+	if expiryTimeStr != "" {
+		var err error
+		expiryTime, err = time.Parse(time.RFC3339, expiryTimeStr)
+		if err != nil {
+			return errorResult("Error parsing expiry_time: %v. Use ISO 8601 format like 2024-12-31T23:59:59Z", err), nil
+		}
+	}
+
+	return updateApiKey(ctx, request, clientManager, func(spec *identityv1.ApiKeySpec) {
+		if displayName != "" {
+			spec.DisplayName = displayName
+		}
+		if setDescription {
+			spec.Description = description
+		}
+		if expiryTimeStr != "" {
+			spec.ExpiryTime = timestamppb.New(expiryTime)
+		}
+	})
+}
+
+func handleSetApiKeyDisabled(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager, disabled bool) (*mcp.CallToolResult, error) {
+	return updateApiKey(ctx, request, clientManager, func(spec *identityv1.ApiKeySpec) {
+		spec.Disabled = disabled
+	})
+}
+
+// updateApiKey applies update to a copy of the key's spec, sends it with the caller's resource version
+// (or the current one), and waits for the async operation to finish
+func updateApiKey(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager, update func(spec *identityv1.ApiKeySpec)) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	ref, errResult := requiredString(arguments, "api_key")
+	if errResult != nil {
+		return errResult, nil
+	}
+	key, errResult := resolveApiKey(ctx, clientManager, ref)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	resourceVersion := optionalString(arguments, "resource_version")
+	if resourceVersion == "" {
+		resourceVersion = key.ResourceVersion
+	}
+	spec := proto.Clone(key.Spec).(*identityv1.ApiKeySpec)
+	update(spec)
+
+	cloudService := clientManager.GetCloudClient().CloudService()
+	resp, err := callCloudService(ctx, clientManager, workflows.UpdateApiKeyWorkflowType, &cloudservicev1.UpdateApiKeyRequest{
+		KeyId:            key.Id,
+		Spec:             spec,
+		ResourceVersion:  resourceVersion,
+		AsyncOperationId: uuid.New().String(),
+	}, cloudService.UpdateApiKey)
+	if err != nil {
+		return cloudAPIErrorResult("Error updating API key", err, "temporal_get_api_key"), nil
+	}
+
+	operation, err := waitForAsyncOperation(ctx, clientManager, resp.AsyncOperation.Id, asyncOperationTimeout(arguments))
+	if err != nil {
+		return cloudAPIErrorResult("Error waiting for API key update", err, ""), nil
+	}
+
+	updated, err := callCloudService(ctx, clientManager, workflows.GetApiKeyWorkflowType, &cloudservicev1.GetApiKeyRequest{KeyId: key.Id},
+		cloudService.GetApiKey)
+	if err != nil {
+		return cloudAPIErrorResult("Error getting updated API key", err, ""), nil
+	}
+	return jsonResult(map[string]interface{}{
+		"api_key":         summarizeApiKey(ctx, updated.ApiKey, newApiKeyOwnerNames(clientManager), time.Now()),
+		"async_operation": operation,
+	}), nil
+}
+
+func handleDeleteApiKey(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	ref, errResult := requiredString(arguments, "api_key")
+	if errResult != nil {
+		return errResult, nil
+	}
+	key, errResult := resolveApiKey(ctx, clientManager, ref)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	resourceVersion := optionalString(arguments, "resource_version")
+	if resourceVersion == "" {
+		resourceVersion = key.ResourceVersion
+	}
+
+	resp, err := callCloudService(ctx, clientManager, workflows.DeleteApiKeyWorkflowType, &cloudservicev1.DeleteApiKeyRequest{
+		KeyId:            key.Id,
+		ResourceVersion:  resourceVersion,
+		AsyncOperationId: uuid.New().String(),
+	}, clientManager.GetCloudClient().CloudService().DeleteApiKey)
+	if err != nil {
+		return cloudAPIErrorResult("Error deleting API key", err, "temporal_get_api_key"), nil
+	}
+
+	operation, err := waitForAsyncOperation(ctx, clientManager, resp.AsyncOperation.Id, asyncOperationTimeout(arguments))
+	if err != nil {
+		return cloudAPIErrorResult("Error waiting for API key deletion", err, ""), nil
+	}
+	return jsonResult(map[string]interface{}{
+		"deleted_api_key_id": key.Id,
+		"display_name":       key.GetSpec().GetDisplayName(),
+		"async_operation":    operation,
+	}), nil
+}
+
+// listAllApiKeys returns every API key matching the request's owner filter
+func listAllApiKeys(ctx context.Context, clientManager *clients.ClientManager, req *cloudservicev1.GetApiKeysRequest) ([]*identityv1.ApiKey, error) {
+	if clientManager.GetTemporalClient() != nil {
+		var keys []*identityv1.ApiKey
+		err := clientManager.ExecuteWorkflowInto(ctx, workflows.GetAllApiKeysWorkflowType, req, &keys)
+		return keys, err
+	}
+	var keys []*identityv1.ApiKey
+	listReq := proto.Clone(req).(*cloudservicev1.GetApiKeysRequest)
+	for {
+		resp, err := clientManager.GetCloudClient().CloudService().GetApiKeys(ctx, listReq)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, resp.ApiKeys...)
+		if resp.NextPageToken == "" {
+			return keys, nil
+		}
+		listReq.PageToken = resp.NextPageToken
+	}
+}
+
+func parseOwnerType(ownerType string) (identityv1.OwnerType, bool) {
+	switch ownerType {
+	case "user":
+		return identityv1.OwnerType_OWNER_TYPE_USER, true
+	case "service-account":
+		return identityv1.OwnerType_OWNER_TYPE_SERVICE_ACCOUNT, true
+	default:
+		return identityv1.OwnerType_OWNER_TYPE_UNSPECIFIED, false
+	}
+}
+
+// apiKeyState returns active, disabled or expired, or the resource state while the key is changing
+func apiKeyState(key *identityv1.ApiKey, now time.Time) string {
+	switch {
+	case key.GetSpec().GetDisabled():
+		return "disabled"
+	case key.State == resourcev1.ResourceState_RESOURCE_STATE_EXPIRED:
+		return "expired"
+	case key.GetSpec().GetExpiryTime() != nil && !key.Spec.ExpiryTime.AsTime().After(now):
+		return "expired"
+	case key.State == resourcev1.ResourceState_RESOURCE_STATE_ACTIVE:
+		return "active"
+	default:
+		return strings.ToLower(strings.TrimPrefix(key.State.String(), "RESOURCE_STATE_"))
+	}
+}
+
+func summarizeApiKey(ctx context.Context, key *identityv1.ApiKey, owners *apiKeyOwnerNames, now time.Time) apiKeySummary {
+	spec := key.GetSpec()
+	summary := apiKeySummary{
+		ID:              key.Id,
+		DisplayName:     spec.GetDisplayName(),
+		Description:     spec.GetDescription(),
+		OwnerID:         spec.GetOwnerId(),
+		Owner:           owners.name(ctx, spec.GetOwnerType(), spec.GetOwnerId()),
+		State:           apiKeyState(key, now),
+		Disabled:        spec.GetDisabled(),
+		ResourceVersion: key.ResourceVersion,
+	}
+	switch spec.GetOwnerType() {
+	case identityv1.OwnerType_OWNER_TYPE_SERVICE_ACCOUNT:
+		summary.OwnerType = "service-account"
+	default:
+		summary.OwnerType = "user"
+	}
+	if spec.GetExpiryTime() != nil {
+		expiry := spec.ExpiryTime.AsTime()
+		summary.ExpiryTime = expiry.Format(time.RFC3339)
+		summary.ExpiresIn = expiryCountdown(expiry, now)
+	}
+	if key.CreatedTime != nil {
+		summary.CreatedTime = key.CreatedTime.AsTime().Format(time.RFC3339)
+	}
+	return summary
+}
+
+// expiryCountdown describes the time until expiry, e.g. "in 12d 4h" or "expired 3d 2h ago"
+func expiryCountdown(expiry, now time.Time) string {
+	d := expiry.Sub(now)
+	if d <= 0 {
+		return "expired " + formatDays(-d) + " ago"
+	}
+	return "in " + formatDays(d)
+}
+
+func formatDays(d time.Duration) string {
+	days := int(d / (24 * time.Hour))
+	hours := int((d % (24 * time.Hour)) / time.Hour)
+	if days == 0 {
+		minutes := int((d % time.Hour) / time.Minute)
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dd %dh", days, hours)
+}
+
+// apiKeyOwnerNames looks up the email of user owners and the name of service account owners,
+// once per owner per tool call. Lookup failures leave the owner blank rather than failing the call.
+type apiKeyOwnerNames struct {
+	clientManager *clients.ClientManager
+	names         map[string]string
+}
+
+func newApiKeyOwnerNames(clientManager *clients.ClientManager) *apiKeyOwnerNames {
+	return &apiKeyOwnerNames{clientManager: clientManager, names: map[string]string{}}
+}
+
+func (o *apiKeyOwnerNames) name(ctx context.Context, ownerType identityv1.OwnerType, ownerID string) string {
+	if ownerID == "" {
+		return ""
+	}
+	if name, ok := o.names[ownerID]; ok {
+		return name
+	}
+	cloudService := o.clientManager.GetCloudClient().CloudService()
+	var name string
+	if ownerType == identityv1.OwnerType_OWNER_TYPE_SERVICE_ACCOUNT {
+		resp, err := cloudService.GetServiceAccount(ctx, &cloudservicev1.GetServiceAccountRequest{ServiceAccountId: ownerID})
+		if err != nil {
+			logging.FromContext(ctx).Debug("looking up API key owner failed", zap.String("owner_id", ownerID), zap.Error(err))
+		} else {
+			name = resp.GetServiceAccount().GetSpec().GetName()
+		}
+	} else {
+		resp, err := cloudService.GetUser(ctx, &cloudservicev1.GetUserRequest{UserId: ownerID})
+		if err != nil {
+			logging.FromContext(ctx).Debug("looking up API key owner failed", zap.String("owner_id", ownerID), zap.Error(err))
+		} else {
+			name = resp.GetUser().GetSpec().GetEmail()
+		}
+	}
+	o.names[ownerID] = name
+	return name
+}
@@ -0,0 +1,39 @@
+package tools
+
+import (
+	"context"
+	"errors"
+
+	"bechols/temcp/cmd/mcp-server/clients"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/grpc"
+)
+
+// callCloudService runs a Cloud API request through its workflow if a Temporal client is available,
+// otherwise calls the Cloud API directly, and returns the typed response either way
+func callCloudService[Req any, Resp any](
+	ctx context.Context,
+	clientManager *clients.ClientManager,
+	workflowType string,
+	req Req,
+	direct func(context.Context, Req, ...grpc.CallOption) (*Resp, error),
+) (*Resp, error) {
+	if clientManager.GetTemporalClient() != nil {
+		resp := new(Resp)
+		if err := clientManager.ExecuteWorkflowInto(ctx, workflowType, req, resp); err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+	return direct(ctx, req)
+}
+
+// startJobErrorResult returns the error of a job workflow that didn't start. When the job is already running,
+// it says so and points at followTool instead of reporting the run as started.
+func startJobErrorResult(job, workflowID string, err error, followTool string) *mcp.CallToolResult {
+	if errors.Is(err, clients.ErrWorkflowRunning) {
+		return errorResult("Error: a %s is already running as workflow %s; follow it with %s and start another once it finishes",
+			job, workflowID, followTool)
+	}
+	return errorResult("Error starting %s: %v", job, err)
+}
@@ -0,0 +1,163 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"bechols/temcp/cmd/mcp-server/clients"
+	"bechols/temcp/cmd/mcp-server/config"
+	"bechols/temcp/cmd/mcp-server/logging"
+	"bechols/temcp/internal/certgen"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// RegisterCertificateTools registers the local mTLS certificate tools with the MCP server
+func RegisterCertificateTools(mcpServer *server.MCPServer, cfg *config.Config, clientManager *clients.ClientManager) {
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_generate_mtls_certificates",
+			mcp.WithDescription("Generate a CA and a client certificate for namespace mTLS auth on the machine running the MCP server, or sign a client certificate with an existing CA. Private keys are written to files readable by the owner only and never returned. Optionally adds the CA to a namespace's accepted CAs through a CA rotation, keeping the CAs it already accepts unless remove_fingerprints names ones to drop."),
+			mcp.WithString("output_dir", mcp.Description("Directory to write ca.pem, ca.key, client.pem and client.key to"), mcp.Required()),
+			mcp.WithString("org", mcp.Description("Organization of the certificate subjects"), mcp.Required()),
+			mcp.WithString("common_name", mcp.Description("Common name of the client certificate subject (optional, default the organization)")),
+			mcp.WithString("ca_validity", mcp.Description("How long a new CA is valid, like 365d, 1y or 720h (optional, default 1y)")),
+			mcp.WithString("leaf_validity", mcp.Description("How long the client certificate is valid, at most as long as the CA (optional, default 30d)")),
+			mcp.WithString("key_algorithm", mcp.Description("Key algorithm: "+strings.Join(certgen.KeyAlgorithms, ", ")+" (optional, default "+certgen.DefaultKeyAlgorithm+")")),
+			mcp.WithString("ca_cert_file", mcp.Description("Existing CA certificate file to sign the client certificate with instead of generating a CA (optional, needs ca_key_file)")),
+			mcp.WithString("ca_key_file", mcp.Description("Private key file of the existing CA (optional, needs ca_cert_file)")),
+			mcp.WithBoolean("overwrite", mcp.Description("Replace existing files in output_dir (optional, default: false)")),
+			mcp.WithString("add_to_namespace", mcp.Description("Namespace ID (name.account) or name whose accepted CAs the CA is added to, as a RotateNamespaceCA workflow (optional)")),
+			mcp.WithString("remove_fingerprints", mcp.Description("Comma-separated SHA-256 fingerprints of accepted CAs to remove after the grace period (optional, default none: the CA is only appended)")),
+			mcp.WithNumber("grace_minutes", mcp.Description("How long both CAs are accepted before remove_fingerprints are removed, unless confirmed first (optional, default 60)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGenerateMTLSCertificates(ctx, request, clientManager)
+		},
+	)
+}
+
+// certificateSummary describes a generated certificate without its private key
+func certificateSummary(pair *certgen.Pair, certPath, keyPath string) map[string]interface{} {
+	summary := map[string]interface{}{
+		"certificate": certPath,
+		"fingerprint": pair.Fingerprint(),
+		"subject":     pair.Cert.Subject.String(),
+		"not_after":   pair.Cert.NotAfter,
+	}
+	if keyPath != "" {
+		summary["private_key"] = keyPath
+	}
+	return summary
+}
+
+func handleGenerateMTLSCertificates(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	outputDir, errResult := requiredString(arguments, "output_dir")
+	if errResult != nil {
+		return errResult, nil
+	}
+	org, errResult := requiredString(arguments, "org")
+	if errResult != nil {
+		return errResult, nil
+	}
+	caOpts := certgen.Options{Organization: org, KeyAlgorithm: optionalString(arguments, "key_algorithm")}
+	leafOpts := caOpts
+	leafOpts.CommonName = optionalString(arguments, "common_name")
+	for name, validity := range map[string]*time.Duration{"ca_validity": &caOpts.Validity, "leaf_validity": &leafOpts.Validity} {
+		if value := optionalString(arguments, name); value != "" {
+			d, err := certgen.ParseValidity(value)
+			if err != nil {
+				return errorResult("Error: %s: %v", name, err), nil
+			}
+			*validity = d
+		}
+	}
+	overwrite, _ := arguments["overwrite"].(bool)
+
+	// Resolve the namespace before writing anything, so a bad reference doesn't leave files behind
+	namespaceRef := optionalString(arguments, "add_to_namespace")
+	var namespaceID string
+	if namespaceRef != "" {
+		if clientManager.GetTemporalClient() == nil {
+			return errorResult("Error: CA rotations run as Temporal workflows; configure a Temporal namespace for the MCP server's worker"), nil
+		}
+		if namespaceID, errResult = resolveNamespace(ctx, clientManager, namespaceRef); errResult != nil {
+			return errResult, nil
+		}
+	}
+
+	result := map[string]interface{}{}
+	var ca *certgen.Pair
+	caCertFile, caKeyFile := optionalString(arguments, "ca_cert_file"), optionalString(arguments, "ca_key_file")
+	switch {
+	case caCertFile != "" && caKeyFile != "":
+		var err error
+		if ca, err = certgen.LoadPair(caCertFile, caKeyFile); err != nil {
+			return errorResult("Error loading CA: %v", err), nil
+		}
+		result["ca"] = certificateSummary(ca, caCertFile, "")
+	case caCertFile != "" || caKeyFile != "":
+		return errorResult("Error: ca_cert_file and ca_key_file must be given together"), nil
+	default:
+		var err error
+		if ca, err = certgen.GenerateCA(caOpts); err != nil {
+			return errorResult("Error generating CA: %v", err), nil
+		}
+	}
+
+	leaf, err := certgen.GenerateLeaf(ca, leafOpts)
+	if err != nil {
+		return errorResult("Error generating client certificate: %v", err), nil
+	}
+	if !overwrite {
+		// Check every file first so a clash doesn't leave a CA behind without its client certificate
+		names := []string{certgen.ClientCertFile, certgen.ClientKeyFile}
+		if caCertFile == "" {
+			names = append(names, certgen.CACertFile, certgen.CAKeyFile)
+		}
+		for _, name := range names {
+			if _, err := os.Stat(filepath.Join(outputDir, name)); err == nil {
+				return errorResult("Error: %s already exists, pass overwrite to replace it", filepath.Join(outputDir, name)), nil
+			}
+		}
+	}
+	if caCertFile == "" {
+		certPath, keyPath := filepath.Join(outputDir, certgen.CACertFile), filepath.Join(outputDir, certgen.CAKeyFile)
+		if err := certgen.Write(certPath, keyPath, ca, overwrite); err != nil {
+			return errorResult("Error writing CA: %v", err), nil
+		}
+		result["ca"] = certificateSummary(ca, certPath, keyPath)
+	}
+	certPath, keyPath := filepath.Join(outputDir, certgen.ClientCertFile), filepath.Join(outputDir, certgen.ClientKeyFile)
+	if err := certgen.Write(certPath, keyPath, leaf, overwrite); err != nil {
+		return errorResult("Error writing client certificate: %v", err), nil
+	}
+	result["client"] = certificateSummary(leaf, certPath, keyPath)
+	result["env"] = map[string]string{
+		"TEMPORAL_CLOUD_NAMESPACE_TLS_CERT": certPath,
+		"TEMPORAL_CLOUD_NAMESPACE_TLS_KEY":  keyPath,
+	}
+	logging.FromContext(ctx).Info("generated mTLS certificates",
+		zap.String("output_dir", outputDir), zap.String("ca_fingerprint", ca.Fingerprint()))
+
+	if namespaceID != "" {
+		// Generating a CA is no reason to drop the ones clients use today, so only remove what's named explicitly
+		in := namespaceCARotationInput(arguments, namespaceID, ca.CertPEM)
+		in.AppendOnly = len(in.RemoveFingerprints) == 0
+		rotation, errResult := startNamespaceCARotation(ctx, clientManager, in)
+		if errResult != nil {
+			// The files are written either way; report them along with why the rotation didn't start, as an error
+			// since the namespace doesn't accept the CA
+			result["rotation_error"] = mcp.GetTextFromContent(errResult.Content[0])
+			errResult = jsonResult(result)
+			errResult.IsError = true
+			return errResult, nil
+		}
+		result["rotation"] = rotation
+	}
+	return jsonResult(result), nil
+}
@@ -0,0 +1,37 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"bechols/temcp/internal/apierrors"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// cloudAPIErrorResult renders a failed Cloud API call as a structured tool error with a category,
+// whether a retry can succeed, and what to do about it. refetchTool is the tool that re-reads the
+// affected resource, and is suggested when the resource version is stale.
+func cloudAPIErrorResult(action string, err error, refetchTool string) *mcp.CallToolResult {
+	translated := apierrors.Translate(err, refetchTool)
+	result := struct {
+		Summary string `json:"error"`
+		*apierrors.Error
+	}{
+		Summary: fmt.Sprintf("%s: %s", action, translated.Message),
+		Error:   translated,
+	}
+
+	text, marshalErr := json.MarshalIndent(result, "", "  ")
+	if marshalErr != nil {
+		text = []byte(fmt.Sprintf("%s: %v", action, err))
+	}
+	return &mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: string(text),
+			},
+		},
+	}
+}
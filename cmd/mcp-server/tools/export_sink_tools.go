@@ -0,0 +1,384 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"bechols/temcp/cmd/mcp-server/clients"
+	"bechols/temcp/cmd/mcp-server/config"
+	"bechols/temcp/internal/apierrors"
+	"bechols/temcp/internal/preflight"
+	"bechols/temcp/workflows"
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+	"go.temporal.io/cloud-sdk/api/namespace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const exportSinkSpecDescription = "Export sink spec with proto field names: name, enabled, and either s3 {role_name, bucket_name, region, aws_account_id, kms_arn} or gcs {sa_id, bucket_name, gcp_project_id, region}"
+
+// RegisterExportSinkTools registers the namespace export sink tools with the MCP server
+func RegisterExportSinkTools(mcpServer *server.MCPServer, cfg *config.Config, clientManager *clients.ClientManager) {
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_list_export_sinks",
+			mcp.WithDescription("List the export sinks of a namespace with their health"),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleListExportSinks(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_get_export_sink",
+			mcp.WithDescription("Get an export sink of a namespace, including its health, last error and latest export time"),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+			mcp.WithString("name", mcp.Description("Export sink name"), mcp.Required()),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetExportSink(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_validate_export_sink",
+			mcp.WithDescription("Check an export sink spec and have Temporal Cloud write a test file to the bucket, reporting missing fields and permission problems with what to fix. Pass spec to check a new spec, or name to check an existing sink."),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+			mcp.WithObject("spec", mcp.Description(exportSinkSpecDescription+" (optional if name is given)")),
+			mcp.WithString("name", mcp.Description("Name of an existing export sink to validate (optional if spec is given)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleValidateExportSink(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_create_export_sink",
+			mcp.WithDescription("Create an export sink so a namespace's closed workflow histories are exported to S3 or GCS. Validates the spec first, then waits for the sink to be created."),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+			mcp.WithObject("spec", mcp.Description(exportSinkSpecDescription), mcp.Required()),
+			mcp.WithBoolean("skip_validation", mcp.Description("Create the sink without the Cloud API's test write (optional, default: false)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the sink to be created in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleCreateExportSink(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_update_export_sink",
+			mcp.WithDescription("Replace the spec of a namespace's export sink, e.g. to enable or disable it or point it at another bucket. Validates the spec first, then waits for the update."),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+			mcp.WithObject("spec", mcp.Description(exportSinkSpecDescription+"; name selects the sink"), mcp.Required()),
+			mcp.WithString("resource_version", mcp.Description("Resource version of the sink (optional, defaults to the current version)")),
+			mcp.WithBoolean("skip_validation", mcp.Description("Update the sink without the Cloud API's test write (optional, default: false)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the update in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleUpdateExportSink(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_delete_export_sink",
+			mcp.WithDescription("Delete an export sink of a namespace. Files already exported stay in the bucket."),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+			mcp.WithString("name", mcp.Description("Export sink name"), mcp.Required()),
+			mcp.WithString("resource_version", mcp.Description("Resource version of the sink (optional, defaults to the current version)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the sink to be deleted in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleDeleteExportSink(ctx, request, clientManager)
+		},
+	)
+}
+
+// exportSinkNamespace resolves the namespace argument
+func exportSinkNamespace(ctx context.Context, clientManager *clients.ClientManager, arguments map[string]interface{}) (string, *mcp.CallToolResult) {
+	namespaceID, errResult := requiredString(arguments, "namespace")
+	if errResult != nil {
+		return "", errResult
+	}
+	return resolveNamespace(ctx, clientManager, namespaceID)
+}
+
+// exportSinkSpec decodes the spec argument, rejecting unknown fields
+func exportSinkSpec(arguments map[string]interface{}) (*namespace.ExportSinkSpec, *mcp.CallToolResult) {
+	raw, ok := arguments["spec"].(map[string]interface{})
+	if !ok {
+		return nil, errorResult("Error: spec is required and must be an object")
+	}
+	specJSON, err := json.Marshal(raw)
+	if err != nil {
+		return nil, errorResult("Error: invalid spec: %v", err)
+	}
+	spec := &namespace.ExportSinkSpec{}
+	if err := protojson.Unmarshal(specJSON, spec); err != nil {
+		return nil, errorResult("Error: invalid spec: %v", err)
+	}
+	return spec, nil
+}
+
+func getExportSink(ctx context.Context, clientManager *clients.ClientManager, namespaceID, name string) (*namespace.ExportSink, error) {
+	resp, err := callCloudService(ctx, clientManager, workflows.GetNamespaceExportSinkWorkflowType,
+		&cloudservice.GetNamespaceExportSinkRequest{Namespace: namespaceID, Name: name},
+		clientManager.GetCloudClient().CloudService().GetNamespaceExportSink)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetSink(), nil
+}
+
+func handleListExportSinks(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	namespaceID, errResult := exportSinkNamespace(ctx, clientManager, request.GetArguments())
+	if errResult != nil {
+		return errResult, nil
+	}
+	var (
+		sinks     []*namespace.ExportSink
+		pageToken string
+	)
+	for {
+		resp, err := callCloudService(ctx, clientManager, workflows.GetNamespaceExportSinksWorkflowType,
+			&cloudservice.GetNamespaceExportSinksRequest{Namespace: namespaceID, PageToken: pageToken},
+			clientManager.GetCloudClient().CloudService().GetNamespaceExportSinks)
+		if err != nil {
+			return cloudAPIErrorResult("Error listing export sinks", err, ""), nil
+		}
+		sinks = append(sinks, resp.GetSinks()...)
+		if resp.GetNextPageToken() == "" {
+			break
+		}
+		pageToken = resp.GetNextPageToken()
+	}
+	return jsonResult(map[string]interface{}{
+		"namespace": namespaceID,
+		"sinks":     sinks,
+	}), nil
+}
+
+func handleGetExportSink(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, errResult := requiredString(arguments, "name")
+	if errResult != nil {
+		return errResult, nil
+	}
+	namespaceID, errResult := exportSinkNamespace(ctx, clientManager, arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+	sink, err := getExportSink(ctx, clientManager, namespaceID, name)
+	if err != nil {
+		return cloudAPIErrorResult("Error getting export sink", err, "temporal_list_export_sinks"), nil
+	}
+	return jsonResult(sink), nil
+}
+
+// exportSinkValidation is the outcome of checking a spec and of the Cloud API's test write
+type exportSinkValidation struct {
+	Valid       bool              `json:"valid"`
+	Checks      *preflight.Report `json:"checks"`
+	Error       *apierrors.Error  `json:"error,omitempty"`
+	Remediation []string          `json:"remediation,omitempty"`
+}
+
+// validateExportSink checks the spec locally and, if that passes, asks the Cloud API to validate it
+func validateExportSink(ctx context.Context, clientManager *clients.ClientManager, namespaceID string, spec *namespace.ExportSinkSpec) *exportSinkValidation {
+	v := &exportSinkValidation{Checks: preflight.CheckExportSink(spec)}
+	if !v.Checks.Valid {
+		return v
+	}
+	_, err := callCloudService(ctx, clientManager, workflows.ValidateNamespaceExportSinkWorkflowType,
+		&cloudservice.ValidateNamespaceExportSinkRequest{Namespace: namespaceID, Spec: spec},
+		clientManager.GetCloudClient().CloudService().ValidateNamespaceExportSink)
+	if err != nil {
+		v.Error = apierrors.Translate(err, "")
+		v.Remediation = exportSinkRemediation(spec)
+		return v
+	}
+	v.Valid = true
+	return v
+}
+
+// exportSinkRemediation lists the cloud provider settings a failed test write usually comes down to
+func exportSinkRemediation(spec *namespace.ExportSinkSpec) []string {
+	if s3 := spec.GetS3(); s3 != nil {
+		remediation := []string{
+			fmt.Sprintf("Check that IAM role %s exists in AWS account %s and its trust policy lets Temporal Cloud assume it.", s3.GetRoleName(), s3.GetAwsAccountId()),
+			fmt.Sprintf("Check that the role's policy allows s3:PutObject and s3:GetBucketLocation on bucket %s.", s3.GetBucketName()),
+			fmt.Sprintf("Check that bucket %s is in region %s.", s3.GetBucketName(), s3.GetRegion()),
+		}
+		if s3.GetKmsArn() != "" {
+			remediation = append(remediation, fmt.Sprintf("Check that the role may use KMS key %s with kms:GenerateDataKey and kms:Decrypt.", s3.GetKmsArn()))
+		}
+		return remediation
+	}
+	gcs := spec.GetGcs()
+	serviceAccount := fmt.Sprintf("%s@%s.iam.gserviceaccount.com", gcs.GetSaId(), gcs.GetGcpProjectId())
+	return []string{
+		fmt.Sprintf("Check that service account %s exists and Temporal Cloud may impersonate it (roles/iam.serviceAccountTokenCreator).", serviceAccount),
+		fmt.Sprintf("Check that %s can write objects to bucket %s (roles/storage.objectCreator).", serviceAccount, gcs.GetBucketName()),
+		fmt.Sprintf("Check that bucket %s is in region %s of project %s.", gcs.GetBucketName(), gcs.GetRegion(), gcs.GetGcpProjectId()),
+	}
+}
+
+// validationFailedResult renders a failed validation as an error result
+func validationFailedResult(v *exportSinkValidation) *mcp.CallToolResult {
+	result := jsonResult(v)
+	result.IsError = true
+	return result
+}
+
+func handleValidateExportSink(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	namespaceID, errResult := exportSinkNamespace(ctx, clientManager, arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+	var spec *namespace.ExportSinkSpec
+	if _, ok := arguments["spec"]; ok {
+		if spec, errResult = exportSinkSpec(arguments); errResult != nil {
+			return errResult, nil
+		}
+	} else {
+		name := optionalString(arguments, "name")
+		if name == "" {
+			return errorResult("Error: spec or name is required"), nil
+		}
+		sink, err := getExportSink(ctx, clientManager, namespaceID, name)
+		if err != nil {
+			return cloudAPIErrorResult("Error getting export sink", err, "temporal_list_export_sinks"), nil
+		}
+		spec = sink.GetSpec()
+	}
+
+	v := validateExportSink(ctx, clientManager, namespaceID, spec)
+	if !v.Valid {
+		return validationFailedResult(v), nil
+	}
+	return jsonResult(v), nil
+}
+
+func handleCreateExportSink(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	namespaceID, errResult := exportSinkNamespace(ctx, clientManager, arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+	spec, errResult := exportSinkSpec(arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if skip, _ := arguments["skip_validation"].(bool); !skip {
+		if v := validateExportSink(ctx, clientManager, namespaceID, spec); !v.Valid {
+			return validationFailedResult(v), nil
+		}
+	}
+
+	createReq := &cloudservice.CreateNamespaceExportSinkRequest{
+		Namespace:        namespaceID,
+		Spec:             spec,
+		AsyncOperationId: uuid.New().String(),
+	}
+	createResp, err := callCloudService(ctx, clientManager, workflows.CreateNamespaceExportSinkWorkflowType, createReq,
+		clientManager.GetCloudClient().CloudService().CreateNamespaceExportSink)
+	if err != nil {
+		return cloudAPIErrorResult("Error creating export sink", err, "temporal_list_export_sinks"), nil
+	}
+	return exportSinkChangeResult(ctx, clientManager, arguments, namespaceID, spec.GetName(), createResp.GetAsyncOperation().GetId(), true)
+}
+
+func handleUpdateExportSink(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	namespaceID, errResult := exportSinkNamespace(ctx, clientManager, arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+	spec, errResult := exportSinkSpec(arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if skip, _ := arguments["skip_validation"].(bool); !skip {
+		if v := validateExportSink(ctx, clientManager, namespaceID, spec); !v.Valid {
+			return validationFailedResult(v), nil
+		}
+	}
+	resourceVersion := optionalString(arguments, "resource_version")
+	if resourceVersion == "" {
+		current, err := getExportSink(ctx, clientManager, namespaceID, spec.GetName())
+		if err != nil {
+			return cloudAPIErrorResult("Error getting export sink", err, "temporal_list_export_sinks"), nil
+		}
+		resourceVersion = current.GetResourceVersion()
+	}
+
+	updateReq := &cloudservice.UpdateNamespaceExportSinkRequest{
+		Namespace:        namespaceID,
+		Spec:             spec,
+		ResourceVersion:  resourceVersion,
+		AsyncOperationId: uuid.New().String(),
+	}
+	updateResp, err := callCloudService(ctx, clientManager, workflows.UpdateNamespaceExportSinkWorkflowType, updateReq,
+		clientManager.GetCloudClient().CloudService().UpdateNamespaceExportSink)
+	if err != nil {
+		return cloudAPIErrorResult("Error updating export sink", err, "temporal_get_export_sink"), nil
+	}
+	return exportSinkChangeResult(ctx, clientManager, arguments, namespaceID, spec.GetName(), updateResp.GetAsyncOperation().GetId(), true)
+}
+
+func handleDeleteExportSink(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, errResult := requiredString(arguments, "name")
+	if errResult != nil {
+		return errResult, nil
+	}
+	namespaceID, errResult := exportSinkNamespace(ctx, clientManager, arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+	resourceVersion := optionalString(arguments, "resource_version")
+	if resourceVersion == "" {
+		current, err := getExportSink(ctx, clientManager, namespaceID, name)
+		if err != nil {
+			return cloudAPIErrorResult("Error getting export sink", err, "temporal_list_export_sinks"), nil
+		}
+		resourceVersion = current.GetResourceVersion()
+	}
+
+	deleteReq := &cloudservice.DeleteNamespaceExportSinkRequest{
+		Namespace:        namespaceID,
+		Name:             name,
+		ResourceVersion:  resourceVersion,
+		AsyncOperationId: uuid.New().String(),
+	}
+	deleteResp, err := callCloudService(ctx, clientManager, workflows.DeleteNamespaceExportSinkWorkflowType, deleteReq,
+		clientManager.GetCloudClient().CloudService().DeleteNamespaceExportSink)
+	if err != nil {
+		return cloudAPIErrorResult("Error deleting export sink", err, "temporal_get_export_sink"), nil
+	}
+	return exportSinkChangeResult(ctx, clientManager, arguments, namespaceID, name, deleteResp.GetAsyncOperation().GetId(), false)
+}
+
+// exportSinkChangeResult waits for the change and returns the operation, and the sink afterwards unless it was deleted
+func exportSinkChangeResult(ctx context.Context, clientManager *clients.ClientManager, arguments map[string]interface{}, namespaceID, name, operationID string, refetch bool) (*mcp.CallToolResult, error) {
+	operation, err := waitForAsyncOperation(ctx, clientManager, operationID, asyncOperationTimeout(arguments))
+	if err != nil {
+		return cloudAPIErrorResult("Error waiting for export sink change", err, "temporal_get_export_sink"), nil
+	}
+	result := map[string]interface{}{
+		"namespace":       namespaceID,
+		"name":            name,
+		"async_operation": operation,
+	}
+	if refetch {
+		sink, err := getExportSink(ctx, clientManager, namespaceID, name)
+		if err != nil {
+			return cloudAPIErrorResult("Error getting export sink", err, "temporal_get_export_sink"), nil
+		}
+		result["sink"] = sink
+	}
+	return jsonResult(result), nil
+}
@@ -0,0 +1,176 @@
+package tools
+
+import (
+	"context"
+	"time"
+
+	"bechols/temcp/cmd/mcp-server/clients"
+	"bechols/temcp/cmd/mcp-server/config"
+	"bechols/temcp/cmd/mcp-server/logging"
+	"bechols/temcp/workflows"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// RegisterFailoverDrillTools registers the failover drill job tools with the MCP server. Drills run as
+// FailoverDrill workflows, so they need a Temporal client.
+func RegisterFailoverDrillTools(mcpServer *server.MCPServer, cfg *config.Config, clientManager *clients.ClientManager) {
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_start_failover_drill",
+			mcp.WithDescription("Start a failover drill job on a multi-region namespace: fail over to the replica, wait for the soak time, then fail back to the original active region. Returns right away with the job's workflow ID; follow it with temporal_get_failover_drill."),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+			mcp.WithString("region", mcp.Description("Replica region to fail over to (optional when the namespace has one replica)")),
+			mcp.WithNumber("soak_minutes", mcp.Description("How long the namespace stays in the replica region before failing back (optional, default 10)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for each failover in seconds (optional, default 1800)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleStartFailoverDrill(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_get_failover_drill",
+			mcp.WithDescription("Get the phase and timing report of a namespace's latest failover drill job"),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetFailoverDrill(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_abort_failover_drill",
+			mcp.WithDescription("Abort a running failover drill job: ends the soak early and fails the namespace back to its original active region"),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+			mcp.WithString("reason", mcp.Description("Why the drill was aborted, recorded in the report (optional)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleAbortFailoverDrill(ctx, request, clientManager)
+		},
+	)
+}
+
+// failoverDrillWorkflowID is the workflow ID of a namespace's drill, so only one drill runs per namespace at a time
+func failoverDrillWorkflowID(namespaceID string) string {
+	return "failover-drill-" + namespaceID
+}
+
+// failoverDrillNamespace checks that drills can run and resolves the namespace argument
+func failoverDrillNamespace(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (string, *mcp.CallToolResult) {
+	if clientManager.GetTemporalClient() == nil {
+		return "", errorResult("Error: failover drills run as Temporal workflows; configure a Temporal namespace for the MCP server's worker")
+	}
+	namespaceID, errResult := requiredString(request.GetArguments(), "namespace")
+	if errResult != nil {
+		return "", errResult
+	}
+	return resolveNamespace(ctx, clientManager, namespaceID)
+}
+
+func handleStartFailoverDrill(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	namespaceID, errResult := failoverDrillNamespace(ctx, request, clientManager)
+	if errResult != nil {
+		return errResult, nil
+	}
+	arguments := request.GetArguments()
+	in := &workflows.FailoverDrillInput{
+		Namespace: namespaceID,
+		Region:    optionalString(arguments, "region"),
+	}
+	if minutes, ok := arguments["soak_minutes"].(float64); ok && minutes > 0 {
+		in.Soak = time.Duration(minutes * float64(time.Minute))
+	}
+	if seconds, ok := arguments["timeout_seconds"].(float64); ok && seconds > 0 {
+		in.OperationTimeout = time.Duration(seconds) * time.Second
+	}
+
+	// Check the failover up front so a drill that can't run isn't started
+	ns, _, err := getNamespaceAndRegions(ctx, clientManager, namespaceID)
+	if err != nil {
+		return cloudAPIErrorResult("Error getting namespace regions", err, "temporal_get_namespace"), nil
+	}
+	if in.Region == "" {
+		for _, region := range ns.GetSpec().GetRegions() {
+			if region != ns.GetActiveRegion() {
+				in.Region = region
+				break
+			}
+		}
+		if in.Region == "" {
+			return errorResult("Namespace %s has no replica region to fail over to; add one with temporal_add_namespace_region", namespaceID), nil
+		}
+	}
+	if check := failoverNamespaceRegion.check(ns, in.Region, nil); !check.Valid {
+		result := jsonResult(check)
+		result.IsError = true
+		return result, nil
+	}
+
+	workflowID := failoverDrillWorkflowID(namespaceID)
+	run, err := clientManager.StartWorkflow(ctx, workflowID, workflows.FailoverDrillWorkflowType, in)
+	if err != nil {
+		return startJobErrorResult("failover drill for namespace "+namespaceID, workflowID, err, "temporal_get_failover_drill"), nil
+	}
+	logging.FromContext(ctx).Info("started failover drill",
+		zap.String("namespace", namespaceID), zap.String("region", in.Region), zap.String("workflow_id", run.GetID()))
+	return jsonResult(map[string]interface{}{
+		"namespace":       namespaceID,
+		"original_region": ns.GetActiveRegion(),
+		"drill_region":    in.Region,
+		"workflow_id":     run.GetID(),
+		"run_id":          run.GetRunID(),
+	}), nil
+}
+
+func handleGetFailoverDrill(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	namespaceID, errResult := failoverDrillNamespace(ctx, request, clientManager)
+	if errResult != nil {
+		return errResult, nil
+	}
+	workflowID := failoverDrillWorkflowID(namespaceID)
+	running, err := clientManager.WorkflowRunning(ctx, workflowID)
+	if err != nil {
+		return errorResult("Error getting failover drill for namespace %s: %v", namespaceID, err), nil
+	}
+
+	// The report query also answers for closed drills, so failed drills keep their timings
+	var report workflows.FailoverDrillReport
+	if err := clientManager.QueryWorkflowInto(ctx, workflowID, workflows.FailoverDrillReportQuery, &report); err != nil {
+		return errorResult("Error getting failover drill report for namespace %s: %v", namespaceID, err), nil
+	}
+	result := map[string]interface{}{
+		"workflow_id": workflowID,
+		"running":     running,
+		"report":      &report,
+	}
+	if !running {
+		var final workflows.FailoverDrillReport
+		if err := clientManager.WorkflowResultInto(ctx, workflowID, &final); err != nil {
+			result["error"] = err.Error()
+		}
+	}
+	return jsonResult(result), nil
+}
+
+func handleAbortFailoverDrill(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	namespaceID, errResult := failoverDrillNamespace(ctx, request, clientManager)
+	if errResult != nil {
+		return errResult, nil
+	}
+	reason := optionalString(request.GetArguments(), "reason")
+	if reason == "" {
+		reason = "aborted"
+	}
+	workflowID := failoverDrillWorkflowID(namespaceID)
+	if err := clientManager.SignalWorkflow(ctx, workflowID, workflows.FailoverDrillAbortSignal, reason); err != nil {
+		return errorResult("Error aborting failover drill for namespace %s: %v", namespaceID, err), nil
+	}
+	logging.FromContext(ctx).Info("aborted failover drill", zap.String("namespace", namespaceID), zap.String("reason", reason))
+	return jsonResult(map[string]interface{}{
+		"namespace":   namespaceID,
+		"workflow_id": workflowID,
+		"aborted":     true,
+		"detail":      "the drill fails the namespace back to its original region; follow it with temporal_get_failover_drill",
+	}), nil
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"bechols/temcp/cmd/mcp-server/clients"
 	"bechols/temcp/cmd/mcp-server/config"
+	"bechols/temcp/internal/access"
 	"bechols/temcp/workflows"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -19,9 +21,9 @@ func RegisterNamespaceAccessTools(mcpServer *server.MCPServer, cfg *config.Confi
 	// Register temporal_get_user_namespace_access tool
 	mcpServer.AddTool(
 		mcp.NewTool("temporal_get_user_namespace_access",
-			mcp.WithDescription("Get a user's access level for a specific namespace - for users only, not service accounts"),
-			mcp.WithString("user_id", mcp.Description("User ID"), mcp.Required()),
-			mcp.WithString("namespace", mcp.Description("Namespace name"), mcp.Required()),
+			mcp.WithDescription("Get a user's direct access level for a specific namespace - for users only, not service accounts; use temporal_explain_access for access through account roles and groups"),
+			mcp.WithString("user_id", mcp.Description("User ID or email"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return handleGetUserNamespaceAccess(ctx, request, clientManager)
@@ -32,8 +34,8 @@ func RegisterNamespaceAccessTools(mcpServer *server.MCPServer, cfg *config.Confi
 	mcpServer.AddTool(
 		mcp.NewTool("temporal_set_user_namespace_access",
 			mcp.WithDescription("Set or update a user's access level for a specific namespace - for users only, not service accounts"),
-			mcp.WithString("user_id", mcp.Description("User ID"), mcp.Required()),
-			mcp.WithString("namespace", mcp.Description("Namespace name"), mcp.Required()),
+			mcp.WithString("user_id", mcp.Description("User ID or email"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
 			mcp.WithString("permission", mcp.Description("Permission level: ADMIN, WRITE, or READ"), mcp.Required()),
 			mcp.WithString("resource_version", mcp.Description("Resource version for optimistic concurrency (optional)")),
 		),
@@ -41,6 +43,31 @@ func RegisterNamespaceAccessTools(mcpServer *server.MCPServer, cfg *config.Confi
 			return handleSetUserNamespaceAccess(ctx, request, clientManager)
 		},
 	)
+
+	// Register temporal_explain_access tool
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_explain_access",
+			mcp.WithDescription("Explain the effective permission of a user or service account on a namespace, combining account role, direct grants and user group memberships"),
+			mcp.WithString("principal", mcp.Description("User ID or email, or service account ID or name"), mcp.Required()),
+			mcp.WithString("principal_type", mcp.Description("Type of principal: user or service-account (optional, default user)")),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleExplainAccess(ctx, request, clientManager)
+		},
+	)
+
+	// Register temporal_namespace_access_report tool
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_namespace_access_report",
+			mcp.WithDescription("Report every user and service account with effective access to a namespace, the source of each grant and the API keys that can act on it"),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+			mcp.WithString("format", mcp.Description("Output format: json, csv or markdown (optional, default markdown)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleNamespaceAccessReport(ctx, request, clientManager)
+		},
+	)
 }
 
 func handleGetUserNamespaceAccess(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
@@ -59,6 +86,11 @@ func handleGetUserNamespaceAccess(ctx context.Context, request mcp.CallToolReque
 		}, nil
 	}
 
+	userID, errResult := resolveUserID(ctx, clientManager, userID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	namespace, ok := arguments["namespace"].(string)
 	if !ok || namespace == "" {
 		return &mcp.CallToolResult{
@@ -72,6 +104,11 @@ func handleGetUserNamespaceAccess(ctx context.Context, request mcp.CallToolReque
 		}, nil
 	}
 
+	namespace, errResult = resolveNamespace(ctx, clientManager, namespace)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	// Get the user details which includes namespace access information
 	getUserReq := &cloudservice.GetUserRequest{
 		UserId: userID,
@@ -89,15 +126,7 @@ func handleGetUserNamespaceAccess(ctx context.Context, request mcp.CallToolReque
 	}
 
 	if err != nil {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error getting user: %v", err),
-				},
-			},
-		}, nil
+		return cloudAPIErrorResult("Error getting user", err, "temporal_get_user"), nil
 	}
 
 	// Extract user data and namespace access
@@ -199,6 +228,11 @@ func handleSetUserNamespaceAccess(ctx context.Context, request mcp.CallToolReque
 		}, nil
 	}
 
+	userID, errResult := resolveUserID(ctx, clientManager, userID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	namespace, ok := arguments["namespace"].(string)
 	if !ok || namespace == "" {
 		return &mcp.CallToolResult{
@@ -212,6 +246,11 @@ func handleSetUserNamespaceAccess(ctx context.Context, request mcp.CallToolReque
 		}, nil
 	}
 
+	namespace, errResult = resolveNamespace(ctx, clientManager, namespace)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	permissionStr, ok := arguments["permission"].(string)
 	if !ok || permissionStr == "" {
 		return &mcp.CallToolResult{
@@ -276,15 +315,7 @@ func handleSetUserNamespaceAccess(ctx context.Context, request mcp.CallToolReque
 	}
 
 	if err != nil {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error setting user namespace access: %v", err),
-				},
-			},
-		}, nil
+		return cloudAPIErrorResult("Error setting user namespace access", err, "temporal_get_user"), nil
 	}
 
 	resultJSON, err := json.MarshalIndent(result, "", "  ")
@@ -309,3 +340,111 @@ func handleSetUserNamespaceAccess(ctx context.Context, request mcp.CallToolReque
 		},
 	}, nil
 }
+
+func handleExplainAccess(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+
+	principal, errResult := requiredString(arguments, "principal")
+	if errResult != nil {
+		return errResult, nil
+	}
+	namespace, errResult := requiredString(arguments, "namespace")
+	if errResult != nil {
+		return errResult, nil
+	}
+	principalType := optionalString(arguments, "principal_type")
+	if principalType == "" {
+		principalType = "user"
+	}
+	ownerType, ok := parseOwnerType(principalType)
+	if !ok {
+		return errorResult("Error: principal_type must be user or service-account"), nil
+	}
+
+	namespace, errResult = resolveNamespace(ctx, clientManager, namespace)
+	if errResult != nil {
+		return errResult, nil
+	}
+	principalID, errResult := resolveOwnerID(ctx, clientManager, ownerType, principal)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	explainer := access.New(clientManager.GetCloudClient())
+	var (
+		explanation *access.Explanation
+		err         error
+	)
+	if ownerType == identity.OwnerType_OWNER_TYPE_SERVICE_ACCOUNT {
+		explanation, err = explainer.ServiceAccount(ctx, principalID, namespace)
+	} else {
+		explanation, err = explainer.User(ctx, principalID, namespace)
+	}
+	if err != nil {
+		return cloudAPIErrorResult("Error explaining access", err, ""), nil
+	}
+	return jsonResult(explanation), nil
+}
+
+func handleNamespaceAccessReport(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+
+	namespace, errResult := requiredString(arguments, "namespace")
+	if errResult != nil {
+		return errResult, nil
+	}
+	format := strings.ToLower(optionalString(arguments, "format"))
+	if format == "" {
+		format = "markdown"
+	}
+	if format != "json" && format != "csv" && format != "markdown" {
+		return errorResult("Error: format must be json, csv or markdown"), nil
+	}
+	namespace, errResult = resolveNamespace(ctx, clientManager, namespace)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	// Use workflow if Temporal client is available, otherwise read the account directly
+	var (
+		report *access.NamespaceReport
+		err    error
+	)
+	if clientManager.GetTemporalClient() != nil {
+		report = &access.NamespaceReport{}
+		err = clientManager.ExecuteWorkflowInto(ctx, workflows.NamespaceAccessReportWorkflowType,
+			&workflows.NamespaceAccessReportInput{Namespace: namespace}, report)
+	} else {
+		report, err = access.New(clientManager.GetCloudClient()).NamespaceReport(ctx, namespace)
+	}
+	if err != nil {
+		return cloudAPIErrorResult("Error building namespace access report", err, ""), nil
+	}
+
+	switch format {
+	case "csv":
+		text, err := report.CSV()
+		if err != nil {
+			return errorResult("Error rendering report: %v", err), nil
+		}
+		return textResult(text), nil
+	case "markdown":
+		return textResult(report.Markdown()), nil
+	default:
+		return jsonResult(report), nil
+	}
+}
+
+// parseNamespacePermission parses a namespace permission name (admin, write or read), ignoring case
+func parseNamespacePermission(permission string) (identity.NamespaceAccess_Permission, bool) {
+	switch strings.ToLower(permission) {
+	case "admin":
+		return identity.NamespaceAccess_PERMISSION_ADMIN, true
+	case "write":
+		return identity.NamespaceAccess_PERMISSION_WRITE, true
+	case "read":
+		return identity.NamespaceAccess_PERMISSION_READ, true
+	default:
+		return identity.NamespaceAccess_PERMISSION_UNSPECIFIED, false
+	}
+}
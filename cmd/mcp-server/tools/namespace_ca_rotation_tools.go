@@ -0,0 +1,180 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"bechols/temcp/cmd/mcp-server/clients"
+	"bechols/temcp/cmd/mcp-server/config"
+	"bechols/temcp/cmd/mcp-server/logging"
+	"bechols/temcp/internal/cabundle"
+	"bechols/temcp/internal/preflight"
+	"bechols/temcp/workflows"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+	"go.uber.org/zap"
+)
+
+// RegisterNamespaceCARotationTools registers the namespace mTLS CA rotation tools with the MCP server.
+// Rotations run as RotateNamespaceCA workflows, so they need a Temporal client.
+func RegisterNamespaceCARotationTools(mcpServer *server.MCPServer, cfg *config.Config, clientManager *clients.ClientManager) {
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_start_namespace_ca_rotation",
+			mcp.WithDescription("Start rotating the CA an mTLS namespace accepts client certificates from: append the new CA, wait for a confirmation or the grace period so workers can switch certificates, then remove the old CA. Returns right away with the rotation's workflow ID; follow it with temporal_get_namespace_ca_rotation."),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+			mcp.WithString("new_ca", mcp.Description("New CA bundle as PEM or base64-encoded PEM"), mcp.Required()),
+			mcp.WithString("remove_fingerprints", mcp.Description("Comma-separated SHA-256 fingerprints of the certificates to remove after the grace period (optional, default every certificate not in the new CA)")),
+			mcp.WithNumber("grace_minutes", mcp.Description("How long both CAs are accepted unless the rotation is confirmed first (optional, default 60)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for each namespace update in seconds (optional, default 1800)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleStartNamespaceCARotation(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_get_namespace_ca_rotation",
+			mcp.WithDescription("Get the phase and report of a namespace's latest CA rotation"),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetNamespaceCARotation(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_confirm_namespace_ca_rotation",
+			mcp.WithDescription("Confirm that every worker and client of the namespace uses a certificate from the new CA, so the rotation removes the old CA without waiting for the rest of the grace period"),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+			mcp.WithString("note", mcp.Description("Who confirmed and how, recorded in the report (optional)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleConfirmNamespaceCARotation(ctx, request, clientManager)
+		},
+	)
+}
+
+// namespaceCARotationWorkflowID is the workflow ID of a namespace's rotation, so only one rotation runs per namespace at a time
+func namespaceCARotationWorkflowID(namespaceID string) string {
+	return "namespace-ca-rotation-" + namespaceID
+}
+
+// namespaceCARotationNamespace checks that rotations can run and resolves the namespace argument
+func namespaceCARotationNamespace(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (string, *mcp.CallToolResult) {
+	if clientManager.GetTemporalClient() == nil {
+		return "", errorResult("Error: CA rotations run as Temporal workflows; configure a Temporal namespace for the MCP server's worker")
+	}
+	namespaceID, errResult := requiredString(request.GetArguments(), "namespace")
+	if errResult != nil {
+		return "", errResult
+	}
+	return resolveNamespace(ctx, clientManager, namespaceID)
+}
+
+func handleStartNamespaceCARotation(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	namespaceID, errResult := namespaceCARotationNamespace(ctx, request, clientManager)
+	if errResult != nil {
+		return errResult, nil
+	}
+	arguments := request.GetArguments()
+	value, errResult := requiredString(arguments, "new_ca")
+	if errResult != nil {
+		return errResult, nil
+	}
+	newCA, err := cabundle.Decode(value)
+	if err != nil {
+		return errorResult("Error: new_ca: %v", err), nil
+	}
+	if errResult := invalidReportResult(preflight.CheckCABundle("new_ca", newCA, time.Now()), false); errResult != nil {
+		return errResult, nil
+	}
+
+	result, errResult := startNamespaceCARotation(ctx, clientManager, namespaceCARotationInput(arguments, namespaceID, newCA))
+	if errResult != nil {
+		return errResult, nil
+	}
+	return jsonResult(result), nil
+}
+
+// namespaceCARotationInput reads the rotation's remove_fingerprints, grace_minutes and timeout_seconds arguments
+func namespaceCARotationInput(arguments map[string]interface{}, namespaceID string, newCA []byte) *workflows.RotateNamespaceCAInput {
+	in := &workflows.RotateNamespaceCAInput{Namespace: namespaceID, NewCA: newCA}
+	for _, fingerprint := range strings.Split(optionalString(arguments, "remove_fingerprints"), ",") {
+		if fingerprint = strings.TrimSpace(fingerprint); fingerprint != "" {
+			in.RemoveFingerprints = append(in.RemoveFingerprints, fingerprint)
+		}
+	}
+	if minutes, ok := arguments["grace_minutes"].(float64); ok && minutes > 0 {
+		in.GracePeriod = time.Duration(minutes * float64(time.Minute))
+	}
+	if seconds, ok := arguments["timeout_seconds"].(float64); ok && seconds > 0 {
+		in.OperationTimeout = time.Duration(seconds) * time.Second
+	}
+	return in
+}
+
+// startNamespaceCARotation starts the namespace's RotateNamespaceCA workflow and returns its IDs
+func startNamespaceCARotation(ctx context.Context, clientManager *clients.ClientManager, in *workflows.RotateNamespaceCAInput) (map[string]interface{}, *mcp.CallToolResult) {
+	// Check the namespace up front so a rotation that can't run isn't started
+	resp, err := callCloudService(ctx, clientManager, workflows.GetNamespaceWorkflowType,
+		&cloudservice.GetNamespaceRequest{Namespace: in.Namespace}, clientManager.GetCloudClient().CloudService().GetNamespace)
+	if err != nil {
+		return nil, cloudAPIErrorResult("Error getting namespace", err, "temporal_get_namespace")
+	}
+	mtls := resp.GetNamespace().GetSpec().GetMtlsAuth()
+	if !mtls.GetEnabled() && len(mtls.GetAcceptedClientCa()) == 0 && mtls.GetAcceptedClientCaDeprecated() == "" {
+		return nil, errorResult("Namespace %s doesn't use mTLS auth, there is no CA to rotate", in.Namespace)
+	}
+
+	workflowID := namespaceCARotationWorkflowID(in.Namespace)
+	run, err := clientManager.StartWorkflow(ctx, workflowID, workflows.RotateNamespaceCAWorkflowType, in)
+	if err != nil {
+		return nil, startJobErrorResult("CA rotation for namespace "+in.Namespace, workflowID, err, "temporal_get_namespace_ca_rotation")
+	}
+	logging.FromContext(ctx).Info("started namespace CA rotation",
+		zap.String("namespace", in.Namespace), zap.String("workflow_id", run.GetID()))
+	return map[string]interface{}{
+		"namespace":   in.Namespace,
+		"workflow_id": run.GetID(),
+		"run_id":      run.GetRunID(),
+	}, nil
+}
+
+func handleGetNamespaceCARotation(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	namespaceID, errResult := namespaceCARotationNamespace(ctx, request, clientManager)
+	if errResult != nil {
+		return errResult, nil
+	}
+	return caRotationResult(ctx, clientManager, namespaceCARotationWorkflowID(namespaceID))
+}
+
+func handleConfirmNamespaceCARotation(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	namespaceID, errResult := namespaceCARotationNamespace(ctx, request, clientManager)
+	if errResult != nil {
+		return errResult, nil
+	}
+	note := optionalString(request.GetArguments(), "note")
+	workflowID := namespaceCARotationWorkflowID(namespaceID)
+	// Only a rotation in its grace period can be confirmed: before that the new CA isn't accepted yet, and the
+	// workflow ignores the confirmation
+	var report workflows.CARotationReport
+	if err := clientManager.QueryWorkflowInto(ctx, workflowID, workflows.CARotationReportQuery, &report); err != nil {
+		return errorResult("Error getting CA rotation report for namespace %s: %v", namespaceID, err), nil
+	}
+	if report.Phase != workflows.CARotationPhaseOverlapping {
+		return errorResult("The CA rotation for namespace %s is %s, only a rotation in its grace period (%s) can be confirmed; follow it with temporal_get_namespace_ca_rotation",
+			namespaceID, report.Phase, workflows.CARotationPhaseOverlapping), nil
+	}
+	if err := clientManager.SignalWorkflow(ctx, workflowID, workflows.NamespaceCARotationConfirmSignal, note); err != nil {
+		return errorResult("Error confirming CA rotation for namespace %s: %v", namespaceID, err), nil
+	}
+	logging.FromContext(ctx).Info("confirmed namespace CA rotation", zap.String("namespace", namespaceID))
+	return jsonResult(map[string]interface{}{
+		"namespace":   namespaceID,
+		"workflow_id": workflowID,
+		"confirmed":   true,
+		"detail":      "the rotation now removes the old CA; follow it with temporal_get_namespace_ca_rotation",
+	}), nil
+}
@@ -4,14 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"bechols/temcp/cmd/mcp-server/clients"
 	"bechols/temcp/cmd/mcp-server/config"
+	"bechols/temcp/cmd/mcp-server/logging"
+	"bechols/temcp/internal/preflight"
 	"bechols/temcp/workflows"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
 	"go.temporal.io/cloud-sdk/api/namespace/v1"
+	"go.uber.org/zap"
 )
 
 // RegisterNamespaceMgmtTools registers all namespace management tools with the MCP server
@@ -20,7 +24,7 @@ func RegisterNamespaceMgmtTools(mcpServer *server.MCPServer, cfg *config.Config,
 	mcpServer.AddTool(
 		mcp.NewTool("temporal_get_namespace",
 			mcp.WithDescription("Get a Temporal Cloud namespace by name"),
-			mcp.WithString("namespace", mcp.Description("Namespace name"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return handleGetNamespace(ctx, request, clientManager)
@@ -42,8 +46,9 @@ func RegisterNamespaceMgmtTools(mcpServer *server.MCPServer, cfg *config.Config,
 	// Register temporal_create_namespace tool
 	mcpServer.AddTool(
 		mcp.NewTool("temporal_create_namespace",
-			mcp.WithDescription("Create a new Temporal Cloud namespace"),
+			mcp.WithDescription("Create a new Temporal Cloud namespace. The spec is checked first (name syntax and collisions, regions, retention, CA bundle, codec endpoint, search attributes) and not sent if it has errors"),
 			mcp.WithObject("namespace_spec", mcp.Description("Namespace specification object with required fields: name (string), regions (array of strings), retention_days (number), and optional fields like ca_certificate_base64, codec_server_endpoint, custom_search_attributes"), mcp.Required()),
+			mcp.WithBoolean("validate_only", mcp.Description("Only run the preflight checks and report the issues, don't create the namespace (optional, default: false)")),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return handleCreateNamespace(ctx, request, clientManager)
@@ -53,8 +58,8 @@ func RegisterNamespaceMgmtTools(mcpServer *server.MCPServer, cfg *config.Config,
 	// Register temporal_update_namespace tool
 	mcpServer.AddTool(
 		mcp.NewTool("temporal_update_namespace",
-			mcp.WithDescription("Update an existing Temporal Cloud namespace"),
-			mcp.WithString("namespace", mcp.Description("Namespace name"), mcp.Required()),
+			mcp.WithDescription("Update an existing Temporal Cloud namespace with a full UpdateNamespaceRequest; prefer temporal_patch_namespace to change only some fields"),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
 			mcp.WithObject("namespace_updates", mcp.Description("Namespace updates object"), mcp.Required()),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -66,7 +71,7 @@ func RegisterNamespaceMgmtTools(mcpServer *server.MCPServer, cfg *config.Config,
 	mcpServer.AddTool(
 		mcp.NewTool("temporal_delete_namespace",
 			mcp.WithDescription("Delete a Temporal Cloud namespace"),
-			mcp.WithString("namespace", mcp.Description("Namespace name"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return handleDeleteNamespace(ctx, request, clientManager)
@@ -89,6 +94,11 @@ func handleGetNamespace(ctx context.Context, request mcp.CallToolRequest, client
 		}, nil
 	}
 
+	namespaceName, errResult := resolveNamespace(ctx, clientManager, namespaceName)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	getNamespaceReq := &cloudservice.GetNamespaceRequest{
 		Namespace: namespaceName,
 	}
@@ -105,15 +115,7 @@ func handleGetNamespace(ctx context.Context, request mcp.CallToolRequest, client
 		result, err = cloudClient.CloudService().GetNamespace(ctx, getNamespaceReq)
 	}
 	if err != nil {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error getting namespace: %v", err),
-				},
-			},
-		}, nil
+		return cloudAPIErrorResult("Error getting namespace", err, "temporal_get_namespace"), nil
 	}
 
 	// Convert result to JSON
@@ -184,15 +186,7 @@ func handleListNamespaces(ctx context.Context, request mcp.CallToolRequest, clie
 		result, err = cloudClient.CloudService().GetNamespaces(ctx, getNamespacesReq)
 	}
 	if err != nil {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error listing namespaces: %v", err),
-				},
-			},
-		}, nil
+		return cloudAPIErrorResult("Error listing namespaces", err, ""), nil
 	}
 
 	resultJSON, err := json.MarshalIndent(result, "", "  ")
@@ -267,8 +261,24 @@ func handleCreateNamespace(ctx context.Context, request mcp.CallToolRequest, cli
 		}
 	}
 
+	report, err := preflightNamespace(ctx, clientManager, &namespaceSpec)
+	if err != nil {
+		return cloudAPIErrorResult("Error checking namespace specification", err, ""), nil
+	}
+	if validateOnly, _ := arguments["validate_only"].(bool); validateOnly {
+		return jsonResult(report), nil
+	}
+	if !report.Valid {
+		result := jsonResult(report)
+		result.IsError = true
+		return result, nil
+	}
+	for _, issue := range report.Issues {
+		logging.FromContext(ctx).Warn("namespace specification warning",
+			zap.String("field", issue.Field), zap.String("message", issue.Message))
+	}
+
 	var result interface{}
-	var err error
 
 	// Use workflow if Temporal client is available, otherwise call API directly
 	if clientManager.GetTemporalClient() != nil {
@@ -283,15 +293,7 @@ func handleCreateNamespace(ctx context.Context, request mcp.CallToolRequest, cli
 		result, err = cloudClient.CloudService().CreateNamespace(ctx, createReq)
 	}
 	if err != nil {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error creating namespace: %v", err),
-				},
-			},
-		}, nil
+		return cloudAPIErrorResult("Error creating namespace", err, "temporal_get_namespace"), nil
 	}
 
 	resultJSON, err := json.MarshalIndent(result, "", "  ")
@@ -332,6 +334,11 @@ func handleUpdateNamespace(ctx context.Context, request mcp.CallToolRequest, cli
 		}, nil
 	}
 
+	namespaceName, errResult := resolveNamespace(ctx, clientManager, namespaceName)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	namespaceUpdatesRaw, ok := arguments["namespace_updates"]
 	if !ok {
 		return &mcp.CallToolResult{
@@ -386,15 +393,7 @@ func handleUpdateNamespace(ctx context.Context, request mcp.CallToolRequest, cli
 		result, err = cloudClient.CloudService().UpdateNamespace(ctx, &updateReq)
 	}
 	if err != nil {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error updating namespace: %v", err),
-				},
-			},
-		}, nil
+		return cloudAPIErrorResult("Error updating namespace", err, "temporal_get_namespace"), nil
 	}
 
 	resultJSON, err := json.MarshalIndent(result, "", "  ")
@@ -435,6 +434,11 @@ func handleDeleteNamespace(ctx context.Context, request mcp.CallToolRequest, cli
 		}, nil
 	}
 
+	namespaceName, errResult := resolveNamespace(ctx, clientManager, namespaceName)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	// First, get the namespace to obtain its resource version
 	getNamespaceReq := &cloudservice.GetNamespaceRequest{
 		Namespace: namespaceName,
@@ -452,15 +456,7 @@ func handleDeleteNamespace(ctx context.Context, request mcp.CallToolRequest, cli
 		getResult, err = cloudClient.CloudService().GetNamespace(ctx, getNamespaceReq)
 	}
 	if err != nil {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error getting namespace before deletion: %v", err),
-				},
-			},
-		}, nil
+		return cloudAPIErrorResult("Error getting namespace before deletion", err, "temporal_get_namespace"), nil
 	}
 
 	// Extract the resource version
@@ -514,15 +510,7 @@ func handleDeleteNamespace(ctx context.Context, request mcp.CallToolRequest, cli
 		result, err = cloudClient.CloudService().DeleteNamespace(ctx, deleteReq)
 	}
 	if err != nil {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error deleting namespace: %v", err),
-				},
-			},
-		}, nil
+		return cloudAPIErrorResult("Error deleting namespace", err, "temporal_get_namespace"), nil
 	}
 
 	resultJSON, err := json.MarshalIndent(result, "", "  ")
@@ -547,3 +535,32 @@ func handleDeleteNamespace(ctx context.Context, request mcp.CallToolRequest, cli
 		},
 	}, nil
 }
+
+// preflightNamespace checks a namespace spec against the account's regions and existing namespaces
+func preflightNamespace(ctx context.Context, clientManager *clients.ClientManager, spec *namespace.NamespaceSpec) (*preflight.Report, error) {
+	cloudService := clientManager.GetCloudClient().CloudService()
+	env := preflight.NamespaceEnvironment{}
+
+	regions, err := callCloudService(ctx, clientManager, workflows.GetAllRegionsWorkflowType, &cloudservice.GetRegionsRequest{},
+		cloudService.GetRegions)
+	if err != nil {
+		return nil, err
+	}
+	for _, region := range regions.Regions {
+		env.Regions = append(env.Regions, region.Id)
+	}
+
+	if spec.Name != "" {
+		existing, err := callCloudService(ctx, clientManager, workflows.GetNamespacesWorkflowType, &cloudservice.GetNamespacesRequest{Name: spec.Name},
+			cloudService.GetNamespaces)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range existing.Namespaces {
+			if ns.GetSpec().GetName() == spec.Name {
+				env.ExistingNamespaces = append(env.ExistingNamespaces, ns.Namespace)
+			}
+		}
+	}
+	return preflight.CheckNamespace(spec, env, time.Now()), nil
+}
@@ -0,0 +1,158 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"bechols/temcp/cmd/mcp-server/clients"
+	"bechols/temcp/cmd/mcp-server/config"
+	"bechols/temcp/cmd/mcp-server/logging"
+	"bechols/temcp/internal/apierrors"
+	"bechols/temcp/internal/jsonpatch"
+	"bechols/temcp/workflows"
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+	"go.temporal.io/cloud-sdk/api/namespace/v1"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// defaultPatchNamespaceRetries is how many times a patch is re-applied after a resource version conflict
+const defaultPatchNamespaceRetries = 3
+
+// namespaceSpecJSON renders namespace specs with the proto field names, e.g. retention_days
+var namespaceSpecJSON = protojson.MarshalOptions{UseProtoNames: true}
+
+// RegisterNamespacePatchTools registers the patch-style namespace update tool with the MCP server
+func RegisterNamespacePatchTools(mcpServer *server.MCPServer, cfg *config.Config, clientManager *clients.ClientManager) {
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_patch_namespace",
+			mcp.WithDescription("Change only the given fields of a namespace spec: fetches the namespace, applies a JSON merge patch to its spec, shows a field-level diff, and submits with the fetched resource version, re-applying the patch if the namespace changed in the meantime"),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+			mcp.WithObject("patch", mcp.Description("JSON merge patch of the namespace spec using proto field names, e.g. {\"retention_days\": 7}; null removes a field"), mcp.Required()),
+			mcp.WithBoolean("dry_run", mcp.Description("Only show the diff, don't update the namespace (optional, default: false)")),
+			mcp.WithNumber("max_retries", mcp.Description("How many times to re-apply the patch after a resource version conflict (optional, default 3)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the update in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handlePatchNamespace(ctx, request, clientManager)
+		},
+	)
+}
+
+func handlePatchNamespace(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+
+	namespaceID, errResult := requiredString(arguments, "namespace")
+	if errResult != nil {
+		return errResult, nil
+	}
+	patch, ok := arguments["patch"].(map[string]interface{})
+	if !ok || len(patch) == 0 {
+		return errorResult("Error: patch is required and must be a non-empty object"), nil
+	}
+	dryRun, _ := arguments["dry_run"].(bool)
+	maxRetries := defaultPatchNamespaceRetries
+	if n, ok := arguments["max_retries"].(float64); ok && n >= 0 {
+		maxRetries = int(n)
+	}
+	namespaceID, errResult = resolveNamespace(ctx, clientManager, namespaceID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	cloudService := clientManager.GetCloudClient().CloudService()
+	for attempt := 1; ; attempt++ {
+		getResp, err := callCloudService(ctx, clientManager, workflows.GetNamespaceWorkflowType,
+			&cloudservice.GetNamespaceRequest{Namespace: namespaceID}, cloudService.GetNamespace)
+		if err != nil {
+			return cloudAPIErrorResult("Error getting namespace", err, "temporal_get_namespace"), nil
+		}
+		current := getResp.GetNamespace()
+		if current.GetSpec() == nil {
+			return errorResult("Namespace %s has no specification", namespaceID), nil
+		}
+
+		spec, changes, err := applyNamespacePatch(current.Spec, patch)
+		if err != nil {
+			return errorResult("Error applying patch to namespace %s: %v", namespaceID, err), nil
+		}
+		result := map[string]interface{}{
+			"namespace":        namespaceID,
+			"changes":          changes,
+			"resource_version": current.ResourceVersion,
+		}
+		if len(changes) == 0 {
+			result["changed"] = false
+			return jsonResult(result), nil
+		}
+		if dryRun {
+			result["dry_run"] = true
+			return jsonResult(result), nil
+		}
+
+		updateReq := &cloudservice.UpdateNamespaceRequest{
+			Namespace:        namespaceID,
+			Spec:             spec,
+			ResourceVersion:  current.ResourceVersion,
+			AsyncOperationId: uuid.New().String(),
+		}
+		updateResp, err := callCloudService(ctx, clientManager, workflows.UpdateNamespaceWorkflowType, updateReq, cloudService.UpdateNamespace)
+		if apierrors.IsConflict(err) && attempt <= maxRetries {
+			logging.FromContext(ctx).Info("namespace changed during patch, re-applying",
+				zap.String("namespace", namespaceID), zap.Int("attempt", attempt), zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return cloudAPIErrorResult("Error updating namespace", ctx.Err(), "temporal_get_namespace"), nil
+			case <-time.After(time.Duration(attempt) * 2 * time.Second):
+			}
+			continue
+		}
+		if err != nil {
+			return cloudAPIErrorResult("Error updating namespace", err, "temporal_get_namespace"), nil
+		}
+
+		operation, err := waitForAsyncOperation(ctx, clientManager, updateResp.AsyncOperation.GetId(), asyncOperationTimeout(arguments))
+		if err != nil {
+			return cloudAPIErrorResult("Error waiting for namespace update", err, ""), nil
+		}
+		result["changed"] = true
+		result["attempts"] = attempt
+		result["async_operation"] = operation
+		return jsonResult(result), nil
+	}
+}
+
+// applyNamespacePatch merges the patch into the spec's JSON form and decodes the result back into a spec,
+// so fields that don't exist or have the wrong type are rejected. The diff is taken between the
+// current spec and the decoded result, which leaves out patched fields that end up unchanged.
+func applyNamespacePatch(spec *namespace.NamespaceSpec, patch map[string]interface{}) (*namespace.NamespaceSpec, []jsonpatch.Change, error) {
+	before, err := namespaceSpecDocument(spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	mergedJSON, err := json.Marshal(jsonpatch.Merge(before, patch))
+	if err != nil {
+		return nil, nil, err
+	}
+	patched := &namespace.NamespaceSpec{}
+	if err := protojson.Unmarshal(mergedJSON, patched); err != nil {
+		return nil, nil, err
+	}
+	after, err := namespaceSpecDocument(patched)
+	if err != nil {
+		return nil, nil, err
+	}
+	return patched, jsonpatch.Diff(before, after), nil
+}
+
+func namespaceSpecDocument(spec *namespace.NamespaceSpec) (interface{}, error) {
+	specJSON, err := namespaceSpecJSON.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	return jsonpatch.Decode(specJSON)
+}
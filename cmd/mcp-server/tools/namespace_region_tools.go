@@ -0,0 +1,264 @@
+package tools
+
+import (
+	"context"
+	"strings"
+
+	"bechols/temcp/cmd/mcp-server/clients"
+	"bechols/temcp/cmd/mcp-server/config"
+	"bechols/temcp/cmd/mcp-server/logging"
+	"bechols/temcp/internal/preflight"
+	"bechols/temcp/workflows"
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+	"go.temporal.io/cloud-sdk/api/namespace/v1"
+	"go.temporal.io/cloud-sdk/api/operation/v1"
+	"go.temporal.io/cloud-sdk/api/region/v1"
+	"go.uber.org/zap"
+)
+
+// RegisterNamespaceRegionTools registers the multi-region (high availability) namespace tools with the MCP server
+func RegisterNamespaceRegionTools(mcpServer *server.MCPServer, cfg *config.Config, clientManager *clients.ClientManager) {
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_get_namespace_regions",
+			mcp.WithDescription("Show the regions of a namespace: which one is active, which are passive replicas, and any region still being added or removed"),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetNamespaceRegions(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_add_namespace_region",
+			mcp.WithDescription("Add a replica region to a namespace, making it a multi-region (high availability) namespace. The new region starts out passive. Waits for the operation and returns the namespace's region status."),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+			mcp.WithString("region", mcp.Description("ID of the region to add, from temporal_list_regions, e.g. aws-us-west-2"), mcp.Required()),
+			mcp.WithBoolean("validate_only", mcp.Description("Only check that the region can be added, don't add it (optional, default: false)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the region to be added in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleNamespaceRegionChange(ctx, request, clientManager, addNamespaceRegion)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_remove_namespace_region",
+			mcp.WithDescription("Remove a passive replica region from a namespace. The active region can't be removed; fail over first. Waits for the operation and returns the namespace's region status."),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+			mcp.WithString("region", mcp.Description("ID of the region to remove"), mcp.Required()),
+			mcp.WithBoolean("validate_only", mcp.Description("Only check that the region can be removed, don't remove it (optional, default: false)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the region to be removed in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleNamespaceRegionChange(ctx, request, clientManager, removeNamespaceRegion)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_failover_namespace",
+			mcp.WithDescription("Fail a multi-region namespace over to one of its passive replica regions, which becomes the active region. Waits for the operation and returns the namespace's region status."),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+			mcp.WithString("region", mcp.Description("ID of the passive region to make active"), mcp.Required()),
+			mcp.WithBoolean("validate_only", mcp.Description("Only check that the namespace can fail over, don't fail over (optional, default: false)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the failover in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleNamespaceRegionChange(ctx, request, clientManager, failoverNamespaceRegion)
+		},
+	)
+}
+
+// namespaceRegionStatus is the region layout of a namespace
+type namespaceRegionStatus struct {
+	Namespace    string               `json:"namespace"`
+	ActiveRegion string               `json:"active_region"`
+	Regions      []namespaceRegionRow `json:"regions"`
+}
+
+type namespaceRegionRow struct {
+	Region           string `json:"region"`
+	Role             string `json:"role"`
+	State            string `json:"state,omitempty"`
+	CloudProvider    string `json:"cloud_provider,omitempty"`
+	Location         string `json:"location,omitempty"`
+	AsyncOperationID string `json:"async_operation_id,omitempty"`
+}
+
+// newNamespaceRegionStatus lists the namespace's regions, active region first. Regions fill in the
+// cloud provider and location when they are known.
+func newNamespaceRegionStatus(ns *namespace.Namespace, regions []*region.Region) *namespaceRegionStatus {
+	status := &namespaceRegionStatus{
+		Namespace:    ns.GetNamespace(),
+		ActiveRegion: ns.GetActiveRegion(),
+		Regions:      []namespaceRegionRow{},
+	}
+	ids := append([]string(nil), ns.GetSpec().GetRegions()...)
+	for id := range ns.GetRegionStatus() {
+		if !preflight.ContainsRegion(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	for _, id := range ids {
+		row := namespaceRegionRow{Region: id, Role: "passive"}
+		if id == ns.GetActiveRegion() || (ns.GetActiveRegion() == "" && len(ids) == 1) {
+			row.Role = "active"
+		}
+		if regionStatus, ok := ns.GetRegionStatus()[id]; ok {
+			row.State = strings.TrimPrefix(regionStatus.GetState().String(), "STATE_")
+			row.AsyncOperationID = regionStatus.GetAsyncOperationId()
+		}
+		if r := preflight.FindRegion(regions, id); r != nil {
+			row.CloudProvider = strings.TrimPrefix(r.GetCloudProvider().String(), "CLOUD_PROVIDER_")
+			row.Location = r.GetLocation()
+		}
+		if row.Role == "active" {
+			status.Regions = append([]namespaceRegionRow{row}, status.Regions...)
+		} else {
+			status.Regions = append(status.Regions, row)
+		}
+	}
+	return status
+}
+
+// getNamespaceAndRegions fetches the namespace and the account's available regions
+func getNamespaceAndRegions(ctx context.Context, clientManager *clients.ClientManager, namespaceID string) (*namespace.Namespace, []*region.Region, error) {
+	cloudService := clientManager.GetCloudClient().CloudService()
+	nsResp, err := callCloudService(ctx, clientManager, workflows.GetNamespaceWorkflowType,
+		&cloudservice.GetNamespaceRequest{Namespace: namespaceID}, cloudService.GetNamespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	regionsResp, err := callCloudService(ctx, clientManager, workflows.GetAllRegionsWorkflowType,
+		&cloudservice.GetRegionsRequest{}, cloudService.GetRegions)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nsResp.GetNamespace(), regionsResp.GetRegions(), nil
+}
+
+func handleGetNamespaceRegions(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	namespaceID, errResult := requiredString(request.GetArguments(), "namespace")
+	if errResult != nil {
+		return errResult, nil
+	}
+	namespaceID, errResult = resolveNamespace(ctx, clientManager, namespaceID)
+	if errResult != nil {
+		return errResult, nil
+	}
+	ns, regions, err := getNamespaceAndRegions(ctx, clientManager, namespaceID)
+	if err != nil {
+		return cloudAPIErrorResult("Error getting namespace regions", err, "temporal_get_namespace"), nil
+	}
+	return jsonResult(newNamespaceRegionStatus(ns, regions)), nil
+}
+
+// namespaceRegionAction is one of the region changes: how it is checked and how it is submitted
+type namespaceRegionAction struct {
+	name   string
+	check  func(ns *namespace.Namespace, regionID string, regions []*region.Region) *preflight.Report
+	submit func(ctx context.Context, clientManager *clients.ClientManager, ns *namespace.Namespace, regionID string) (*operation.AsyncOperation, error)
+}
+
+var (
+	addNamespaceRegion = namespaceRegionAction{
+		name:  "adding namespace region",
+		check: preflight.CheckAddRegion,
+		submit: func(ctx context.Context, clientManager *clients.ClientManager, ns *namespace.Namespace, regionID string) (*operation.AsyncOperation, error) {
+			resp, err := callCloudService(ctx, clientManager, workflows.AddNamespaceRegionWorkflowType, &cloudservice.AddNamespaceRegionRequest{
+				Namespace:        ns.GetNamespace(),
+				Region:           regionID,
+				ResourceVersion:  ns.GetResourceVersion(),
+				AsyncOperationId: uuid.New().String(),
+			}, clientManager.GetCloudClient().CloudService().AddNamespaceRegion)
+			return resp.GetAsyncOperation(), err
+		},
+	}
+	removeNamespaceRegion = namespaceRegionAction{
+		name: "removing namespace region",
+		check: func(ns *namespace.Namespace, regionID string, _ []*region.Region) *preflight.Report {
+			return preflight.CheckRemoveRegion(ns, regionID)
+		},
+		submit: func(ctx context.Context, clientManager *clients.ClientManager, ns *namespace.Namespace, regionID string) (*operation.AsyncOperation, error) {
+			resp, err := callCloudService(ctx, clientManager, workflows.DeleteNamespaceRegionWorkflowType, &cloudservice.DeleteNamespaceRegionRequest{
+				Namespace:        ns.GetNamespace(),
+				Region:           regionID,
+				ResourceVersion:  ns.GetResourceVersion(),
+				AsyncOperationId: uuid.New().String(),
+			}, clientManager.GetCloudClient().CloudService().DeleteNamespaceRegion)
+			return resp.GetAsyncOperation(), err
+		},
+	}
+	failoverNamespaceRegion = namespaceRegionAction{
+		name: "failing over namespace",
+		check: func(ns *namespace.Namespace, regionID string, _ []*region.Region) *preflight.Report {
+			return preflight.CheckFailover(ns, regionID)
+		},
+		submit: func(ctx context.Context, clientManager *clients.ClientManager, ns *namespace.Namespace, regionID string) (*operation.AsyncOperation, error) {
+			resp, err := callCloudService(ctx, clientManager, workflows.FailoverNamespaceRegionWorkflowType, &cloudservice.FailoverNamespaceRegionRequest{
+				Namespace:        ns.GetNamespace(),
+				Region:           regionID,
+				AsyncOperationId: uuid.New().String(),
+			}, clientManager.GetCloudClient().CloudService().FailoverNamespaceRegion)
+			return resp.GetAsyncOperation(), err
+		},
+	}
+)
+
+// handleNamespaceRegionChange checks the change against the namespace and the available regions,
+// submits it, waits for the operation and reports the region status afterwards
+func handleNamespaceRegionChange(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager, action namespaceRegionAction) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	namespaceID, errResult := requiredString(arguments, "namespace")
+	if errResult != nil {
+		return errResult, nil
+	}
+	regionID, errResult := requiredString(arguments, "region")
+	if errResult != nil {
+		return errResult, nil
+	}
+	namespaceID, errResult = resolveNamespace(ctx, clientManager, namespaceID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	ns, regions, err := getNamespaceAndRegions(ctx, clientManager, namespaceID)
+	if err != nil {
+		return cloudAPIErrorResult("Error getting namespace regions", err, "temporal_get_namespace"), nil
+	}
+	report := action.check(ns, regionID, regions)
+	result := map[string]interface{}{
+		"namespace": namespaceID,
+		"region":    regionID,
+		"checks":    report,
+		"before":    newNamespaceRegionStatus(ns, regions),
+	}
+	if validateOnly, _ := arguments["validate_only"].(bool); validateOnly || !report.Valid {
+		toolResult := jsonResult(result)
+		toolResult.IsError = !report.Valid
+		return toolResult, nil
+	}
+	for _, issue := range report.Issues {
+		logging.FromContext(ctx).Warn("namespace region warning",
+			zap.String("namespace", namespaceID), zap.String("region", regionID), zap.String("message", issue.Message))
+	}
+
+	op, err := action.submit(ctx, clientManager, ns, regionID)
+	if err != nil {
+		return cloudAPIErrorResult("Error "+action.name, err, "temporal_get_namespace_regions"), nil
+	}
+	operation, err := waitForAsyncOperation(ctx, clientManager, op.GetId(), asyncOperationTimeout(arguments))
+	if err != nil {
+		return cloudAPIErrorResult("Error waiting for "+action.name, err, "temporal_get_namespace_regions"), nil
+	}
+	result["async_operation"] = operation
+
+	ns, regions, err = getNamespaceAndRegions(ctx, clientManager, namespaceID)
+	if err != nil {
+		return cloudAPIErrorResult("Error getting namespace regions", err, "temporal_get_namespace_regions"), nil
+	}
+	result["after"] = newNamespaceRegionStatus(ns, regions)
+	return jsonResult(result), nil
+}
@@ -7,7 +7,7 @@ import (
 
 	"bechols/temcp/cmd/mcp-server/clients"
 	"bechols/temcp/cmd/mcp-server/config"
-
+	"bechols/temcp/workflows"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
@@ -18,7 +18,7 @@ func RegisterNamespaceServiceAccountAccessTools(mcpServer *server.MCPServer, cfg
 	mcpServer.AddTool(
 		mcp.NewTool("temporal_get_service_account_namespace_access",
 			mcp.WithDescription("Get namespace access permissions for a service account - for service accounts only, not users"),
-			mcp.WithString("service_account_id", mcp.Description("Service account ID"), mcp.Required()),
+			mcp.WithString("service_account_id", mcp.Description("Service account ID or name"), mcp.Required()),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return handleGetServiceAccountNamespaceAccess(ctx, request, clientManager)
@@ -28,8 +28,8 @@ func RegisterNamespaceServiceAccountAccessTools(mcpServer *server.MCPServer, cfg
 	mcpServer.AddTool(
 		mcp.NewTool("temporal_set_service_account_namespace_access",
 			mcp.WithDescription("Set namespace access permissions for a service account - for service accounts only, not users"),
-			mcp.WithString("service_account_id", mcp.Description("Service account ID"), mcp.Required()),
-			mcp.WithString("namespace", mcp.Description("Namespace name"), mcp.Required()),
+			mcp.WithString("service_account_id", mcp.Description("Service account ID or name"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
 			mcp.WithString("permission", mcp.Description("Permission level: admin, write, or read"), mcp.Required()),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -53,22 +53,20 @@ func handleGetServiceAccountNamespaceAccess(ctx context.Context, request mcp.Cal
 		}, nil
 	}
 
+	serviceAccountID, errResult := resolveServiceAccountID(ctx, clientManager, serviceAccountID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	getServiceAccountReq := &cloudservice.GetServiceAccountRequest{
 		ServiceAccountId: serviceAccountID,
 	}
 
-	cloudClient := clientManager.GetCloudClient()
-	result, err := cloudClient.CloudService().GetServiceAccount(ctx, getServiceAccountReq)
+	// Use workflow if Temporal client is available, otherwise call API directly
+	result, err := callCloudService(ctx, clientManager, workflows.GetServiceAccountWorkflowType, getServiceAccountReq,
+		clientManager.GetCloudClient().CloudService().GetServiceAccount)
 	if err != nil {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error getting service account: %v", err),
-				},
-			},
-		}, nil
+		return cloudAPIErrorResult("Error getting service account", err, "temporal_get_service_account_namespace_access"), nil
 	}
 
 	if result.ServiceAccount == nil || result.ServiceAccount.Spec == nil {
@@ -139,6 +137,11 @@ func handleSetServiceAccountNamespaceAccess(ctx context.Context, request mcp.Cal
 		}, nil
 	}
 
+	serviceAccountID, errResult := resolveServiceAccountID(ctx, clientManager, serviceAccountID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	namespace, ok := arguments["namespace"].(string)
 	if !ok || namespace == "" {
 		return &mcp.CallToolResult{
@@ -152,6 +155,11 @@ func handleSetServiceAccountNamespaceAccess(ctx context.Context, request mcp.Cal
 		}, nil
 	}
 
+	namespace, errResult = resolveNamespace(ctx, clientManager, namespace)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	permissionStr, ok := arguments["permission"].(string)
 	if !ok || permissionStr == "" {
 		return &mcp.CallToolResult{
@@ -185,20 +193,12 @@ func handleSetServiceAccountNamespaceAccess(ctx context.Context, request mcp.Cal
 		}, nil
 	}
 
-	cloudClient := clientManager.GetCloudClient()
-	getResult, err := cloudClient.CloudService().GetServiceAccount(ctx, &cloudservice.GetServiceAccountRequest{
+	cloudService := clientManager.GetCloudClient().CloudService()
+	getResult, err := callCloudService(ctx, clientManager, workflows.GetServiceAccountWorkflowType, &cloudservice.GetServiceAccountRequest{
 		ServiceAccountId: serviceAccountID,
-	})
+	}, cloudService.GetServiceAccount)
 	if err != nil {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error getting service account: %v", err),
-				},
-			},
-		}, nil
+		return cloudAPIErrorResult("Error getting service account", err, "temporal_get_service_account_namespace_access"), nil
 	}
 
 	if getResult.ServiceAccount == nil || getResult.ServiceAccount.Spec == nil {
@@ -232,17 +232,10 @@ func handleSetServiceAccountNamespaceAccess(ctx context.Context, request mcp.Cal
 		ResourceVersion:  getResult.ServiceAccount.ResourceVersion,
 	}
 
-	result, err := cloudClient.CloudService().UpdateServiceAccount(ctx, updateReq)
+	result, err := callCloudService(ctx, clientManager, workflows.UpdateServiceAccountWorkflowType, updateReq,
+		cloudService.UpdateServiceAccount)
 	if err != nil {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error updating service account namespace access: %v", err),
-				},
-			},
-		}, nil
+		return cloudAPIErrorResult("Error updating service account namespace access", err, "temporal_get_service_account_namespace_access"), nil
 	}
 
 	resultJSON, err := json.MarshalIndent(result, "", "  ")
@@ -0,0 +1,384 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"bechols/temcp/cmd/mcp-server/clients"
+	"bechols/temcp/cmd/mcp-server/config"
+	"bechols/temcp/cmd/mcp-server/resolver"
+	"bechols/temcp/internal/nexusendpoint"
+	"bechols/temcp/internal/preflight"
+	"bechols/temcp/workflows"
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+	"go.temporal.io/cloud-sdk/api/nexus/v1"
+)
+
+// RegisterNexusEndpointTools registers the Nexus endpoint tools with the MCP server
+func RegisterNexusEndpointTools(mcpServer *server.MCPServer, cfg *config.Config, clientManager *clients.ClientManager) {
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_list_nexus_endpoints",
+			mcp.WithDescription("List the account's Nexus endpoints with their targets and allowed caller namespaces"),
+			mcp.WithString("name", mcp.Description("Only the endpoint with this name (optional)")),
+			mcp.WithString("target_namespace", mcp.Description("Only endpoints targeting this namespace ID (name.account) or name (optional)")),
+			mcp.WithString("target_task_queue", mcp.Description("Only endpoints targeting this task queue, requires target_namespace (optional)")),
+			mcp.WithNumber("page_size", mcp.Description("Number of endpoints per page (optional)")),
+			mcp.WithString("page_token", mcp.Description("Page token from a previous call (optional)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleListNexusEndpoints(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_get_nexus_endpoint",
+			mcp.WithDescription("Get a Nexus endpoint with its target, allowed caller namespaces and description"),
+			mcp.WithString("endpoint", mcp.Description("Endpoint ID or name"), mcp.Required()),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetNexusEndpoint(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_create_nexus_endpoint",
+			mcp.WithDescription("Create a Nexus endpoint that routes operations to a task queue in a target namespace. Checks that the target and caller namespaces exist, then waits for the endpoint to be created."),
+			mcp.WithString("name", mcp.Description("Endpoint name: letters, digits and hyphens, starting with a letter"), mcp.Required()),
+			mcp.WithString("target_namespace", mcp.Description("Namespace ID (name.account) or name whose workers handle the endpoint's operations"), mcp.Required()),
+			mcp.WithString("target_task_queue", mcp.Description("Task queue the target namespace's workers poll"), mcp.Required()),
+			mcp.WithString("allowed_caller_namespaces", mcp.Description("Comma-separated namespace IDs or names allowed to call the endpoint (optional)")),
+			mcp.WithString("description", mcp.Description("Markdown description shown in the Cloud UI (optional)")),
+			mcp.WithBoolean("validate_only", mcp.Description("Only check the endpoint, don't create it (optional, default: false)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the endpoint to be created in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleCreateNexusEndpoint(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_update_nexus_endpoint",
+			mcp.WithDescription("Update a Nexus endpoint. Only the fields given change; allowed_caller_namespaces replaces the whole list. Checks that the target and caller namespaces exist, then waits for the update."),
+			mcp.WithString("endpoint", mcp.Description("Endpoint ID or name"), mcp.Required()),
+			mcp.WithString("name", mcp.Description("New endpoint name (optional)")),
+			mcp.WithString("target_namespace", mcp.Description("New target namespace ID (name.account) or name (optional)")),
+			mcp.WithString("target_task_queue", mcp.Description("New target task queue (optional)")),
+			mcp.WithString("allowed_caller_namespaces", mcp.Description("Comma-separated namespace IDs or names allowed to call the endpoint, replacing the current list; pass an empty string to allow none (optional)")),
+			mcp.WithString("description", mcp.Description("New markdown description (optional)")),
+			mcp.WithString("resource_version", mcp.Description("Resource version of the endpoint (optional, defaults to the current version)")),
+			mcp.WithBoolean("validate_only", mcp.Description("Only check the updated endpoint, don't update it (optional, default: false)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the update in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleUpdateNexusEndpoint(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_delete_nexus_endpoint",
+			mcp.WithDescription("Delete a Nexus endpoint. Callers using it start failing as soon as it is gone."),
+			mcp.WithString("endpoint", mcp.Description("Endpoint ID or name"), mcp.Required()),
+			mcp.WithString("resource_version", mcp.Description("Resource version of the endpoint (optional, defaults to the current version)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the endpoint to be deleted in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleDeleteNexusEndpoint(ctx, request, clientManager)
+		},
+	)
+}
+
+// nexusEndpointSummary is how the Nexus endpoint tools present an endpoint. The target and policies are
+// oneofs and the description a payload in the Cloud API, so the spec is shown as a flat config instead.
+type nexusEndpointSummary struct {
+	ID               string               `json:"id"`
+	Endpoint         nexusendpoint.Config `json:"endpoint"`
+	State            string               `json:"state"`
+	ResourceVersion  string               `json:"resource_version"`
+	AsyncOperationID string               `json:"async_operation_id,omitempty"`
+	CreatedTime      string               `json:"created_time,omitempty"`
+	LastModifiedTime string               `json:"last_modified_time,omitempty"`
+}
+
+func summarizeNexusEndpoint(endpoint *nexus.Endpoint) nexusEndpointSummary {
+	summary := nexusEndpointSummary{
+		ID:               endpoint.GetId(),
+		Endpoint:         nexusendpoint.FromSpec(endpoint.GetSpec()),
+		State:            endpoint.GetState().String(),
+		ResourceVersion:  endpoint.GetResourceVersion(),
+		AsyncOperationID: endpoint.GetAsyncOperationId(),
+	}
+	if endpoint.CreatedTime != nil {
+		summary.CreatedTime = endpoint.CreatedTime.AsTime().Format(time.RFC3339)
+	}
+	if endpoint.LastModifiedTime != nil {
+		summary.LastModifiedTime = endpoint.LastModifiedTime.AsTime().Format(time.RFC3339)
+	}
+	return summary
+}
+
+// getNexusEndpoint resolves an endpoint ID or name and fetches the endpoint
+func getNexusEndpoint(ctx context.Context, clientManager *clients.ClientManager, ref string) (*nexus.Endpoint, *mcp.CallToolResult) {
+	endpoint, err := resolver.New(clientManager.GetCloudClient()).NexusEndpoint(ctx, ref)
+	if err != nil {
+		return nil, cloudAPIErrorResult("Error resolving Nexus endpoint", err, "temporal_list_nexus_endpoints")
+	}
+	resp, err := callCloudService(ctx, clientManager, workflows.GetNexusEndpointWorkflowType,
+		&cloudservice.GetNexusEndpointRequest{EndpointId: endpoint.Id},
+		clientManager.GetCloudClient().CloudService().GetNexusEndpoint)
+	if err != nil {
+		return nil, cloudAPIErrorResult("Error getting Nexus endpoint", err, "temporal_list_nexus_endpoints")
+	}
+	return resp.GetEndpoint(), nil
+}
+
+// resolveCallerNamespaces resolves a comma-separated list of namespace IDs or names
+func resolveCallerNamespaces(ctx context.Context, clientManager *clients.ClientManager, list string) ([]string, *mcp.CallToolResult) {
+	namespaces := []string{}
+	for _, ref := range strings.Split(list, ",") {
+		if ref = strings.TrimSpace(ref); ref == "" {
+			continue
+		}
+		namespaceID, errResult := resolveNamespace(ctx, clientManager, ref)
+		if errResult != nil {
+			return nil, errResult
+		}
+		namespaces = append(namespaces, namespaceID)
+	}
+	return namespaces, nil
+}
+
+// accountNamespaceIDs lists the IDs of all namespaces in the account
+func accountNamespaceIDs(ctx context.Context, clientManager *clients.ClientManager) ([]string, error) {
+	var (
+		ids       []string
+		pageToken string
+	)
+	for {
+		resp, err := callCloudService(ctx, clientManager, workflows.GetNamespacesWorkflowType,
+			&cloudservice.GetNamespacesRequest{PageToken: pageToken},
+			clientManager.GetCloudClient().CloudService().GetNamespaces)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range resp.GetNamespaces() {
+			ids = append(ids, ns.GetNamespace())
+		}
+		if resp.GetNextPageToken() == "" {
+			return ids, nil
+		}
+		pageToken = resp.GetNextPageToken()
+	}
+}
+
+// checkNexusEndpointConfig builds the spec of the config and checks it against the account's namespaces.
+// The error result is set when the spec can't be built or checked, or when the check fails or validateOnly is set.
+func checkNexusEndpointConfig(ctx context.Context, clientManager *clients.ClientManager, c nexusendpoint.Config, validateOnly bool) (*nexus.EndpointSpec, *mcp.CallToolResult) {
+	spec, err := c.Spec()
+	if err != nil {
+		return nil, errorResult("Error: %v", err)
+	}
+	namespaces, err := accountNamespaceIDs(ctx, clientManager)
+	if err != nil {
+		return nil, cloudAPIErrorResult("Error listing namespaces", err, "")
+	}
+	if errResult := invalidReportResult(preflight.CheckNexusEndpoint(spec, namespaces), validateOnly); errResult != nil {
+		return nil, errResult
+	}
+	return spec, nil
+}
+
+func handleListNexusEndpoints(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+
+	listReq := &cloudservice.GetNexusEndpointsRequest{
+		Name:            optionalString(arguments, "name"),
+		TargetTaskQueue: optionalString(arguments, "target_task_queue"),
+		PageToken:       optionalString(arguments, "page_token"),
+	}
+	if ps, ok := arguments["page_size"].(float64); ok {
+		listReq.PageSize = int32(ps)
+	}
+	if target := optionalString(arguments, "target_namespace"); target != "" {
+		target, errResult := resolveNamespace(ctx, clientManager, target)
+		if errResult != nil {
+			return errResult, nil
+		}
+		listReq.TargetNamespaceId = target
+	} else if listReq.TargetTaskQueue != "" {
+		return errorResult("Error: target_task_queue requires target_namespace"), nil
+	}
+
+	resp, err := callCloudService(ctx, clientManager, workflows.GetNexusEndpointsWorkflowType, listReq,
+		clientManager.GetCloudClient().CloudService().GetNexusEndpoints)
+	if err != nil {
+		return cloudAPIErrorResult("Error listing Nexus endpoints", err, ""), nil
+	}
+
+	endpoints := make([]nexusEndpointSummary, 0, len(resp.Endpoints))
+	for _, endpoint := range resp.Endpoints {
+		endpoints = append(endpoints, summarizeNexusEndpoint(endpoint))
+	}
+	return jsonResult(map[string]interface{}{
+		"endpoints":       endpoints,
+		"next_page_token": resp.NextPageToken,
+	}), nil
+}
+
+func handleGetNexusEndpoint(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	ref, errResult := requiredString(request.GetArguments(), "endpoint")
+	if errResult != nil {
+		return errResult, nil
+	}
+	endpoint, errResult := getNexusEndpoint(ctx, clientManager, ref)
+	if errResult != nil {
+		return errResult, nil
+	}
+	return jsonResult(summarizeNexusEndpoint(endpoint)), nil
+}
+
+func handleCreateNexusEndpoint(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	c := nexusendpoint.Config{Description: optionalString(arguments, "description")}
+	var errResult *mcp.CallToolResult
+	if c.Name, errResult = requiredString(arguments, "name"); errResult != nil {
+		return errResult, nil
+	}
+	if c.TargetTaskQueue, errResult = requiredString(arguments, "target_task_queue"); errResult != nil {
+		return errResult, nil
+	}
+	target, errResult := requiredString(arguments, "target_namespace")
+	if errResult != nil {
+		return errResult, nil
+	}
+	if c.TargetNamespace, errResult = resolveNamespace(ctx, clientManager, target); errResult != nil {
+		return errResult, nil
+	}
+	if c.AllowedCallerNamespaces, errResult = resolveCallerNamespaces(ctx, clientManager, optionalString(arguments, "allowed_caller_namespaces")); errResult != nil {
+		return errResult, nil
+	}
+	validateOnly, _ := arguments["validate_only"].(bool)
+	spec, errResult := checkNexusEndpointConfig(ctx, clientManager, c, validateOnly)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	createReq := &cloudservice.CreateNexusEndpointRequest{
+		Spec:             spec,
+		AsyncOperationId: uuid.New().String(),
+	}
+	createResp, err := callCloudService(ctx, clientManager, workflows.CreateNexusEndpointWorkflowType, createReq,
+		clientManager.GetCloudClient().CloudService().CreateNexusEndpoint)
+	if err != nil {
+		return cloudAPIErrorResult("Error creating Nexus endpoint", err, "temporal_list_nexus_endpoints"), nil
+	}
+	return nexusEndpointChangeResult(ctx, clientManager, arguments, createResp.GetEndpointId(), createResp.GetAsyncOperation().GetId(), true)
+}
+
+func handleUpdateNexusEndpoint(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	ref, errResult := requiredString(arguments, "endpoint")
+	if errResult != nil {
+		return errResult, nil
+	}
+	endpoint, errResult := getNexusEndpoint(ctx, clientManager, ref)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	c := nexusendpoint.FromSpec(endpoint.GetSpec())
+	if name := optionalString(arguments, "name"); name != "" {
+		c.Name = name
+	}
+	if target := optionalString(arguments, "target_namespace"); target != "" {
+		if c.TargetNamespace, errResult = resolveNamespace(ctx, clientManager, target); errResult != nil {
+			return errResult, nil
+		}
+	}
+	if taskQueue := optionalString(arguments, "target_task_queue"); taskQueue != "" {
+		c.TargetTaskQueue = taskQueue
+	}
+	if callers, ok := arguments["allowed_caller_namespaces"].(string); ok {
+		if c.AllowedCallerNamespaces, errResult = resolveCallerNamespaces(ctx, clientManager, callers); errResult != nil {
+			return errResult, nil
+		}
+	}
+	if description, ok := arguments["description"].(string); ok {
+		c.Description = description
+	}
+	validateOnly, _ := arguments["validate_only"].(bool)
+	spec, errResult := checkNexusEndpointConfig(ctx, clientManager, c, validateOnly)
+	if errResult != nil {
+		return errResult, nil
+	}
+	resourceVersion := optionalString(arguments, "resource_version")
+	if resourceVersion == "" {
+		resourceVersion = endpoint.GetResourceVersion()
+	}
+
+	updateReq := &cloudservice.UpdateNexusEndpointRequest{
+		EndpointId:       endpoint.GetId(),
+		Spec:             spec,
+		ResourceVersion:  resourceVersion,
+		AsyncOperationId: uuid.New().String(),
+	}
+	updateResp, err := callCloudService(ctx, clientManager, workflows.UpdateNexusEndpointWorkflowType, updateReq,
+		clientManager.GetCloudClient().CloudService().UpdateNexusEndpoint)
+	if err != nil {
+		return cloudAPIErrorResult("Error updating Nexus endpoint", err, "temporal_get_nexus_endpoint"), nil
+	}
+	return nexusEndpointChangeResult(ctx, clientManager, arguments, endpoint.GetId(), updateResp.GetAsyncOperation().GetId(), true)
+}
+
+func handleDeleteNexusEndpoint(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	ref, errResult := requiredString(arguments, "endpoint")
+	if errResult != nil {
+		return errResult, nil
+	}
+	endpoint, errResult := getNexusEndpoint(ctx, clientManager, ref)
+	if errResult != nil {
+		return errResult, nil
+	}
+	resourceVersion := optionalString(arguments, "resource_version")
+	if resourceVersion == "" {
+		resourceVersion = endpoint.GetResourceVersion()
+	}
+
+	deleteReq := &cloudservice.DeleteNexusEndpointRequest{
+		EndpointId:       endpoint.GetId(),
+		ResourceVersion:  resourceVersion,
+		AsyncOperationId: uuid.New().String(),
+	}
+	deleteResp, err := callCloudService(ctx, clientManager, workflows.DeleteNexusEndpointWorkflowType, deleteReq,
+		clientManager.GetCloudClient().CloudService().DeleteNexusEndpoint)
+	if err != nil {
+		return cloudAPIErrorResult("Error deleting Nexus endpoint", err, "temporal_get_nexus_endpoint"), nil
+	}
+	return nexusEndpointChangeResult(ctx, clientManager, arguments, endpoint.GetId(), deleteResp.GetAsyncOperation().GetId(), false)
+}
+
+// nexusEndpointChangeResult waits for the change and returns the operation, and the endpoint afterwards unless it was deleted
+func nexusEndpointChangeResult(ctx context.Context, clientManager *clients.ClientManager, arguments map[string]interface{}, endpointID, operationID string, refetch bool) (*mcp.CallToolResult, error) {
+	operation, err := waitForAsyncOperation(ctx, clientManager, operationID, asyncOperationTimeout(arguments))
+	if err != nil {
+		return cloudAPIErrorResult("Error waiting for Nexus endpoint change", err, "temporal_get_nexus_endpoint"), nil
+	}
+	result := map[string]interface{}{
+		"endpoint_id":     endpointID,
+		"async_operation": operation,
+	}
+	if refetch {
+		resp, err := callCloudService(ctx, clientManager, workflows.GetNexusEndpointWorkflowType,
+			&cloudservice.GetNexusEndpointRequest{EndpointId: endpointID},
+			clientManager.GetCloudClient().CloudService().GetNexusEndpoint)
+		if err != nil {
+			return cloudAPIErrorResult("Error getting Nexus endpoint", err, "temporal_get_nexus_endpoint"), nil
+		}
+		result["endpoint"] = summarizeNexusEndpoint(resp.GetEndpoint())
+	}
+	return jsonResult(result), nil
+}
@@ -72,15 +72,7 @@ func handleGetAsyncOperationImpl(ctx context.Context, request mcp.CallToolReques
 		result, err = cloudClient.CloudService().GetAsyncOperation(ctx, getOpReq)
 	}
 	if err != nil {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error getting async operation: %v", err),
-				},
-			},
-		}, nil
+		return cloudAPIErrorResult("Error getting async operation", err, ""), nil
 	}
 
 	resultJSON, err := json.MarshalIndent(result, "", "  ")
@@ -127,71 +119,9 @@ func handleWaitForOperationImpl(ctx context.Context, request mcp.CallToolRequest
 		timeoutSeconds = ts
 	}
 
-	var result interface{}
-	var err error
-
-	// Use workflow if Temporal client is available, otherwise implement polling directly
-	if clientManager.GetTemporalClient() != nil {
-		// Use the existing WaitForAsyncOperation workflow
-		waitInput := &workflows.WaitForAsyncOperationInput{
-			AsyncOperationID: operationID,
-			Timeout:          time.Duration(timeoutSeconds) * time.Second,
-		}
-		result, err = clientManager.ExecuteWorkflowWithTimeout(ctx, workflows.WaitForAsyncOperationType, waitInput, time.Duration(timeoutSeconds+30)*time.Second)
-	} else {
-		// Implement polling logic directly
-		cloudClient := clientManager.GetCloudClient()
-		getOpReq := &cloudservice.GetAsyncOperationRequest{
-			AsyncOperationId: operationID,
-		}
-
-		// Set up timeout context
-		timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(timeoutSeconds)*time.Second)
-		defer cancel()
-
-		// Poll until complete or timeout
-		for {
-			opResult, pollErr := cloudClient.CloudService().GetAsyncOperation(timeoutCtx, getOpReq)
-			if pollErr != nil {
-				err = pollErr
-				break
-			}
-
-			// Check if operation is complete
-			if opResult.AsyncOperation.State != operation.AsyncOperation_STATE_PENDING &&
-				opResult.AsyncOperation.State != operation.AsyncOperation_STATE_IN_PROGRESS {
-				// Operation completed (success or failure)
-				result = opResult
-				break
-			}
-
-			// Wait before next poll
-			select {
-			case <-timeoutCtx.Done():
-				return &mcp.CallToolResult{
-					IsError: true,
-					Content: []mcp.Content{
-						mcp.TextContent{
-							Type: "text",
-							Text: "Timeout waiting for async operation to complete",
-						},
-					},
-				}, nil
-			case <-time.After(2 * time.Second):
-				// Continue polling
-			}
-		}
-	}
+	result, err := waitForAsyncOperation(ctx, clientManager, operationID, time.Duration(timeoutSeconds)*time.Second)
 	if err != nil {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error waiting for async operation: %v", err),
-				},
-			},
-		}, nil
+		return cloudAPIErrorResult("Error waiting for async operation", err, ""), nil
 	}
 
 	resultJSON, err := json.MarshalIndent(result, "", "  ")
@@ -216,3 +146,59 @@ func handleWaitForOperationImpl(ctx context.Context, request mcp.CallToolRequest
 		},
 	}, nil
 }
+
+// defaultAsyncOperationTimeout is how long tools that change a resource wait for its async operation by default
+const defaultAsyncOperationTimeout = 5 * time.Minute
+
+// asyncOperationTimeout returns the timeout_seconds argument, or the default timeout if it is not set
+func asyncOperationTimeout(arguments map[string]interface{}) time.Duration {
+	if ts, ok := arguments["timeout_seconds"].(float64); ok && ts > 0 {
+		return time.Duration(ts) * time.Second
+	}
+	return defaultAsyncOperationTimeout
+}
+
+// waitForAsyncOperation waits for an async operation to leave the pending and in-progress states,
+// through the WaitForAsyncOperation workflow if a Temporal client is available, otherwise by polling
+func waitForAsyncOperation(ctx context.Context, clientManager *clients.ClientManager, operationID string, timeout time.Duration) (interface{}, error) {
+	if clientManager.GetTemporalClient() != nil {
+		waitInput := &workflows.WaitForAsyncOperationInput{
+			AsyncOperationID: operationID,
+			Timeout:          timeout,
+		}
+		return clientManager.ExecuteWorkflowWithTimeout(ctx, workflows.WaitForAsyncOperationType, waitInput, timeout+30*time.Second)
+	}
+
+	cloudClient := clientManager.GetCloudClient()
+	getOpReq := &cloudservice.GetAsyncOperationRequest{
+		AsyncOperationId: operationID,
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Poll until complete or timeout
+	for {
+		opResult, err := cloudClient.CloudService().GetAsyncOperation(timeoutCtx, getOpReq)
+		if err != nil {
+			return nil, err
+		}
+		// Fail the same way the WaitForAsyncOperation workflow does
+		switch opResult.AsyncOperation.State {
+		case operation.AsyncOperation_STATE_FAILED:
+			return nil, fmt.Errorf("request failed: %s", opResult.AsyncOperation.FailureReason)
+		case operation.AsyncOperation_STATE_CANCELLED:
+			return nil, fmt.Errorf("request cancelled: %s", opResult.AsyncOperation.FailureReason)
+		case operation.AsyncOperation_STATE_PENDING, operation.AsyncOperation_STATE_IN_PROGRESS:
+		default:
+			return opResult, nil
+		}
+
+		select {
+		case <-timeoutCtx.Done():
+			return nil, fmt.Errorf("timed out waiting for async operation %s after %s", operationID, timeout)
+		case <-time.After(2 * time.Second):
+			// Continue polling
+		}
+	}
+}
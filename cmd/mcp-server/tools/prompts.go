@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RegisterPrompts registers prompts for common access and namespace tasks.
+// Their arguments are completed by the completion provider.
+func RegisterPrompts(mcpServer *server.MCPServer) {
+	mcpServer.AddPrompt(
+		mcp.NewPrompt("grant_namespace_access",
+			mcp.WithPromptDescription("Grant a user access to a namespace"),
+			mcp.WithArgument("user_id", mcp.ArgumentDescription("User ID, complete by typing an email"), mcp.RequiredArgument()),
+			mcp.WithArgument("namespace", mcp.ArgumentDescription("Namespace"), mcp.RequiredArgument()),
+			mcp.WithArgument("permission", mcp.ArgumentDescription("Permission level: admin, write, or read"), mcp.RequiredArgument()),
+		),
+		func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			args := request.Params.Arguments
+			return promptResult("Grant namespace access", fmt.Sprintf(
+				"Check the current access of user %s on namespace %s with temporal_get_user_namespace_access, "+
+					"then set it to %s with temporal_set_user_namespace_access. Report the access before and after.",
+				args["user_id"], args["namespace"], args["permission"],
+			)), nil
+		},
+	)
+
+	mcpServer.AddPrompt(
+		mcp.NewPrompt("review_user_access",
+			mcp.WithPromptDescription("Summarize what a user has access to"),
+			mcp.WithArgument("user_id", mcp.ArgumentDescription("User ID, complete by typing an email"), mcp.RequiredArgument()),
+		),
+		func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return promptResult("Review user access", fmt.Sprintf(
+				"Use temporal_get_user and temporal_get_account_access to summarize the account role of user %s "+
+					"and every namespace they can access, with the permission on each.",
+				request.Params.Arguments["user_id"],
+			)), nil
+		},
+	)
+
+	mcpServer.AddPrompt(
+		mcp.NewPrompt("describe_namespace",
+			mcp.WithPromptDescription("Describe how a namespace is configured"),
+			mcp.WithArgument("namespace", mcp.ArgumentDescription("Namespace"), mcp.RequiredArgument()),
+		),
+		func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return promptResult("Describe namespace", fmt.Sprintf(
+				"Use temporal_get_namespace to describe namespace %s: regions, retention, authentication "+
+					"(API keys and/or mTLS), endpoints and custom search attributes.",
+				request.Params.Arguments["namespace"],
+			)), nil
+		},
+	)
+
+	mcpServer.AddPrompt(
+		mcp.NewPrompt("grant_service_account_access",
+			mcp.WithPromptDescription("Grant a service account access to a namespace"),
+			mcp.WithArgument("service_account_id", mcp.ArgumentDescription("Service account ID, complete by typing a name"), mcp.RequiredArgument()),
+			mcp.WithArgument("namespace", mcp.ArgumentDescription("Namespace"), mcp.RequiredArgument()),
+			mcp.WithArgument("permission", mcp.ArgumentDescription("Permission level: admin, write, or read"), mcp.RequiredArgument()),
+		),
+		func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			args := request.Params.Arguments
+			return promptResult("Grant service account access", fmt.Sprintf(
+				"Set the access of service account %s on namespace %s to %s with temporal_set_service_account_namespace_access, "+
+					"then confirm it with temporal_get_service_account_namespace_access.",
+				args["service_account_id"], args["namespace"], args["permission"],
+			)), nil
+		},
+	)
+}
+
+func promptResult(description, text string) *mcp.GetPromptResult {
+	return mcp.NewGetPromptResult(description, []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+	})
+}
@@ -67,15 +67,7 @@ func handleGetRegionImpl(ctx context.Context, request mcp.CallToolRequest, clien
 		result, err = clientManager.GetCloudClient().CloudService().GetRegion(ctx, getRegionReq)
 	}
 	if err != nil {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error getting region: %v", err),
-				},
-			},
-		}, nil
+		return cloudAPIErrorResult("Error getting region", err, ""), nil
 	}
 
 	resultJSON, err := json.MarshalIndent(result, "", "  ")
@@ -116,15 +108,7 @@ func handleListRegionsImpl(ctx context.Context, clientManager *clients.ClientMan
 	}
 
 	if err != nil {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error listing regions: %v", err),
-				},
-			},
-		}, nil
+		return cloudAPIErrorResult("Error listing regions", err, ""), nil
 	}
 
 	resultJSON, err := json.MarshalIndent(result, "", "  ")
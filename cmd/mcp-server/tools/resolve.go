@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"context"
+
+	"bechols/temcp/cmd/mcp-server/clients"
+	"bechols/temcp/cmd/mcp-server/resolver"
+	"github.com/mark3labs/mcp-go/mcp"
+	identityv1 "go.temporal.io/cloud-sdk/api/identity/v1"
+)
+
+// The resolve helpers accept what a person would type for an argument (an email, a name, a short
+// namespace name) as well as the ID, and return the ID or an error result for the tool to return.
+
+func resolveUserID(ctx context.Context, clientManager *clients.ClientManager, ref string) (string, *mcp.CallToolResult) {
+	id, err := resolver.New(clientManager.GetCloudClient()).UserID(ctx, ref)
+	if err != nil {
+		return "", cloudAPIErrorResult("Error resolving user", err, "")
+	}
+	return id, nil
+}
+
+func resolveServiceAccountID(ctx context.Context, clientManager *clients.ClientManager, ref string) (string, *mcp.CallToolResult) {
+	id, err := resolver.New(clientManager.GetCloudClient()).ServiceAccountID(ctx, ref)
+	if err != nil {
+		return "", cloudAPIErrorResult("Error resolving service account", err, "")
+	}
+	return id, nil
+}
+
+func resolveOwnerID(ctx context.Context, clientManager *clients.ClientManager, ownerType identityv1.OwnerType, ref string) (string, *mcp.CallToolResult) {
+	id, err := resolver.New(clientManager.GetCloudClient()).Owner(ctx, ownerType, ref)
+	if err != nil {
+		return "", cloudAPIErrorResult("Error resolving API key owner", err, "")
+	}
+	return id, nil
+}
+
+func resolveNamespace(ctx context.Context, clientManager *clients.ClientManager, ref string) (string, *mcp.CallToolResult) {
+	ns, err := resolver.New(clientManager.GetCloudClient()).Namespace(ctx, ref)
+	if err != nil {
+		return "", cloudAPIErrorResult("Error resolving namespace", err, "")
+	}
+	return ns, nil
+}
+
+func resolveApiKey(ctx context.Context, clientManager *clients.ClientManager, ref string) (*identityv1.ApiKey, *mcp.CallToolResult) {
+	key, err := resolver.New(clientManager.GetCloudClient()).ApiKey(ctx, ref)
+	if err != nil {
+		return nil, cloudAPIErrorResult("Error resolving API key", err, "")
+	}
+	return key, nil
+}
@@ -0,0 +1,120 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"bechols/temcp/cmd/mcp-server/clients"
+	"bechols/temcp/cmd/mcp-server/resolver"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+)
+
+// RegisterResourceTemplates registers read-only resource templates for Cloud resources.
+// Their arguments are completed by the completion provider, and accept emails and names as well as IDs.
+func RegisterResourceTemplates(mcpServer *server.MCPServer, clientManager *clients.ClientManager) {
+	mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate("temporal-cloud://namespaces/{namespace}", "Namespace",
+			mcp.WithTemplateDescription("A Temporal Cloud namespace"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			namespace, err := resolver.New(clientManager.GetCloudClient()).Namespace(ctx, templateArgument(request, "namespace"))
+			if err != nil {
+				return nil, err
+			}
+			resp, err := clientManager.GetCloudClient().CloudService().GetNamespace(ctx, &cloudservice.GetNamespaceRequest{
+				Namespace: namespace,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return jsonResourceContents(request.Params.URI, resp.Namespace)
+		},
+	)
+
+	mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate("temporal-cloud://users/{user_id}", "User",
+			mcp.WithTemplateDescription("A Temporal Cloud user"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			userID, err := resolver.New(clientManager.GetCloudClient()).UserID(ctx, templateArgument(request, "user_id"))
+			if err != nil {
+				return nil, err
+			}
+			resp, err := clientManager.GetCloudClient().CloudService().GetUser(ctx, &cloudservice.GetUserRequest{
+				UserId: userID,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return jsonResourceContents(request.Params.URI, resp.User)
+		},
+	)
+
+	mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate("temporal-cloud://service-accounts/{service_account_id}", "Service account",
+			mcp.WithTemplateDescription("A Temporal Cloud service account"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			serviceAccountID, err := resolver.New(clientManager.GetCloudClient()).ServiceAccountID(ctx, templateArgument(request, "service_account_id"))
+			if err != nil {
+				return nil, err
+			}
+			resp, err := clientManager.GetCloudClient().CloudService().GetServiceAccount(ctx, &cloudservice.GetServiceAccountRequest{
+				ServiceAccountId: serviceAccountID,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return jsonResourceContents(request.Params.URI, resp.ServiceAccount)
+		},
+	)
+
+	mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate("temporal-cloud://regions/{region}", "Region",
+			mcp.WithTemplateDescription("A Temporal Cloud region"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			resp, err := clientManager.GetCloudClient().CloudService().GetRegion(ctx, &cloudservice.GetRegionRequest{
+				Region: templateArgument(request, "region"),
+			})
+			if err != nil {
+				return nil, err
+			}
+			return jsonResourceContents(request.Params.URI, resp.Region)
+		},
+	)
+}
+
+// templateArgument returns a variable matched from the resource URI template
+func templateArgument(request mcp.ReadResourceRequest, name string) string {
+	switch v := request.Params.Arguments[name].(type) {
+	case string:
+		return v
+	case []string:
+		if len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+func jsonResourceContents(uri string, v any) ([]mcp.ResourceContents, error) {
+	text, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error serializing resource: %w", err)
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     string(text),
+		},
+	}, nil
+}
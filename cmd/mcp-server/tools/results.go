@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// errorResult returns a tool error with a formatted message
+func errorResult(format string, args ...interface{}) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: fmt.Sprintf(format, args...),
+			},
+		},
+	}
+}
+
+// jsonResult returns v serialized as indented JSON
+func jsonResult(v interface{}) *mcp.CallToolResult {
+	resultJSON, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errorResult("Error serializing result: %v", err)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: string(resultJSON),
+			},
+		},
+	}
+}
+
+// textResult returns text as is, for results that are already rendered
+func textResult(text string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+	}
+}
+
+// writeOutputFile writes a rendered report to a local file readable by the owner only. An existing file is kept
+// unless overwrite is set, so a mistyped path can't clobber it.
+func writeOutputFile(path string, data []byte, overwrite bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if overwrite {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%s already exists, pass overwrite to replace it", path)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// requiredString returns a required string argument, or an error result if it is missing or empty
+func requiredString(arguments map[string]interface{}, name string) (string, *mcp.CallToolResult) {
+	value, ok := arguments[name].(string)
+	if !ok || value == "" {
+		return "", errorResult("Error: %s is required and must be a string", name)
+	}
+	return value, nil
+}
+
+// optionalString returns a string argument, or "" if it is not set
+func optionalString(arguments map[string]interface{}, name string) string {
+	value, _ := arguments[name].(string)
+	return value
+}
@@ -0,0 +1,234 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"bechols/temcp/cmd/mcp-server/clients"
+	"bechols/temcp/cmd/mcp-server/config"
+	"bechols/temcp/internal/apierrors"
+	"bechols/temcp/internal/preflight"
+	"bechols/temcp/workflows"
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+	"go.temporal.io/cloud-sdk/api/namespace/v1"
+)
+
+// RegisterSearchAttributeTools registers the custom search attribute tools with the MCP server
+func RegisterSearchAttributeTools(mcpServer *server.MCPServer, cfg *config.Config, clientManager *clients.ClientManager) {
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_list_search_attributes",
+			mcp.WithDescription("List the custom search attributes of a namespace with their types"),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleListSearchAttributes(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_add_search_attribute",
+			mcp.WithDescription("Add one custom search attribute to a namespace, keeping the rest of its spec as is. Waits for the update and returns the namespace's search attributes."),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+			mcp.WithString("name", mcp.Description("Search attribute name: a letter followed by letters, digits and underscores, not a system search attribute"), mcp.Required()),
+			mcp.WithString("type", mcp.Description("Search attribute type: Text, Keyword, Int, Double, Bool, Datetime or KeywordList"), mcp.Required()),
+			mcp.WithBoolean("validate_only", mcp.Description("Only check the name and type, don't add the search attribute (optional, default: false)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the update in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleAddSearchAttribute(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_rename_search_attribute",
+			mcp.WithDescription("Rename a custom search attribute of a namespace. Its type and the values already indexed are kept. Waits for the rename and returns the namespace's search attributes."),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+			mcp.WithString("name", mcp.Description("Current search attribute name"), mcp.Required()),
+			mcp.WithString("new_name", mcp.Description("New search attribute name"), mcp.Required()),
+			mcp.WithBoolean("validate_only", mcp.Description("Only check the names, don't rename the search attribute (optional, default: false)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the rename in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleRenameSearchAttribute(ctx, request, clientManager)
+		},
+	)
+}
+
+// getNamespaceForSearchAttributes resolves the namespace argument and fetches the namespace
+func getNamespaceForSearchAttributes(ctx context.Context, clientManager *clients.ClientManager, arguments map[string]interface{}) (*namespace.Namespace, *mcp.CallToolResult) {
+	namespaceID, errResult := requiredString(arguments, "namespace")
+	if errResult != nil {
+		return nil, errResult
+	}
+	namespaceID, errResult = resolveNamespace(ctx, clientManager, namespaceID)
+	if errResult != nil {
+		return nil, errResult
+	}
+	resp, err := callCloudService(ctx, clientManager, workflows.GetNamespaceWorkflowType,
+		&cloudservice.GetNamespaceRequest{Namespace: namespaceID}, clientManager.GetCloudClient().CloudService().GetNamespace)
+	if err != nil {
+		return nil, cloudAPIErrorResult("Error getting namespace", err, "temporal_get_namespace")
+	}
+	return resp.GetNamespace(), nil
+}
+
+func handleListSearchAttributes(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	ns, errResult := getNamespaceForSearchAttributes(ctx, clientManager, request.GetArguments())
+	if errResult != nil {
+		return errResult, nil
+	}
+	return jsonResult(map[string]interface{}{
+		"namespace":         ns.GetNamespace(),
+		"search_attributes": preflight.SearchAttributes(ns.GetSpec()),
+	}), nil
+}
+
+// invalidReportResult returns the report as an error result when it has errors, or as the result of a validate_only call
+func invalidReportResult(report *preflight.Report, validateOnly bool) *mcp.CallToolResult {
+	if report.Valid && !validateOnly {
+		return nil
+	}
+	result := jsonResult(report)
+	result.IsError = !report.Valid
+	return result
+}
+
+func handleAddSearchAttribute(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, errResult := requiredString(arguments, "name")
+	if errResult != nil {
+		return errResult, nil
+	}
+	typeName, errResult := requiredString(arguments, "type")
+	if errResult != nil {
+		return errResult, nil
+	}
+	saType, err := preflight.ParseSearchAttributeType(typeName)
+	if err != nil {
+		return errorResult("Error: %v", err), nil
+	}
+	ns, errResult := getNamespaceForSearchAttributes(ctx, clientManager, arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+	// An attribute that already exists with the same type is what the caller asked for, e.g. on a retried call
+	alreadyExists := func(ns *namespace.Namespace) *mcp.CallToolResult {
+		return jsonResult(map[string]interface{}{
+			"namespace":         ns.GetNamespace(),
+			"already_exists":    preflight.SearchAttribute{Name: name, Type: preflight.SearchAttributeTypeName(saType)},
+			"search_attributes": preflight.SearchAttributes(ns.GetSpec()),
+		})
+	}
+	if workflows.HasSearchAttribute(ns.GetSpec(), name, saType) {
+		return alreadyExists(ns), nil
+	}
+	_, report := workflows.AddSearchAttributeToSpec(ns.GetSpec(), name, saType)
+	validateOnly, _ := arguments["validate_only"].(bool)
+	if result := invalidReportResult(report, validateOnly); result != nil {
+		return result, nil
+	}
+
+	if clientManager.GetTemporalClient() != nil {
+		var out workflows.AddNamespaceSearchAttributeOutput
+		err := clientManager.ExecuteWorkflowInto(ctx, workflows.AddNamespaceSearchAttributeWorkflowType, &workflows.AddNamespaceSearchAttributeInput{
+			Namespace: ns.GetNamespace(),
+			Name:      name,
+			Type:      saType,
+		}, &out)
+		if err != nil {
+			return cloudAPIErrorResult("Error adding search attribute", err, "temporal_list_search_attributes"), nil
+		}
+		return jsonResult(map[string]interface{}{
+			"namespace":          ns.GetNamespace(),
+			"added":              preflight.SearchAttribute{Name: name, Type: preflight.SearchAttributeTypeName(saType)},
+			"async_operation_id": out.AsyncOperationID,
+			"search_attributes":  preflight.SearchAttributes(out.Namespace.GetSpec()),
+		}), nil
+	}
+
+	// Without a Temporal client, read-modify-write the spec here the same way the workflow does
+	cloudService := clientManager.GetCloudClient().CloudService()
+	for attempt := 1; ; attempt++ {
+		if workflows.HasSearchAttribute(ns.GetSpec(), name, saType) {
+			return alreadyExists(ns), nil
+		}
+		spec, report := workflows.AddSearchAttributeToSpec(ns.GetSpec(), name, saType)
+		if !report.Valid {
+			return invalidReportResult(report, false), nil
+		}
+		updateResp, err := cloudService.UpdateNamespace(ctx, &cloudservice.UpdateNamespaceRequest{
+			Namespace:        ns.GetNamespace(),
+			Spec:             spec,
+			ResourceVersion:  ns.GetResourceVersion(),
+			AsyncOperationId: uuid.New().String(),
+		})
+		if apierrors.IsConflict(err) && attempt < defaultPatchNamespaceRetries {
+			if ns, errResult = getNamespaceForSearchAttributes(ctx, clientManager, arguments); errResult != nil {
+				return errResult, nil
+			}
+			continue
+		}
+		if err != nil {
+			return cloudAPIErrorResult("Error adding search attribute", err, "temporal_list_search_attributes"), nil
+		}
+		return searchAttributeChangeResult(ctx, clientManager, arguments, updateResp.GetAsyncOperation().GetId(),
+			"added", preflight.SearchAttribute{Name: name, Type: preflight.SearchAttributeTypeName(saType)})
+	}
+}
+
+func handleRenameSearchAttribute(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, errResult := requiredString(arguments, "name")
+	if errResult != nil {
+		return errResult, nil
+	}
+	newName, errResult := requiredString(arguments, "new_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+	ns, errResult := getNamespaceForSearchAttributes(ctx, clientManager, arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+	report := preflight.CheckRenameSearchAttribute(ns.GetSpec(), name, newName)
+	validateOnly, _ := arguments["validate_only"].(bool)
+	if result := invalidReportResult(report, validateOnly); result != nil {
+		return result, nil
+	}
+
+	renameReq := &cloudservice.RenameCustomSearchAttributeRequest{
+		Namespace:                         ns.GetNamespace(),
+		ExistingCustomSearchAttributeName: name,
+		NewCustomSearchAttributeName:      newName,
+		ResourceVersion:                   ns.GetResourceVersion(),
+		AsyncOperationId:                  uuid.New().String(),
+	}
+	renameResp, err := callCloudService(ctx, clientManager, workflows.RenameCustomSearchAttributeWorkflowType, renameReq,
+		clientManager.GetCloudClient().CloudService().RenameCustomSearchAttribute)
+	if err != nil {
+		return cloudAPIErrorResult("Error renaming search attribute", err, "temporal_list_search_attributes"), nil
+	}
+	return searchAttributeChangeResult(ctx, clientManager, arguments, renameResp.GetAsyncOperation().GetId(),
+		"renamed", fmt.Sprintf("%s -> %s", name, newName))
+}
+
+// searchAttributeChangeResult waits for the change and returns it with the namespace's search attributes afterwards
+func searchAttributeChangeResult(ctx context.Context, clientManager *clients.ClientManager, arguments map[string]interface{}, operationID, key string, change interface{}) (*mcp.CallToolResult, error) {
+	operation, err := waitForAsyncOperation(ctx, clientManager, operationID, asyncOperationTimeout(arguments))
+	if err != nil {
+		return cloudAPIErrorResult("Error waiting for search attribute change", err, "temporal_list_search_attributes"), nil
+	}
+	ns, errResult := getNamespaceForSearchAttributes(ctx, clientManager, arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+	return jsonResult(map[string]interface{}{
+		"namespace":         ns.GetNamespace(),
+		key:                 change,
+		"async_operation":   operation,
+		"search_attributes": preflight.SearchAttributes(ns.GetSpec()),
+	}), nil
+}
@@ -7,10 +7,14 @@ import (
 
 	"bechols/temcp/cmd/mcp-server/clients"
 	"bechols/temcp/cmd/mcp-server/config"
+	"bechols/temcp/cmd/mcp-server/resolver"
+	"bechols/temcp/workflows"
+	"github.com/google/uuid"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
 	"go.temporal.io/cloud-sdk/api/identity/v1"
+	"google.golang.org/protobuf/proto"
 )
 
 // RegisterServiceAccountTools registers all service account management tools with the MCP server
@@ -27,19 +31,60 @@ func RegisterServiceAccountTools(mcpServer *server.MCPServer, cfg *config.Config
 		},
 	)
 
+	// Register temporal_get_service_account tool
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_get_service_account",
+			mcp.WithDescription("Get a Temporal Cloud service account by ID or name"),
+			mcp.WithString("service_account", mcp.Description("Service account ID or name"), mcp.Required()),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetServiceAccount(ctx, request, clientManager)
+		},
+	)
+
 	// Register temporal_create_service_account tool
 	mcpServer.AddTool(
 		mcp.NewTool("temporal_create_service_account",
-			mcp.WithDescription("Create a new Temporal Cloud service account with namespace access"),
+			mcp.WithDescription("Create a new Temporal Cloud service account, either account-scoped with an account role or scoped to a single namespace"),
 			mcp.WithString("name", mcp.Description("Service account name"), mcp.Required()),
-			mcp.WithString("namespace", mcp.Description("Namespace name"), mcp.Required()),
-			mcp.WithString("permission", mcp.Description("Permission level: admin, write, or read"), mcp.Required()),
+			mcp.WithString("account_role", mcp.Description("Account role for an account-scoped service account: admin, developer, finance_admin or read (optional, omit for a namespace-scoped service account)")),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name; required for a namespace-scoped service account, optional extra namespace access for an account-scoped one")),
+			mcp.WithString("permission", mcp.Description("Namespace permission level: admin, write, or read (required with namespace)")),
 			mcp.WithString("description", mcp.Description("Service account description (optional)")),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return handleCreateServiceAccount(ctx, request, clientManager)
 		},
 	)
+
+	// Register temporal_update_service_account tool
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_update_service_account",
+			mcp.WithDescription("Update a service account's name or description and wait for the update to complete"),
+			mcp.WithString("service_account", mcp.Description("Service account ID or name"), mcp.Required()),
+			mcp.WithString("name", mcp.Description("New name (optional)")),
+			mcp.WithString("description", mcp.Description("New description (optional)")),
+			mcp.WithString("resource_version", mcp.Description("Resource version for optimistic concurrency (optional, default: current version)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the update in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleUpdateServiceAccount(ctx, request, clientManager)
+		},
+	)
+
+	// Register temporal_delete_service_account tool
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_delete_service_account",
+			mcp.WithDescription("Delete a service account and wait for the deletion to complete. Refuses while the service account still owns API keys unless force is set"),
+			mcp.WithString("service_account", mcp.Description("Service account ID or name"), mcp.Required()),
+			mcp.WithString("resource_version", mcp.Description("Resource version for optimistic concurrency (optional, default: current version)")),
+			mcp.WithBoolean("force", mcp.Description("Delete even if the service account owns API keys (optional, default: false)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the deletion in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleDeleteServiceAccount(ctx, request, clientManager)
+		},
+	)
 }
 
 func handleListServiceAccounts(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
@@ -61,19 +106,11 @@ func handleListServiceAccounts(ctx context.Context, request mcp.CallToolRequest,
 		PageToken: pageToken,
 	}
 
-	// Call GetServiceAccounts through cloud client
-	cloudClient := clientManager.GetCloudClient()
-	result, err := cloudClient.CloudService().GetServiceAccounts(ctx, getServiceAccountsReq)
+	// Use workflow if Temporal client is available, otherwise call API directly
+	result, err := callCloudService(ctx, clientManager, workflows.GetServiceAccountsWorkflowType, getServiceAccountsReq,
+		clientManager.GetCloudClient().CloudService().GetServiceAccounts)
 	if err != nil {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error listing service accounts: %v", err),
-				},
-			},
-		}, nil
+		return cloudAPIErrorResult("Error listing service accounts", err, ""), nil
 	}
 
 	resultJSON, err := json.MarshalIndent(result, "", "  ")
@@ -99,121 +136,207 @@ func handleListServiceAccounts(ctx context.Context, request mcp.CallToolRequest,
 	}, nil
 }
 
+func handleGetServiceAccount(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	ref, errResult := requiredString(request.GetArguments(), "service_account")
+	if errResult != nil {
+		return errResult, nil
+	}
+	serviceAccount, err := resolver.New(clientManager.GetCloudClient()).ServiceAccount(ctx, ref)
+	if err != nil {
+		return cloudAPIErrorResult("Error getting service account", err, ""), nil
+	}
+	return jsonResult(serviceAccount), nil
+}
+
 func handleCreateServiceAccount(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
 	arguments := request.GetArguments()
 
-	name, ok := arguments["name"].(string)
-	if !ok || name == "" {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: name is required and must be a string",
-				},
-			},
-		}, nil
-	}
-
-	namespace, ok := arguments["namespace"].(string)
-	if !ok || namespace == "" {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: namespace is required and must be a string",
-				},
-			},
-		}, nil
+	name, errResult := requiredString(arguments, "name")
+	if errResult != nil {
+		return errResult, nil
 	}
+	accountRoleStr := optionalString(arguments, "account_role")
+	namespace := optionalString(arguments, "namespace")
+	permissionStr := optionalString(arguments, "permission")
 
-	permissionStr, ok := arguments["permission"].(string)
-	if !ok || permissionStr == "" {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: permission is required and must be a string",
-				},
-			},
-		}, nil
+	if accountRoleStr == "" && namespace == "" {
+		return errorResult("Error: either account_role (account-scoped) or namespace and permission (namespace-scoped) is required"), nil
 	}
-
-	var permission identity.NamespaceAccess_Permission
-	switch permissionStr {
-	case "admin":
-		permission = identity.NamespaceAccess_PERMISSION_ADMIN
-	case "write":
-		permission = identity.NamespaceAccess_PERMISSION_WRITE
-	case "read":
-		permission = identity.NamespaceAccess_PERMISSION_READ
-	default:
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: permission must be 'admin', 'write', or 'read'",
-				},
-			},
-		}, nil
+	if (namespace == "") != (permissionStr == "") {
+		return errorResult("Error: namespace and permission must be given together"), nil
 	}
 
-	description := ""
-	if desc, ok := arguments["description"].(string); ok {
-		description = desc
+	var namespaceAccess *identity.NamespaceAccess
+	if namespace != "" {
+		namespace, errResult = resolveNamespace(ctx, clientManager, namespace)
+		if errResult != nil {
+			return errResult, nil
+		}
+		permission, ok := parseNamespacePermission(permissionStr)
+		if !ok {
+			return errorResult("Error: permission must be 'admin', 'write', or 'read'"), nil
+		}
+		namespaceAccess = &identity.NamespaceAccess{Permission: permission}
 	}
 
 	serviceAccountSpec := &identity.ServiceAccountSpec{
 		Name:        name,
-		Description: description,
-		NamespaceScopedAccess: &identity.NamespaceScopedAccess{
+		Description: optionalString(arguments, "description"),
+	}
+	if accountRoleStr != "" {
+		role, ok := parseAccountRole(accountRoleStr)
+		if !ok || role == identity.AccountAccess_ROLE_OWNER {
+			return errorResult("Error: account_role must be one of admin, developer, finance_admin or read"), nil
+		}
+		serviceAccountSpec.Access = &identity.Access{
+			AccountAccess: &identity.AccountAccess{Role: role},
+		}
+		if namespaceAccess != nil {
+			serviceAccountSpec.Access.NamespaceAccesses = map[string]*identity.NamespaceAccess{
+				namespace: namespaceAccess,
+			}
+		}
+	} else {
+		serviceAccountSpec.NamespaceScopedAccess = &identity.NamespaceScopedAccess{
 			Namespace: namespace,
-			Access: &identity.NamespaceAccess{
-				Permission: permission,
-			},
-		},
+			Access:    namespaceAccess,
+		}
 	}
 
 	createReq := &cloudservice.CreateServiceAccountRequest{
 		Spec: serviceAccountSpec,
 	}
 
-	cloudClient := clientManager.GetCloudClient()
-	result, err := cloudClient.CloudService().CreateServiceAccount(ctx, createReq)
+	result, err := callCloudService(ctx, clientManager, workflows.CreateServiceAccountWorkflowType, createReq,
+		clientManager.GetCloudClient().CloudService().CreateServiceAccount)
 	if err != nil {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error creating service account: %v", err),
-				},
-			},
-		}, nil
+		return cloudAPIErrorResult("Error creating service account", err, ""), nil
 	}
+	return jsonResult(result), nil
+}
 
-	resultJSON, err := json.MarshalIndent(result, "", "  ")
+func handleUpdateServiceAccount(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+
+	ref, errResult := requiredString(arguments, "service_account")
+	if errResult != nil {
+		return errResult, nil
+	}
+	name := optionalString(arguments, "name")
+	description, setDescription := arguments["description"].(string)
+	if name == "" && !setDescription {
+		return errorResult("Error: at least one of name or description is required"), nil
+	}
+
+	serviceAccount, err := resolver.New(clientManager.GetCloudClient()).ServiceAccount(ctx, ref)
 	if err != nil {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error serializing result: %v", err),
-				},
-			},
-		}, nil
+		return cloudAPIErrorResult("Error getting service account", err, ""), nil
 	}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: string(resultJSON),
-			},
-		},
-	}, nil
+	spec := proto.Clone(serviceAccount.Spec).(*identity.ServiceAccountSpec)
+	if name != "" {
+		spec.Name = name
+	}
+	if setDescription {
+		spec.Description = description
+	}
+	resourceVersion := optionalString(arguments, "resource_version")
+	if resourceVersion == "" {
+		resourceVersion = serviceAccount.ResourceVersion
+	}
+
+	cloudService := clientManager.GetCloudClient().CloudService()
+	resp, err := callCloudService(ctx, clientManager, workflows.UpdateServiceAccountWorkflowType, &cloudservice.UpdateServiceAccountRequest{
+		ServiceAccountId: serviceAccount.Id,
+		Spec:             spec,
+		ResourceVersion:  resourceVersion,
+		AsyncOperationId: uuid.New().String(),
+	}, cloudService.UpdateServiceAccount)
+	if err != nil {
+		return cloudAPIErrorResult("Error updating service account", err, "temporal_get_service_account"), nil
+	}
+
+	operation, err := waitForAsyncOperation(ctx, clientManager, resp.AsyncOperation.Id, asyncOperationTimeout(arguments))
+	if err != nil {
+		return cloudAPIErrorResult("Error waiting for service account update", err, ""), nil
+	}
+
+	updated, err := callCloudService(ctx, clientManager, workflows.GetServiceAccountWorkflowType, &cloudservice.GetServiceAccountRequest{
+		ServiceAccountId: serviceAccount.Id,
+	}, cloudService.GetServiceAccount)
+	if err != nil {
+		return cloudAPIErrorResult("Error getting updated service account", err, ""), nil
+	}
+	return jsonResult(map[string]interface{}{
+		"service_account": updated.ServiceAccount,
+		"async_operation": operation,
+	}), nil
+}
+
+func handleDeleteServiceAccount(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+
+	ref, errResult := requiredString(arguments, "service_account")
+	if errResult != nil {
+		return errResult, nil
+	}
+	force, _ := arguments["force"].(bool)
+
+	serviceAccount, err := resolver.New(clientManager.GetCloudClient()).ServiceAccount(ctx, ref)
+	if err != nil {
+		return cloudAPIErrorResult("Error getting service account", err, ""), nil
+	}
+
+	// Find the API keys the service account still owns, they stop working once it is gone
+	keys, err := listAllApiKeys(ctx, clientManager, &cloudservice.GetApiKeysRequest{
+		OwnerId:   serviceAccount.Id,
+		OwnerType: identity.OwnerType_OWNER_TYPE_SERVICE_ACCOUNT,
+	})
+	if err != nil {
+		return cloudAPIErrorResult("Error listing the service account's API keys", err, ""), nil
+	}
+	var ownedKeys []map[string]interface{}
+	for _, key := range keys {
+		ownedKeys = append(ownedKeys, map[string]interface{}{
+			"id":           key.Id,
+			"display_name": key.GetSpec().GetDisplayName(),
+			"disabled":     key.GetSpec().GetDisabled(),
+		})
+	}
+	if len(ownedKeys) > 0 && !force {
+		text, _ := json.MarshalIndent(map[string]interface{}{
+			"error":    fmt.Sprintf("Service account %s still owns %d API key(s), which will stop working once it is deleted. Delete or replace them first, or pass force=true to delete anyway.", serviceAccount.GetSpec().GetName(), len(ownedKeys)),
+			"api_keys": ownedKeys,
+		}, "", "  ")
+		return errorResult("%s", text), nil
+	}
+
+	resourceVersion := optionalString(arguments, "resource_version")
+	if resourceVersion == "" {
+		resourceVersion = serviceAccount.ResourceVersion
+	}
+	resp, err := callCloudService(ctx, clientManager, workflows.DeleteServiceAccountWorkflowType, &cloudservice.DeleteServiceAccountRequest{
+		ServiceAccountId: serviceAccount.Id,
+		ResourceVersion:  resourceVersion,
+		AsyncOperationId: uuid.New().String(),
+	}, clientManager.GetCloudClient().CloudService().DeleteServiceAccount)
+	if err != nil {
+		return cloudAPIErrorResult("Error deleting service account", err, "temporal_get_service_account"), nil
+	}
+
+	operation, err := waitForAsyncOperation(ctx, clientManager, resp.AsyncOperation.Id, asyncOperationTimeout(arguments))
+	if err != nil {
+		return cloudAPIErrorResult("Error waiting for service account deletion", err, ""), nil
+	}
+
+	result := map[string]interface{}{
+		"deleted_service_account_id": serviceAccount.Id,
+		"name":                       serviceAccount.GetSpec().GetName(),
+		"async_operation":            operation,
+	}
+	if len(ownedKeys) > 0 {
+		result["warning"] = fmt.Sprintf("The service account owned %d API key(s) that no longer work", len(ownedKeys))
+		result["api_keys"] = ownedKeys
+	}
+	return jsonResult(result), nil
 }
@@ -6,12 +6,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
-func RegisterAllTools(mcpServer *server.MCPServer, cfg *config.Config) error {
-	clientManager, err := clients.NewClientManager(cfg)
-	if err != nil {
-		return err
-	}
-
+func RegisterAllTools(mcpServer *server.MCPServer, cfg *config.Config, clientManager *clients.ClientManager) error {
 	RegisterUserTools(mcpServer, cfg, clientManager)
 
 	RegisterAccountAccessTools(mcpServer, cfg, clientManager)
@@ -20,19 +15,45 @@ func RegisterAllTools(mcpServer *server.MCPServer, cfg *config.Config) error {
 
 	RegisterNamespaceMgmtTools(mcpServer, cfg, clientManager)
 
+	RegisterNamespacePatchTools(mcpServer, cfg, clientManager)
+
+	RegisterNamespaceRegionTools(mcpServer, cfg, clientManager)
+
+	RegisterFailoverDrillTools(mcpServer, cfg, clientManager)
+
+	RegisterNamespaceCARotationTools(mcpServer, cfg, clientManager)
+
+	RegisterCertificateTools(mcpServer, cfg, clientManager)
+
+	RegisterSearchAttributeTools(mcpServer, cfg, clientManager)
+
 	RegisterRegionTools(mcpServer, cfg, clientManager)
 
 	RegisterOperationTools(mcpServer, cfg, clientManager)
 
 	RegisterExportTools(mcpServer, cfg, clientManager)
 
+	RegisterExportSinkTools(mcpServer, cfg, clientManager)
+
+	RegisterNexusEndpointTools(mcpServer, cfg, clientManager)
+
+	RegisterUsageTools(mcpServer, cfg, clientManager)
+
+	RegisterAccountTools(mcpServer, cfg, clientManager)
+
 	RegisterApiKeyTools(mcpServer, cfg, clientManager)
 
 	RegisterServiceAccountTools(mcpServer, cfg, clientManager)
 
 	RegisterNamespaceServiceAccountAccessTools(mcpServer, cfg, clientManager)
 
+	RegisterUserGroupTools(mcpServer, cfg, clientManager)
+
 	RegisterConnectionInfoTools(mcpServer, cfg, clientManager)
 
+	RegisterResourceTemplates(mcpServer, clientManager)
+
+	RegisterPrompts(mcpServer)
+
 	return nil
 }
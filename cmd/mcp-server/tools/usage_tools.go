@@ -0,0 +1,138 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"bechols/temcp/cmd/mcp-server/clients"
+	"bechols/temcp/cmd/mcp-server/config"
+	"bechols/temcp/internal/usage"
+	"bechols/temcp/workflows"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RegisterUsageTools registers the usage reporting tools with the MCP server
+func RegisterUsageTools(mcpServer *server.MCPServer, cfg *config.Config, clientManager *clients.ClientManager) {
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_get_usage",
+			mcp.WithDescription("Report account usage (actions, active storage, retained storage) per namespace, day and metric over a month or date range, with totals and the change from the previous period"),
+			mcp.WithString("month", mcp.Description("Calendar month as YYYY-MM (optional, default the last full month; ignored if start_date is given)")),
+			mcp.WithString("start_date", mcp.Description("First day as YYYY-MM-DD, UTC (optional, requires end_date)")),
+			mcp.WithString("end_date", mcp.Description("Last day as YYYY-MM-DD, inclusive (optional, requires start_date)")),
+			mcp.WithString("namespaces", mcp.Description("Comma-separated namespace IDs or names to report on (optional, default all)")),
+			mcp.WithBoolean("compare_previous", mcp.Description("Add the previous period's totals and the change from them (optional, default true)")),
+			mcp.WithBoolean("daily", mcp.Description("Include the per-day rows in csv and markdown output; csv then has only daily rows (optional, default false)")),
+			mcp.WithString("format", mcp.Description("Output format: json, csv or markdown (optional, default markdown)")),
+			mcp.WithString("output_file", mcp.Description("Local file to write the report to instead of returning it (optional)")),
+			mcp.WithBoolean("overwrite", mcp.Description("Replace output_file if it exists (optional, default: false)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetUsage(ctx, request, clientManager)
+		},
+	)
+}
+
+// usagePeriod reads the period from start_date and end_date, or month, defaulting to the last full month
+func usagePeriod(arguments map[string]interface{}, now time.Time) (usage.Period, *mcp.CallToolResult) {
+	startDate, endDate := optionalString(arguments, "start_date"), optionalString(arguments, "end_date")
+	if startDate != "" || endDate != "" {
+		if startDate == "" || endDate == "" {
+			return usage.Period{}, errorResult("Error: start_date and end_date must be given together")
+		}
+		period, err := usage.DaysPeriod(startDate, endDate)
+		if err != nil {
+			return usage.Period{}, errorResult("Error: %v", err)
+		}
+		return period, nil
+	}
+	if month := optionalString(arguments, "month"); month != "" {
+		start, err := time.Parse("2006-01", month)
+		if err != nil {
+			return usage.Period{}, errorResult("Error: month %q is not YYYY-MM", month)
+		}
+		return usage.MonthPeriod(start.Year(), start.Month()), nil
+	}
+	now = now.UTC()
+	return usage.MonthPeriod(now.Year(), now.Month()).Previous(), nil
+}
+
+func handleGetUsage(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+
+	format := strings.ToLower(optionalString(arguments, "format"))
+	if format == "" {
+		format = "markdown"
+	}
+	if format != "json" && format != "csv" && format != "markdown" {
+		return errorResult("Error: format must be json, csv or markdown"), nil
+	}
+	period, errResult := usagePeriod(arguments, time.Now())
+	if errResult != nil {
+		return errResult, nil
+	}
+	input := &workflows.UsageReportInput{Options: usage.Options{Period: period, ComparePrevious: true}}
+	if compare, ok := arguments["compare_previous"].(bool); ok {
+		input.Options.ComparePrevious = compare
+	}
+	for _, ref := range strings.Split(optionalString(arguments, "namespaces"), ",") {
+		if ref = strings.TrimSpace(ref); ref == "" {
+			continue
+		}
+		namespaceID, errResult := resolveNamespace(ctx, clientManager, ref)
+		if errResult != nil {
+			return errResult, nil
+		}
+		input.Options.Namespaces = append(input.Options.Namespaces, namespaceID)
+	}
+	daily, _ := arguments["daily"].(bool)
+
+	// Use workflow if Temporal client is available, otherwise read usage directly
+	var (
+		report *usage.Report
+		err    error
+	)
+	if clientManager.GetTemporalClient() != nil {
+		report = &usage.Report{}
+		err = clientManager.ExecuteWorkflowInto(ctx, workflows.UsageReportWorkflowType, input, report)
+	} else {
+		report, err = usage.New(clientManager.GetCloudClient()).Report(ctx, input.Options)
+	}
+	if err != nil {
+		return cloudAPIErrorResult("Error getting usage", err, ""), nil
+	}
+
+	var rendered string
+	switch format {
+	case "csv":
+		if rendered, err = report.CSV(daily); err != nil {
+			return errorResult("Error rendering usage report: %v", err), nil
+		}
+	case "markdown":
+		rendered = report.Markdown(daily)
+	default:
+		reportJSON, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return errorResult("Error serializing usage report: %v", err), nil
+		}
+		rendered = string(reportJSON)
+	}
+
+	outputFile := optionalString(arguments, "output_file")
+	if outputFile == "" {
+		return textResult(rendered), nil
+	}
+	overwrite, _ := arguments["overwrite"].(bool)
+	if err := writeOutputFile(outputFile, []byte(rendered), overwrite); err != nil {
+		return errorResult("Error writing usage report to %s: %v", outputFile, err), nil
+	}
+	return jsonResult(map[string]interface{}{
+		"output_file":    outputFile,
+		"format":         format,
+		"period":         report.Period.String(),
+		"account_totals": report.AccountTotals,
+		"notes":          report.Notes,
+	}), nil
+}
@@ -0,0 +1,608 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"bechols/temcp/cmd/mcp-server/clients"
+	"bechols/temcp/cmd/mcp-server/config"
+	"bechols/temcp/cmd/mcp-server/resolver"
+	"bechols/temcp/workflows"
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+	"go.temporal.io/cloud-sdk/api/identity/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// RegisterUserGroupTools registers user group and group membership tools with the MCP server
+func RegisterUserGroupTools(mcpServer *server.MCPServer, cfg *config.Config, clientManager *clients.ClientManager) {
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_list_user_groups",
+			mcp.WithDescription("List Temporal Cloud user groups with their type, account role and namespace access"),
+			mcp.WithString("namespace", mcp.Description("Only list groups with access to this namespace ID or name (optional)")),
+			mcp.WithString("display_name", mcp.Description("Only list groups with this display name (optional)")),
+			mcp.WithNumber("page_size", mcp.Description("Number of groups per page (optional)")),
+			mcp.WithString("page_token", mcp.Description("Token for next page (optional)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleListUserGroups(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_get_user_group",
+			mcp.WithDescription("Get a user group by ID or display name"),
+			mcp.WithString("group", mcp.Description("User group ID or display name"), mcp.Required()),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetUserGroup(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_create_user_group",
+			mcp.WithDescription("Create a user group with an account role and optional namespace access, and wait for the creation to complete"),
+			mcp.WithString("display_name", mcp.Description("Display name of the group"), mcp.Required()),
+			mcp.WithString("account_role", mcp.Description("Account role of the group's members: admin, developer, finance_admin or read"), mcp.Required()),
+			mcp.WithObject("namespace_accesses", mcp.Description("Namespace access, mapping namespace ID or name to permission (admin, write or read), e.g. {\"orders\": \"write\"} (optional)")),
+			mcp.WithString("type", mcp.Description("Group type: cloud (members managed with the member tools) or google (members come from a Google group) (optional, default cloud)")),
+			mcp.WithString("google_group_email", mcp.Description("Email address of the Google group, required when type is google")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the creation in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleCreateUserGroup(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_update_user_group",
+			mcp.WithDescription("Change a user group's display name or account role and wait for the update to complete"),
+			mcp.WithString("group", mcp.Description("User group ID or display name"), mcp.Required()),
+			mcp.WithString("display_name", mcp.Description("New display name (optional)")),
+			mcp.WithString("account_role", mcp.Description("New account role: admin, developer, finance_admin or read (optional)")),
+			mcp.WithString("resource_version", mcp.Description("Resource version for optimistic concurrency (optional, default: current version)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the update in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleUpdateUserGroup(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_delete_user_group",
+			mcp.WithDescription("Delete a user group and wait for the deletion to complete"),
+			mcp.WithString("group", mcp.Description("User group ID or display name"), mcp.Required()),
+			mcp.WithString("resource_version", mcp.Description("Resource version for optimistic concurrency (optional, default: current version)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the deletion in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleDeleteUserGroup(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_set_user_group_namespace_access",
+			mcp.WithDescription("Set or remove a user group's access to a namespace and wait for the change to complete"),
+			mcp.WithString("group", mcp.Description("User group ID or display name"), mcp.Required()),
+			mcp.WithString("namespace", mcp.Description("Namespace ID (name.account) or name"), mcp.Required()),
+			mcp.WithString("permission", mcp.Description("Permission level: admin, write or read, or none to remove the group's access"), mcp.Required()),
+			mcp.WithString("resource_version", mcp.Description("Resource version for optimistic concurrency (optional, default: current version)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the change in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleSetUserGroupNamespaceAccess(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_list_user_group_members",
+			mcp.WithDescription("List the members of a user group with their emails"),
+			mcp.WithString("group", mcp.Description("User group ID or display name"), mcp.Required()),
+			mcp.WithNumber("page_size", mcp.Description("Number of members per page (optional)")),
+			mcp.WithString("page_token", mcp.Description("Token for next page (optional)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleListUserGroupMembers(ctx, request, clientManager)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_add_user_group_member",
+			mcp.WithDescription("Add a user to a cloud user group and wait for the change to complete"),
+			mcp.WithString("group", mcp.Description("User group ID or display name"), mcp.Required()),
+			mcp.WithString("user_id", mcp.Description("User ID or email"), mcp.Required()),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the change in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleChangeUserGroupMember(ctx, request, clientManager, true)
+		},
+	)
+
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_remove_user_group_member",
+			mcp.WithDescription("Remove a user from a cloud user group and wait for the change to complete"),
+			mcp.WithString("group", mcp.Description("User group ID or display name"), mcp.Required()),
+			mcp.WithString("user_id", mcp.Description("User ID or email"), mcp.Required()),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the change in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleChangeUserGroupMember(ctx, request, clientManager, false)
+		},
+	)
+}
+
+// userGroupSummary is how the user group tools present a group. The group type is a oneof in the
+// Cloud API, which doesn't read well as JSON, so it is flattened into type and a type-specific field.
+type userGroupSummary struct {
+	ID                string            `json:"id"`
+	DisplayName       string            `json:"display_name"`
+	Type              string            `json:"type"`
+	GoogleGroupEmail  string            `json:"google_group_email,omitempty"`
+	ScimIdpID         string            `json:"scim_idp_id,omitempty"`
+	AccountRole       string            `json:"account_role,omitempty"`
+	NamespaceAccesses map[string]string `json:"namespace_accesses,omitempty"`
+	State             string            `json:"state"`
+	ResourceVersion   string            `json:"resource_version"`
+	CreatedTime       string            `json:"created_time,omitempty"`
+}
+
+func summarizeUserGroup(group *identity.UserGroup) userGroupSummary {
+	spec := group.GetSpec()
+	summary := userGroupSummary{
+		ID:              group.Id,
+		DisplayName:     spec.GetDisplayName(),
+		State:           group.State.String(),
+		ResourceVersion: group.ResourceVersion,
+	}
+	switch {
+	case spec.GetGoogleGroup() != nil:
+		summary.Type = "google"
+		summary.GoogleGroupEmail = spec.GetGoogleGroup().GetEmailAddress()
+	case spec.GetScimGroup() != nil:
+		summary.Type = "scim"
+		summary.ScimIdpID = spec.GetScimGroup().GetIdpId()
+	default:
+		summary.Type = "cloud"
+	}
+	if role := spec.GetAccess().GetAccountAccess().GetRole(); role != identity.AccountAccess_ROLE_UNSPECIFIED {
+		summary.AccountRole = role.String()
+	}
+	if accesses := spec.GetAccess().GetNamespaceAccesses(); len(accesses) > 0 {
+		summary.NamespaceAccesses = make(map[string]string, len(accesses))
+		for namespace, access := range accesses {
+			summary.NamespaceAccesses[namespace] = access.GetPermission().String()
+		}
+	}
+	if group.CreatedTime != nil {
+		summary.CreatedTime = group.CreatedTime.AsTime().Format(time.RFC3339)
+	}
+	return summary
+}
+
+func handleListUserGroups(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+
+	listReq := &cloudservice.GetUserGroupsRequest{
+		DisplayName: optionalString(arguments, "display_name"),
+		PageToken:   optionalString(arguments, "page_token"),
+	}
+	if ps, ok := arguments["page_size"].(float64); ok {
+		listReq.PageSize = int32(ps)
+	}
+	if namespace := optionalString(arguments, "namespace"); namespace != "" {
+		namespace, errResult := resolveNamespace(ctx, clientManager, namespace)
+		if errResult != nil {
+			return errResult, nil
+		}
+		listReq.Namespace = namespace
+	}
+
+	resp, err := callCloudService(ctx, clientManager, workflows.GetUserGroupsWorkflowType, listReq,
+		clientManager.GetCloudClient().CloudService().GetUserGroups)
+	if err != nil {
+		return cloudAPIErrorResult("Error listing user groups", err, ""), nil
+	}
+
+	groups := make([]userGroupSummary, 0, len(resp.Groups))
+	for _, group := range resp.Groups {
+		groups = append(groups, summarizeUserGroup(group))
+	}
+	return jsonResult(map[string]interface{}{
+		"groups":          groups,
+		"next_page_token": resp.NextPageToken,
+	}), nil
+}
+
+func handleGetUserGroup(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	ref, errResult := requiredString(request.GetArguments(), "group")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	group, errResult := getUserGroupForUpdate(ctx, clientManager, ref)
+	if errResult != nil {
+		return errResult, nil
+	}
+	return jsonResult(summarizeUserGroup(group)), nil
+}
+
+func handleCreateUserGroup(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+
+	displayName, errResult := requiredString(arguments, "display_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+	roleStr, errResult := requiredString(arguments, "account_role")
+	if errResult != nil {
+		return errResult, nil
+	}
+	role, ok := parseAccountRole(roleStr)
+	if !ok || role == identity.AccountAccess_ROLE_OWNER {
+		return errorResult("Error: account_role must be one of admin, developer, finance_admin or read"), nil
+	}
+
+	spec := &identity.UserGroupSpec{
+		DisplayName: displayName,
+		Access: &identity.Access{
+			AccountAccess:     &identity.AccountAccess{Role: role},
+			NamespaceAccesses: map[string]*identity.NamespaceAccess{},
+		},
+	}
+	switch strings.ToLower(optionalString(arguments, "type")) {
+	case "", "cloud":
+		spec.GroupType = &identity.UserGroupSpec_CloudGroup{CloudGroup: &identity.CloudGroupSpec{}}
+	case "google":
+		email, errResult := requiredString(arguments, "google_group_email")
+		if errResult != nil {
+			return errResult, nil
+		}
+		spec.GroupType = &identity.UserGroupSpec_GoogleGroup{GoogleGroup: &identity.GoogleGroupSpec{EmailAddress: email}}
+	default:
+		return errorResult("Error: type must be cloud or google"), nil
+	}
+
+	if raw, ok := arguments["namespace_accesses"]; ok && raw != nil {
+		accesses, ok := raw.(map[string]interface{})
+		if !ok {
+			return errorResult("Error: namespace_accesses must be an object mapping namespace to permission"), nil
+		}
+		for ref, permissionRaw := range accesses {
+			permissionStr, _ := permissionRaw.(string)
+			permission, ok := parseNamespacePermission(permissionStr)
+			if !ok {
+				return errorResult("Error: permission for namespace %s must be admin, write or read", ref), nil
+			}
+			namespace, errResult := resolveNamespace(ctx, clientManager, ref)
+			if errResult != nil {
+				return errResult, nil
+			}
+			spec.Access.NamespaceAccesses[namespace] = &identity.NamespaceAccess{Permission: permission}
+		}
+	}
+
+	createReq := &cloudservice.CreateUserGroupRequest{
+		Spec:             spec,
+		AsyncOperationId: uuid.New().String(),
+	}
+	resp, err := callCloudService(ctx, clientManager, workflows.CreateUserGroupWorkflowType, createReq,
+		clientManager.GetCloudClient().CloudService().CreateUserGroup)
+	if err != nil {
+		return cloudAPIErrorResult("Error creating user group", err, "temporal_list_user_groups"), nil
+	}
+
+	operation, err := waitForAsyncOperation(ctx, clientManager, resp.AsyncOperation.GetId(), asyncOperationTimeout(arguments))
+	if err != nil {
+		return cloudAPIErrorResult("Error waiting for user group creation", err, ""), nil
+	}
+	return jsonResult(map[string]interface{}{
+		"group":           summarizeUserGroup(&identity.UserGroup{Id: resp.GroupId, Spec: spec}),
+		"async_operation": operation,
+	}), nil
+}
+
+func handleUpdateUserGroup(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+
+	ref, errResult := requiredString(arguments, "group")
+	if errResult != nil {
+		return errResult, nil
+	}
+	displayName := optionalString(arguments, "display_name")
+	roleStr := optionalString(arguments, "account_role")
+	if displayName == "" && roleStr == "" {
+		return errorResult("Error: pass display_name, account_role or both"), nil
+	}
+
+	group, errResult := getUserGroupForUpdate(ctx, clientManager, ref)
+	if errResult != nil {
+		return errResult, nil
+	}
+	spec := proto.Clone(group.Spec).(*identity.UserGroupSpec)
+	if displayName != "" {
+		spec.DisplayName = displayName
+	}
+	if roleStr != "" {
+		role, ok := parseAccountRole(roleStr)
+		if !ok || role == identity.AccountAccess_ROLE_OWNER {
+			return errorResult("Error: account_role must be one of admin, developer, finance_admin or read"), nil
+		}
+		if spec.Access == nil {
+			spec.Access = &identity.Access{}
+		}
+		spec.Access.AccountAccess = &identity.AccountAccess{Role: role}
+	}
+
+	resourceVersion := optionalString(arguments, "resource_version")
+	if resourceVersion == "" {
+		resourceVersion = group.ResourceVersion
+	}
+	updateReq := &cloudservice.UpdateUserGroupRequest{
+		GroupId:          group.Id,
+		Spec:             spec,
+		ResourceVersion:  resourceVersion,
+		AsyncOperationId: uuid.New().String(),
+	}
+	resp, err := callCloudService(ctx, clientManager, workflows.UpdateUserGroupWorkflowType, updateReq,
+		clientManager.GetCloudClient().CloudService().UpdateUserGroup)
+	if err != nil {
+		return cloudAPIErrorResult("Error updating user group", err, "temporal_get_user_group"), nil
+	}
+
+	operation, err := waitForAsyncOperation(ctx, clientManager, resp.AsyncOperation.GetId(), asyncOperationTimeout(arguments))
+	if err != nil {
+		return cloudAPIErrorResult("Error waiting for user group update", err, ""), nil
+	}
+	return jsonResult(map[string]interface{}{
+		"group":           summarizeUserGroup(&identity.UserGroup{Id: group.Id, Spec: spec, State: group.State, CreatedTime: group.CreatedTime}),
+		"async_operation": operation,
+	}), nil
+}
+
+func handleDeleteUserGroup(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+
+	ref, errResult := requiredString(arguments, "group")
+	if errResult != nil {
+		return errResult, nil
+	}
+	group, errResult := getUserGroupForUpdate(ctx, clientManager, ref)
+	if errResult != nil {
+		return errResult, nil
+	}
+	resourceVersion := optionalString(arguments, "resource_version")
+	if resourceVersion == "" {
+		resourceVersion = group.ResourceVersion
+	}
+
+	deleteReq := &cloudservice.DeleteUserGroupRequest{
+		GroupId:          group.Id,
+		ResourceVersion:  resourceVersion,
+		AsyncOperationId: uuid.New().String(),
+	}
+	resp, err := callCloudService(ctx, clientManager, workflows.DeleteUserGroupWorkflowType, deleteReq,
+		clientManager.GetCloudClient().CloudService().DeleteUserGroup)
+	if err != nil {
+		return cloudAPIErrorResult("Error deleting user group", err, "temporal_get_user_group"), nil
+	}
+
+	operation, err := waitForAsyncOperation(ctx, clientManager, resp.AsyncOperation.GetId(), asyncOperationTimeout(arguments))
+	if err != nil {
+		return cloudAPIErrorResult("Error waiting for user group deletion", err, ""), nil
+	}
+	return jsonResult(map[string]interface{}{
+		"deleted_group_id": group.Id,
+		"display_name":     group.GetSpec().GetDisplayName(),
+		"async_operation":  operation,
+	}), nil
+}
+
+func handleSetUserGroupNamespaceAccess(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+
+	ref, errResult := requiredString(arguments, "group")
+	if errResult != nil {
+		return errResult, nil
+	}
+	namespace, errResult := requiredString(arguments, "namespace")
+	if errResult != nil {
+		return errResult, nil
+	}
+	permissionStr, errResult := requiredString(arguments, "permission")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	// A nil access removes the group's access to the namespace
+	var access *identity.NamespaceAccess
+	if !strings.EqualFold(permissionStr, "none") {
+		permission, ok := parseNamespacePermission(permissionStr)
+		if !ok {
+			return errorResult("Error: permission must be admin, write, read or none"), nil
+		}
+		access = &identity.NamespaceAccess{Permission: permission}
+	}
+
+	namespace, errResult = resolveNamespace(ctx, clientManager, namespace)
+	if errResult != nil {
+		return errResult, nil
+	}
+	group, errResult := getUserGroupForUpdate(ctx, clientManager, ref)
+	if errResult != nil {
+		return errResult, nil
+	}
+	resourceVersion := optionalString(arguments, "resource_version")
+	if resourceVersion == "" {
+		resourceVersion = group.ResourceVersion
+	}
+
+	setReq := &cloudservice.SetUserGroupNamespaceAccessRequest{
+		Namespace:        namespace,
+		GroupId:          group.Id,
+		Access:           access,
+		ResourceVersion:  resourceVersion,
+		AsyncOperationId: uuid.New().String(),
+	}
+	resp, err := callCloudService(ctx, clientManager, workflows.SetUserGroupNamespaceAccessWorkflowType, setReq,
+		clientManager.GetCloudClient().CloudService().SetUserGroupNamespaceAccess)
+	if err != nil {
+		return cloudAPIErrorResult("Error setting user group namespace access", err, "temporal_get_user_group"), nil
+	}
+
+	operation, err := waitForAsyncOperation(ctx, clientManager, resp.AsyncOperation.GetId(), asyncOperationTimeout(arguments))
+	if err != nil {
+		return cloudAPIErrorResult("Error waiting for user group namespace access change", err, ""), nil
+	}
+	previous := "NONE"
+	if current, ok := group.GetSpec().GetAccess().GetNamespaceAccesses()[namespace]; ok {
+		previous = current.GetPermission().String()
+	}
+	permission := "NONE"
+	if access != nil {
+		permission = access.Permission.String()
+	}
+	return jsonResult(map[string]interface{}{
+		"group_id":            group.Id,
+		"namespace":           namespace,
+		"previous_permission": previous,
+		"permission":          permission,
+		"async_operation":     operation,
+	}), nil
+}
+
+func handleListUserGroupMembers(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+
+	ref, errResult := requiredString(arguments, "group")
+	if errResult != nil {
+		return errResult, nil
+	}
+	group, errResult := getUserGroupForUpdate(ctx, clientManager, ref)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	listReq := &cloudservice.GetUserGroupMembersRequest{
+		GroupId:   group.Id,
+		PageToken: optionalString(arguments, "page_token"),
+	}
+	if ps, ok := arguments["page_size"].(float64); ok {
+		listReq.PageSize = int32(ps)
+	}
+	resp, err := callCloudService(ctx, clientManager, workflows.GetUserGroupMembersWorkflowType, listReq,
+		clientManager.GetCloudClient().CloudService().GetUserGroupMembers)
+	if err != nil {
+		return cloudAPIErrorResult("Error listing user group members", err, "temporal_get_user_group"), nil
+	}
+
+	emails := newApiKeyOwnerNames(clientManager)
+	members := make([]map[string]interface{}, 0, len(resp.Members))
+	for _, member := range resp.Members {
+		userID := member.GetMemberId().GetUserId()
+		entry := map[string]interface{}{
+			"user_id": userID,
+			"email":   emails.name(ctx, identity.OwnerType_OWNER_TYPE_USER, userID),
+		}
+		if member.CreatedTime != nil {
+			entry["added_time"] = member.CreatedTime.AsTime().Format(time.RFC3339)
+		}
+		members = append(members, entry)
+	}
+	return jsonResult(map[string]interface{}{
+		"group_id":        group.Id,
+		"display_name":    group.GetSpec().GetDisplayName(),
+		"members":         members,
+		"next_page_token": resp.NextPageToken,
+	}), nil
+}
+
+// handleChangeUserGroupMember adds the user to the group, or removes them if add is false
+func handleChangeUserGroupMember(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager, add bool) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+
+	ref, errResult := requiredString(arguments, "group")
+	if errResult != nil {
+		return errResult, nil
+	}
+	userID, errResult := requiredString(arguments, "user_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	userID, errResult = resolveUserID(ctx, clientManager, userID)
+	if errResult != nil {
+		return errResult, nil
+	}
+	group, errResult := getUserGroupForUpdate(ctx, clientManager, ref)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if group.GetSpec().GetCloudGroup() == nil {
+		return errorResult("Error: user group %s is not a cloud group; its members are managed by its identity provider", group.Id), nil
+	}
+
+	memberID := &identity.UserGroupMemberId{
+		MemberType: &identity.UserGroupMemberId_UserId{UserId: userID},
+	}
+	cloudService := clientManager.GetCloudClient().CloudService()
+	var (
+		operationID string
+		err         error
+	)
+	if add {
+		var resp *cloudservice.AddUserGroupMemberResponse
+		resp, err = callCloudService(ctx, clientManager, workflows.AddUserGroupMemberWorkflowType, &cloudservice.AddUserGroupMemberRequest{
+			GroupId:          group.Id,
+			MemberId:         memberID,
+			AsyncOperationId: uuid.New().String(),
+		}, cloudService.AddUserGroupMember)
+		operationID = resp.GetAsyncOperation().GetId()
+	} else {
+		var resp *cloudservice.RemoveUserGroupMemberResponse
+		resp, err = callCloudService(ctx, clientManager, workflows.RemoveUserGroupMemberWorkflowType, &cloudservice.RemoveUserGroupMemberRequest{
+			GroupId:          group.Id,
+			MemberId:         memberID,
+			AsyncOperationId: uuid.New().String(),
+		}, cloudService.RemoveUserGroupMember)
+		operationID = resp.GetAsyncOperation().GetId()
+	}
+	if err != nil {
+		return cloudAPIErrorResult("Error changing user group membership", err, "temporal_list_user_group_members"), nil
+	}
+
+	operation, err := waitForAsyncOperation(ctx, clientManager, operationID, asyncOperationTimeout(arguments))
+	if err != nil {
+		return cloudAPIErrorResult("Error waiting for user group membership change", err, ""), nil
+	}
+	action := "removed"
+	if add {
+		action = "added"
+	}
+	return jsonResult(map[string]interface{}{
+		"group_id":        group.Id,
+		"user_id":         userID,
+		"action":          action,
+		"async_operation": operation,
+	}), nil
+}
+
+// getUserGroupForUpdate resolves a group ID or display name and fetches the group whose spec and
+// resource version an update starts from
+func getUserGroupForUpdate(ctx context.Context, clientManager *clients.ClientManager, ref string) (*identity.UserGroup, *mcp.CallToolResult) {
+	group, err := resolver.New(clientManager.GetCloudClient()).UserGroup(ctx, ref)
+	if err != nil {
+		return nil, cloudAPIErrorResult("Error resolving user group", err, "temporal_list_user_groups")
+	}
+	resp, err := callCloudService(ctx, clientManager, workflows.GetUserGroupWorkflowType, &cloudservice.GetUserGroupRequest{GroupId: group.Id},
+		clientManager.GetCloudClient().CloudService().GetUserGroup)
+	if err != nil {
+		return nil, cloudAPIErrorResult("Error getting user group", err, "temporal_list_user_groups")
+	}
+	if resp.Group == nil || resp.Group.Spec == nil {
+		return nil, errorResult("User group %s has no specification", group.Id)
+	}
+	return resp.Group, nil
+}
@@ -8,17 +8,20 @@ import (
 	"bechols/temcp/cmd/mcp-server/clients"
 	"bechols/temcp/cmd/mcp-server/config"
 	"bechols/temcp/workflows"
+	"github.com/google/uuid"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+	"go.temporal.io/cloud-sdk/api/identity/v1"
+	"google.golang.org/protobuf/proto"
 )
 
 func RegisterUserTools(mcpServer *server.MCPServer, cfg *config.Config, clientManager *clients.ClientManager) {
 	// Register temporal_get_user tool
 	mcpServer.AddTool(
 		mcp.NewTool("temporal_get_user",
-			mcp.WithDescription("Get a Temporal Cloud user by ID"),
-			mcp.WithString("user_id", mcp.Description("User ID"), mcp.Required()),
+			mcp.WithDescription("Get a Temporal Cloud user by ID or email"),
+			mcp.WithString("user_id", mcp.Description("User ID or email"), mcp.Required()),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return handleGetUser(ctx, request, clientManager)
@@ -37,6 +40,46 @@ func RegisterUserTools(mcpServer *server.MCPServer, cfg *config.Config, clientMa
 		},
 	)
 
+	// Register temporal_invite_user tool
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_invite_user",
+			mcp.WithDescription("Invite a user to the Temporal Cloud account with an account role and optional namespace access, and wait for the invite to complete"),
+			mcp.WithString("email", mcp.Description("Email address to invite"), mcp.Required()),
+			mcp.WithString("account_role", mcp.Description("Account role: admin, developer, finance_admin or read"), mcp.Required()),
+			mcp.WithObject("namespace_accesses", mcp.Description("Initial namespace access, mapping namespace ID or name to permission (admin, write or read), e.g. {\"orders\": \"write\"} (optional)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the invite in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleInviteUser(ctx, request, clientManager)
+		},
+	)
+
+	// Register temporal_set_user_account_role tool
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_set_user_account_role",
+			mcp.WithDescription("Change a user's account role and wait for the update to complete"),
+			mcp.WithString("user_id", mcp.Description("User ID or email"), mcp.Required()),
+			mcp.WithString("account_role", mcp.Description("Account role: admin, developer, finance_admin or read"), mcp.Required()),
+			mcp.WithString("resource_version", mcp.Description("Resource version for optimistic concurrency (optional, default: current version)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the update in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleSetUserAccountRole(ctx, request, clientManager)
+		},
+	)
+
+	// Register temporal_delete_user tool
+	mcpServer.AddTool(
+		mcp.NewTool("temporal_delete_user",
+			mcp.WithDescription("Remove a user from the Temporal Cloud account and wait for the deletion to complete"),
+			mcp.WithString("user_id", mcp.Description("User ID or email"), mcp.Required()),
+			mcp.WithString("resource_version", mcp.Description("Resource version for optimistic concurrency (optional, default: current version)")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("How long to wait for the deletion in seconds (optional, default 300)")),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleDeleteUser(ctx, request, clientManager)
+		},
+	)
 }
 
 func handleGetUser(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
@@ -55,6 +98,11 @@ func handleGetUser(ctx context.Context, request mcp.CallToolRequest, clientManag
 		}, nil
 	}
 
+	userID, errResult := resolveUserID(ctx, clientManager, userID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	getUserReq := &cloudservice.GetUserRequest{
 		UserId: userID,
 	}
@@ -71,15 +119,7 @@ func handleGetUser(ctx context.Context, request mcp.CallToolRequest, clientManag
 		user, err = cloudClient.CloudService().GetUser(ctx, getUserReq)
 	}
 	if err != nil {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error getting user: %v", err),
-				},
-			},
-		}, nil
+		return cloudAPIErrorResult("Error getting user", err, "temporal_get_user"), nil
 	}
 
 	// Convert result to JSON
@@ -150,15 +190,7 @@ func handleListUsers(ctx context.Context, request mcp.CallToolRequest, clientMan
 		result, err = cloudClient.CloudService().GetUsers(ctx, getUsersReq)
 	}
 	if err != nil {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error listing users: %v", err),
-				},
-			},
-		}, nil
+		return cloudAPIErrorResult("Error listing users", err, ""), nil
 	}
 
 	resultJSON, err := json.MarshalIndent(result, "", "  ")
@@ -183,3 +215,184 @@ func handleListUsers(ctx context.Context, request mcp.CallToolRequest, clientMan
 		},
 	}, nil
 }
+
+func handleInviteUser(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+
+	email, errResult := requiredString(arguments, "email")
+	if errResult != nil {
+		return errResult, nil
+	}
+	roleStr, errResult := requiredString(arguments, "account_role")
+	if errResult != nil {
+		return errResult, nil
+	}
+	role, ok := parseAccountRole(roleStr)
+	if !ok || role == identity.AccountAccess_ROLE_OWNER {
+		return errorResult("Error: account_role must be one of admin, developer, finance_admin or read"), nil
+	}
+
+	namespaceAccesses := map[string]*identity.NamespaceAccess{}
+	if raw, ok := arguments["namespace_accesses"]; ok && raw != nil {
+		accesses, ok := raw.(map[string]interface{})
+		if !ok {
+			return errorResult("Error: namespace_accesses must be an object mapping namespace to permission"), nil
+		}
+		for ref, permissionRaw := range accesses {
+			permissionStr, _ := permissionRaw.(string)
+			permission, ok := parseNamespacePermission(permissionStr)
+			if !ok {
+				return errorResult("Error: permission for namespace %s must be admin, write or read", ref), nil
+			}
+			namespace, errResult := resolveNamespace(ctx, clientManager, ref)
+			if errResult != nil {
+				return errResult, nil
+			}
+			namespaceAccesses[namespace] = &identity.NamespaceAccess{Permission: permission}
+		}
+	}
+
+	createReq := &cloudservice.CreateUserRequest{
+		Spec: &identity.UserSpec{
+			Email: email,
+			Access: &identity.Access{
+				AccountAccess:     &identity.AccountAccess{Role: role},
+				NamespaceAccesses: namespaceAccesses,
+			},
+		},
+		AsyncOperationId: uuid.New().String(),
+	}
+	resp, err := callCloudService(ctx, clientManager, workflows.CreateUserWorkflowType, createReq,
+		clientManager.GetCloudClient().CloudService().CreateUser)
+	if err != nil {
+		return cloudAPIErrorResult("Error inviting user", err, ""), nil
+	}
+
+	operation, err := waitForAsyncOperation(ctx, clientManager, resp.AsyncOperation.GetId(), asyncOperationTimeout(arguments))
+	if err != nil {
+		return cloudAPIErrorResult("Error waiting for user invite", err, ""), nil
+	}
+	return jsonResult(map[string]interface{}{
+		"user_id":            resp.UserId,
+		"email":              email,
+		"account_role":       role.String(),
+		"namespace_accesses": namespaceAccesses,
+		"async_operation":    operation,
+	}), nil
+}
+
+func handleSetUserAccountRole(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+
+	userID, errResult := requiredString(arguments, "user_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+	roleStr, errResult := requiredString(arguments, "account_role")
+	if errResult != nil {
+		return errResult, nil
+	}
+	role, ok := parseAccountRole(roleStr)
+	if !ok || role == identity.AccountAccess_ROLE_OWNER {
+		return errorResult("Error: account_role must be one of admin, developer, finance_admin or read"), nil
+	}
+	userID, errResult = resolveUserID(ctx, clientManager, userID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	user, err := getUserForUpdate(ctx, clientManager, userID)
+	if err != nil {
+		return cloudAPIErrorResult("Error getting user", err, ""), nil
+	}
+	spec := proto.Clone(user.Spec).(*identity.UserSpec)
+	if spec.Access == nil {
+		spec.Access = &identity.Access{}
+	}
+	previousRole := spec.Access.GetAccountAccess().GetRole()
+	spec.Access.AccountAccess = &identity.AccountAccess{Role: role}
+
+	resourceVersion := optionalString(arguments, "resource_version")
+	if resourceVersion == "" {
+		resourceVersion = user.ResourceVersion
+	}
+	updateReq := &cloudservice.UpdateUserRequest{
+		UserId:           userID,
+		Spec:             spec,
+		ResourceVersion:  resourceVersion,
+		AsyncOperationId: uuid.New().String(),
+	}
+	resp, err := callCloudService(ctx, clientManager, workflows.UpdateUserWorkflowType, updateReq,
+		clientManager.GetCloudClient().CloudService().UpdateUser)
+	if err != nil {
+		return cloudAPIErrorResult("Error updating user account role", err, "temporal_get_user"), nil
+	}
+
+	operation, err := waitForAsyncOperation(ctx, clientManager, resp.AsyncOperation.GetId(), asyncOperationTimeout(arguments))
+	if err != nil {
+		return cloudAPIErrorResult("Error waiting for user update", err, ""), nil
+	}
+	return jsonResult(map[string]interface{}{
+		"user_id":               userID,
+		"email":                 spec.Email,
+		"previous_account_role": previousRole.String(),
+		"account_role":          role.String(),
+		"async_operation":       operation,
+	}), nil
+}
+
+func handleDeleteUser(ctx context.Context, request mcp.CallToolRequest, clientManager *clients.ClientManager) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+
+	userID, errResult := requiredString(arguments, "user_id")
+	if errResult != nil {
+		return errResult, nil
+	}
+	userID, errResult = resolveUserID(ctx, clientManager, userID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	user, err := getUserForUpdate(ctx, clientManager, userID)
+	if err != nil {
+		return cloudAPIErrorResult("Error getting user", err, ""), nil
+	}
+	resourceVersion := optionalString(arguments, "resource_version")
+	if resourceVersion == "" {
+		resourceVersion = user.ResourceVersion
+	}
+
+	deleteReq := &cloudservice.DeleteUserRequest{
+		UserId:           userID,
+		ResourceVersion:  resourceVersion,
+		AsyncOperationId: uuid.New().String(),
+	}
+	resp, err := callCloudService(ctx, clientManager, workflows.DeleteUserWorkflowType, deleteReq,
+		clientManager.GetCloudClient().CloudService().DeleteUser)
+	if err != nil {
+		return cloudAPIErrorResult("Error deleting user", err, "temporal_get_user"), nil
+	}
+
+	operation, err := waitForAsyncOperation(ctx, clientManager, resp.AsyncOperation.GetId(), asyncOperationTimeout(arguments))
+	if err != nil {
+		return cloudAPIErrorResult("Error waiting for user deletion", err, ""), nil
+	}
+	return jsonResult(map[string]interface{}{
+		"deleted_user_id": userID,
+		"email":           user.GetSpec().GetEmail(),
+		"async_operation": operation,
+	}), nil
+}
+
+// getUserForUpdate fetches the user whose spec and resource version an update or delete starts from
+func getUserForUpdate(ctx context.Context, clientManager *clients.ClientManager, userID string) (*identity.User, error) {
+	resp, err := callCloudService(ctx, clientManager, workflows.GetUserWorkflowType, &cloudservice.GetUserRequest{UserId: userID},
+		clientManager.GetCloudClient().CloudService().GetUser)
+	if err != nil {
+		return nil, err
+	}
+	if resp.User == nil || resp.User.Spec == nil {
+		return nil, fmt.Errorf("user %s has no specification", userID)
+	}
+	return resp.User, nil
+}
@@ -7,8 +7,10 @@ import (
 
 	"bechols/temcp/client/api"
 	"bechols/temcp/client/temporal"
+	"bechols/temcp/internal/telemetry"
 	"bechols/temcp/workflows"
 	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/interceptor"
 	"go.temporal.io/sdk/worker"
 	"go.temporal.io/server/common/log"
 
@@ -21,6 +23,8 @@ const (
 	temporalCloudNamespaceAPIKeyEnvName  = "TEMPORAL_CLOUD_NAMESPACE_API_KEY"
 	temporalCloudNamespaceTLSCertPathEnv = "TEMPORAL_CLOUD_NAMESPACE_TLS_CERT"
 	temporalCloudNamespaceTLSKeyPathEnv  = "TEMPORAL_CLOUD_NAMESPACE_TLS_KEY"
+	tracesExporterEnvName                = "OTEL_TRACES_EXPORTER"
+	tracesFileEnvName                    = "TEMCP_TRACES_FILE"
 )
 
 func main() {
@@ -32,6 +36,15 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	shutdownTracing, err := telemetry.Setup(context.Background(), telemetry.Options{
+		Exporter:    os.Getenv(tracesExporterEnvName),
+		FilePath:    os.Getenv(tracesFileEnvName),
+		ServiceName: "temcp-worker",
+	})
+	if err != nil {
+		panic(fmt.Errorf("failed to set up tracing: %+v", err))
+	}
+	defer shutdownTracing(context.Background())
 	c, err := newTemporalClient(logger)
 	if err != nil {
 		panic(fmt.Errorf("failed to create temporal client: %+v", err))
@@ -51,9 +64,14 @@ func main() {
 }
 
 func newTemporalClient(logger *zap.Logger) (client.Client, error) {
+	tracingInterceptor, err := telemetry.NewTemporalInterceptor()
+	if err != nil {
+		return nil, err
+	}
+	interceptors := []interceptor.ClientInterceptor{tracingInterceptor}
 	ns := os.Getenv(temporalCloudNamespaceEnvName)
 	if ns == "" {
-		return client.Dial(client.Options{})
+		return client.Dial(client.Options{Interceptors: interceptors})
 	}
 	var auth temporal.AuthType
 	if os.Getenv(temporalCloudNamespaceTLSKeyPathEnv) != "" || os.Getenv(temporalCloudNamespaceTLSCertPathEnv) != "" {
@@ -77,9 +95,10 @@ func newTemporalClient(logger *zap.Logger) (client.Client, error) {
 	return temporal.GetTemporalCloudNamespaceClient(
 		context.Background(),
 		&temporal.GetTemporalCloudNamespaceClientInput{
-			Namespace: ns,
-			Auth:      auth,
-			Logger:    log.NewSdkLogger(log.NewZapLogger(logger)),
+			Namespace:    ns,
+			Auth:         auth,
+			Logger:       log.NewSdkLogger(log.NewZapLogger(logger)),
+			Interceptors: interceptors,
 		},
 	)
 }
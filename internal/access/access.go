@@ -0,0 +1,212 @@
+package access
+
+import (
+	"fmt"
+
+	"go.temporal.io/cloud-sdk/api/identity/v1"
+)
+
+// Where a grant comes from
+const (
+	SourceAccountRole      = "account_role"
+	SourceDirect           = "direct"
+	SourceNamespaceScoped  = "namespace_scoped"
+	SourceGroup            = "group"
+	SourceGroupAccountRole = "group_account_role"
+)
+
+// PermissionNone is reported when a principal has no access to the namespace
+const PermissionNone = "NONE"
+
+// Grant is one reason a principal has access to a namespace
+type Grant struct {
+	Source     string `json:"source"`
+	Permission string `json:"permission"`
+	GroupID    string `json:"group_id,omitempty"`
+	GroupName  string `json:"group_name,omitempty"`
+	Detail     string `json:"detail"`
+}
+
+// Explanation is the effective permission of a principal on a namespace and the grants it is made of
+type Explanation struct {
+	PrincipalType       string   `json:"principal_type"`
+	PrincipalID         string   `json:"principal_id"`
+	Principal           string   `json:"principal"`
+	Namespace           string   `json:"namespace"`
+	AccountRole         string   `json:"account_role,omitempty"`
+	EffectivePermission string   `json:"effective_permission"`
+	Grants              []Grant  `json:"grants"`
+	Notes               []string `json:"notes,omitempty"`
+
+	effective identity.NamespaceAccess_Permission
+}
+
+// The rules are the same wherever the account data comes from: a principal's access is the highest of
+//   - its account role: owners and global admins are namespace admins everywhere
+//   - its direct namespace grants, or the single grant of a namespace-scoped service account
+//   - for users, the namespace grants and account roles of the user groups it is a member of
+//
+// Google group membership lives in Google Workspace and can't be read through the Cloud API,
+// so Google groups that would grant access are noted rather than counted.
+
+// ExplainUser explains the access of the user to the namespace, given the groups the user is a member of
+func ExplainUser(user *identity.User, memberOf []*identity.UserGroup, namespace string) *Explanation {
+	e := &Explanation{
+		PrincipalType: "user",
+		PrincipalID:   user.GetId(),
+		Principal:     user.GetSpec().GetEmail(),
+		Namespace:     namespace,
+		Grants:        []Grant{},
+	}
+	e.addAccess(user.GetSpec().GetAccess(), namespace, "user")
+	for _, group := range memberOf {
+		e.addGroup(group, namespace)
+	}
+	e.finish()
+	return e
+}
+
+// ExplainServiceAccount explains the access of the service account to the namespace
+func ExplainServiceAccount(serviceAccount *identity.ServiceAccount, namespace string) *Explanation {
+	spec := serviceAccount.GetSpec()
+	e := &Explanation{
+		PrincipalType: "service-account",
+		PrincipalID:   serviceAccount.GetId(),
+		Principal:     spec.GetName(),
+		Namespace:     namespace,
+		Grants:        []Grant{},
+	}
+	if scoped := spec.GetNamespaceScopedAccess(); scoped != nil {
+		if scoped.GetNamespace() == namespace && scoped.GetAccess() != nil {
+			e.add(Grant{
+				Source:     SourceNamespaceScoped,
+				Permission: scoped.GetAccess().GetPermission().String(),
+				Detail:     "namespace-scoped service account",
+			}, scoped.GetAccess().GetPermission())
+		} else {
+			e.Notes = append(e.Notes, fmt.Sprintf("namespace-scoped service account, limited to %s", scoped.GetNamespace()))
+		}
+	} else {
+		e.addAccess(spec.GetAccess(), namespace, "service account")
+	}
+	e.finish()
+	return e
+}
+
+// GrantingGroups returns the groups that grant something on the namespace to their members,
+// the only groups whose membership matters for the namespace
+func GrantingGroups(groups []*identity.UserGroup, namespace string) []*identity.UserGroup {
+	var granting []*identity.UserGroup
+	for _, group := range groups {
+		groupAccess := group.GetSpec().GetAccess()
+		if grantFromAccountRole(groupAccess.GetAccountAccess().GetRole()) != identity.NamespaceAccess_PERMISSION_UNSPECIFIED ||
+			groupAccess.GetNamespaceAccesses()[namespace] != nil {
+			granting = append(granting, group)
+		}
+	}
+	return granting
+}
+
+// MembershipReadable reports whether the group's members can be read through the Cloud API
+func MembershipReadable(group *identity.UserGroup) bool {
+	return group.GetSpec().GetGoogleGroup() == nil
+}
+
+// UnreadableGroupNote describes a Google group that grants access but whose members can't be read
+func UnreadableGroupNote(group *identity.UserGroup) string {
+	return fmt.Sprintf("Google group %q (%s, %s) grants access to the namespace; membership can't be checked through the Cloud API",
+		group.GetSpec().GetDisplayName(), group.GetId(), group.GetSpec().GetGoogleGroup().GetEmailAddress())
+}
+
+// addAccess adds the grants of the principal's own account role and namespace accesses
+func (e *Explanation) addAccess(access *identity.Access, namespace, kind string) {
+	role := access.GetAccountAccess().GetRole()
+	if role != identity.AccountAccess_ROLE_UNSPECIFIED {
+		e.AccountRole = role.String()
+	}
+	if permission := grantFromAccountRole(role); permission != identity.NamespaceAccess_PERMISSION_UNSPECIFIED {
+		e.add(Grant{
+			Source:     SourceAccountRole,
+			Permission: permission.String(),
+			Detail:     fmt.Sprintf("%s account role %s is namespace admin on every namespace", kind, role),
+		}, permission)
+	}
+	if direct := access.GetNamespaceAccesses()[namespace]; direct != nil {
+		e.add(Grant{
+			Source:     SourceDirect,
+			Permission: direct.GetPermission().String(),
+			Detail:     fmt.Sprintf("granted to the %s on the namespace", kind),
+		}, direct.GetPermission())
+	}
+}
+
+// addGroup adds the grants the user gets from being a member of the group
+func (e *Explanation) addGroup(group *identity.UserGroup, namespace string) {
+	access := group.GetSpec().GetAccess()
+	name := group.GetSpec().GetDisplayName()
+	role := access.GetAccountAccess().GetRole()
+	if permission := grantFromAccountRole(role); permission != identity.NamespaceAccess_PERMISSION_UNSPECIFIED {
+		e.add(Grant{
+			Source:     SourceGroupAccountRole,
+			Permission: permission.String(),
+			GroupID:    group.GetId(),
+			GroupName:  name,
+			Detail:     fmt.Sprintf("member of group %q, whose account role %s is namespace admin on every namespace", name, role),
+		}, permission)
+	}
+	if grant := access.GetNamespaceAccesses()[namespace]; grant != nil {
+		e.add(Grant{
+			Source:     SourceGroup,
+			Permission: grant.GetPermission().String(),
+			GroupID:    group.GetId(),
+			GroupName:  name,
+			Detail:     fmt.Sprintf("member of group %q, which is granted the namespace", name),
+		}, grant.GetPermission())
+	}
+}
+
+func (e *Explanation) add(grant Grant, permission identity.NamespaceAccess_Permission) {
+	e.Grants = append(e.Grants, grant)
+	if rank(permission) > rank(e.effective) {
+		e.effective = permission
+	}
+}
+
+func (e *Explanation) finish() {
+	e.EffectivePermission = PermissionNone
+	if e.effective != identity.NamespaceAccess_PERMISSION_UNSPECIFIED {
+		e.EffectivePermission = e.effective.String()
+	}
+	if len(e.Grants) == 0 && e.AccountRole != "" {
+		e.Notes = append(e.Notes, fmt.Sprintf("account role %s doesn't grant namespace access on its own", e.AccountRole))
+	}
+}
+
+// HasAccess reports whether the explanation found any grant on the namespace
+func (e *Explanation) HasAccess() bool {
+	return e.effective != identity.NamespaceAccess_PERMISSION_UNSPECIFIED
+}
+
+// grantFromAccountRole returns the namespace permission an account role implies on every namespace
+func grantFromAccountRole(role identity.AccountAccess_Role) identity.NamespaceAccess_Permission {
+	switch role {
+	case identity.AccountAccess_ROLE_OWNER, identity.AccountAccess_ROLE_ADMIN:
+		return identity.NamespaceAccess_PERMISSION_ADMIN
+	default:
+		return identity.NamespaceAccess_PERMISSION_UNSPECIFIED
+	}
+}
+
+// rank orders permissions from none to admin; the enum values run the other way
+func rank(permission identity.NamespaceAccess_Permission) int {
+	switch permission {
+	case identity.NamespaceAccess_PERMISSION_ADMIN:
+		return 3
+	case identity.NamespaceAccess_PERMISSION_WRITE:
+		return 2
+	case identity.NamespaceAccess_PERMISSION_READ:
+		return 1
+	default:
+		return 0
+	}
+}
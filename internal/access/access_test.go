@@ -0,0 +1,191 @@
+package access
+
+import (
+	"reflect"
+	"testing"
+
+	"go.temporal.io/cloud-sdk/api/identity/v1"
+)
+
+const (
+	read  = identity.NamespaceAccess_PERMISSION_READ
+	write = identity.NamespaceAccess_PERMISSION_WRITE
+	admin = identity.NamespaceAccess_PERMISSION_ADMIN
+)
+
+// grants returns account access with the role and namespace permissions
+func grants(role identity.AccountAccess_Role, namespaces map[string]identity.NamespaceAccess_Permission) *identity.Access {
+	access := &identity.Access{
+		AccountAccess:     &identity.AccountAccess{Role: role},
+		NamespaceAccesses: map[string]*identity.NamespaceAccess{},
+	}
+	for namespace, permission := range namespaces {
+		access.NamespaceAccesses[namespace] = &identity.NamespaceAccess{Permission: permission}
+	}
+	return access
+}
+
+func user(id, email string, access *identity.Access) *identity.User {
+	return &identity.User{Id: id, Spec: &identity.UserSpec{Email: email, Access: access}}
+}
+
+func group(id, name string, access *identity.Access) *identity.UserGroup {
+	return &identity.UserGroup{Id: id, Spec: &identity.UserGroupSpec{DisplayName: name, Access: access}}
+}
+
+func googleGroup(id, name string, access *identity.Access) *identity.UserGroup {
+	g := group(id, name, access)
+	g.Spec.GroupType = &identity.UserGroupSpec_GoogleGroup{GoogleGroup: &identity.GoogleGroupSpec{EmailAddress: name + "@example.com"}}
+	return g
+}
+
+func serviceAccount(id, name string, access *identity.Access) *identity.ServiceAccount {
+	return &identity.ServiceAccount{Id: id, Spec: &identity.ServiceAccountSpec{Name: name, Access: access}}
+}
+
+func sources(e *Explanation) []string {
+	got := []string{}
+	for _, grant := range e.Grants {
+		got = append(got, grant.Source+" "+grant.Permission)
+	}
+	return got
+}
+
+func TestExplainUser(t *testing.T) {
+	const ns = "orders.acct"
+	tests := []struct {
+		name      string
+		access    *identity.Access
+		memberOf  []*identity.UserGroup
+		want      string
+		wantGrant []string
+		wantNotes int
+	}{
+		{
+			name:      "no access",
+			access:    grants(identity.AccountAccess_ROLE_READ, nil),
+			want:      PermissionNone,
+			wantGrant: []string{},
+			wantNotes: 1,
+		},
+		{
+			name:      "direct grant",
+			access:    grants(identity.AccountAccess_ROLE_READ, map[string]identity.NamespaceAccess_Permission{ns: write}),
+			want:      "PERMISSION_WRITE",
+			wantGrant: []string{"direct PERMISSION_WRITE"},
+		},
+		{
+			name:      "grant on another namespace",
+			access:    grants(identity.AccountAccess_ROLE_DEVELOPER, map[string]identity.NamespaceAccess_Permission{"other.acct": admin}),
+			want:      PermissionNone,
+			wantGrant: []string{},
+			wantNotes: 1,
+		},
+		{
+			name:      "account admin",
+			access:    grants(identity.AccountAccess_ROLE_ADMIN, map[string]identity.NamespaceAccess_Permission{ns: read}),
+			want:      "PERMISSION_ADMIN",
+			wantGrant: []string{"account_role PERMISSION_ADMIN", "direct PERMISSION_READ"},
+		},
+		{
+			name:   "highest of direct and group grants",
+			access: grants(identity.AccountAccess_ROLE_READ, map[string]identity.NamespaceAccess_Permission{ns: read}),
+			memberOf: []*identity.UserGroup{
+				group("g1", "writers", grants(identity.AccountAccess_ROLE_UNSPECIFIED, map[string]identity.NamespaceAccess_Permission{ns: write})),
+			},
+			want:      "PERMISSION_WRITE",
+			wantGrant: []string{"direct PERMISSION_READ", "group PERMISSION_WRITE"},
+		},
+		{
+			name:   "group account role",
+			access: grants(identity.AccountAccess_ROLE_READ, nil),
+			memberOf: []*identity.UserGroup{
+				group("g1", "owners", grants(identity.AccountAccess_ROLE_OWNER, nil)),
+			},
+			want:      "PERMISSION_ADMIN",
+			wantGrant: []string{"group_account_role PERMISSION_ADMIN"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := ExplainUser(user("u1", "a@example.com", tt.access), tt.memberOf, ns)
+			if e.EffectivePermission != tt.want {
+				t.Errorf("EffectivePermission = %s, want %s", e.EffectivePermission, tt.want)
+			}
+			if e.HasAccess() != (tt.want != PermissionNone) {
+				t.Errorf("HasAccess() = %v with permission %s", e.HasAccess(), e.EffectivePermission)
+			}
+			if got := sources(e); !reflect.DeepEqual(got, tt.wantGrant) {
+				t.Errorf("grants = %v, want %v", got, tt.wantGrant)
+			}
+			if len(e.Notes) != tt.wantNotes {
+				t.Errorf("notes = %v, want %d", e.Notes, tt.wantNotes)
+			}
+		})
+	}
+}
+
+func TestExplainServiceAccount(t *testing.T) {
+	const ns = "orders.acct"
+	scoped := func(namespace string) *identity.ServiceAccount {
+		sa := serviceAccount("sa1", "worker", nil)
+		sa.Spec.NamespaceScopedAccess = &identity.NamespaceScopedAccess{Namespace: namespace, Access: &identity.NamespaceAccess{Permission: write}}
+		return sa
+	}
+	tests := []struct {
+		name      string
+		sa        *identity.ServiceAccount
+		want      string
+		wantGrant []string
+		wantNotes int
+	}{
+		{
+			name:      "direct grant",
+			sa:        serviceAccount("sa1", "worker", grants(identity.AccountAccess_ROLE_READ, map[string]identity.NamespaceAccess_Permission{ns: read})),
+			want:      "PERMISSION_READ",
+			wantGrant: []string{"direct PERMISSION_READ"},
+		},
+		{
+			name:      "account admin",
+			sa:        serviceAccount("sa1", "ci", grants(identity.AccountAccess_ROLE_ADMIN, nil)),
+			want:      "PERMISSION_ADMIN",
+			wantGrant: []string{"account_role PERMISSION_ADMIN"},
+		},
+		{name: "scoped to the namespace", sa: scoped(ns), want: "PERMISSION_WRITE", wantGrant: []string{"namespace_scoped PERMISSION_WRITE"}},
+		{name: "scoped to another namespace", sa: scoped("other.acct"), want: PermissionNone, wantGrant: []string{}, wantNotes: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := ExplainServiceAccount(tt.sa, ns)
+			if e.EffectivePermission != tt.want {
+				t.Errorf("EffectivePermission = %s, want %s", e.EffectivePermission, tt.want)
+			}
+			if got := sources(e); !reflect.DeepEqual(got, tt.wantGrant) {
+				t.Errorf("grants = %v, want %v", got, tt.wantGrant)
+			}
+			if len(e.Notes) != tt.wantNotes {
+				t.Errorf("notes = %v, want %d", e.Notes, tt.wantNotes)
+			}
+		})
+	}
+}
+
+func TestGrantingGroups(t *testing.T) {
+	const ns = "orders.acct"
+	groups := []*identity.UserGroup{
+		group("g1", "readers", grants(identity.AccountAccess_ROLE_UNSPECIFIED, map[string]identity.NamespaceAccess_Permission{ns: read})),
+		group("g2", "others", grants(identity.AccountAccess_ROLE_DEVELOPER, map[string]identity.NamespaceAccess_Permission{"other.acct": admin})),
+		group("g3", "admins", grants(identity.AccountAccess_ROLE_ADMIN, nil)),
+		googleGroup("g4", "google", grants(identity.AccountAccess_ROLE_UNSPECIFIED, map[string]identity.NamespaceAccess_Permission{ns: write})),
+	}
+	var got []string
+	for _, g := range GrantingGroups(groups, ns) {
+		got = append(got, g.GetId())
+	}
+	if want := []string{"g1", "g3", "g4"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GrantingGroups() = %v, want %v", got, want)
+	}
+	if MembershipReadable(groups[3]) || !MembershipReadable(groups[0]) {
+		t.Error("MembershipReadable() should be false only for the Google group")
+	}
+}
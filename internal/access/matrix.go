@@ -0,0 +1,268 @@
+package access
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.temporal.io/cloud-sdk/api/identity/v1"
+)
+
+const (
+	// DefaultMaxAccountAdmins is how many users may hold the owner or admin account role before the matrix flags it
+	DefaultMaxAccountAdmins = 3
+
+	// Risk severities
+	SeverityHigh   = "high"
+	SeverityMedium = "medium"
+
+	// Risk checks
+	CheckManyAccountAdmins               = "many_account_admins"
+	CheckServiceAccountAccountAdmin      = "service_account_account_admin"
+	CheckServiceAccountAdminOnProduction = "service_account_admin_on_production"
+)
+
+// DefaultProductionPatterns mark namespaces whose ID contains "prod" as production
+var DefaultProductionPatterns = []string{"prod"}
+
+// MatrixOptions tune the risk checks of an access matrix
+type MatrixOptions struct {
+	// MaxAccountAdmins is how many users may be account owners or admins, directly or through a group
+	MaxAccountAdmins int `json:"max_account_admins"`
+	// ProductionPatterns are case-insensitive substrings of namespace IDs that mark a namespace as production
+	ProductionPatterns []string `json:"production_patterns"`
+}
+
+func (o MatrixOptions) withDefaults() MatrixOptions {
+	if o.MaxAccountAdmins <= 0 {
+		o.MaxAccountAdmins = DefaultMaxAccountAdmins
+	}
+	if len(o.ProductionPatterns) == 0 {
+		o.ProductionPatterns = DefaultProductionPatterns
+	}
+	return o
+}
+
+func (o MatrixOptions) isProduction(namespace string) bool {
+	for _, pattern := range o.ProductionPatterns {
+		if pattern != "" && strings.Contains(strings.ToLower(namespace), strings.ToLower(pattern)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matrix is the effective permission of every principal on every namespace
+type Matrix struct {
+	GeneratedTime time.Time    `json:"generated_time"`
+	Namespaces    []string     `json:"namespaces"`
+	Rows          []*MatrixRow `json:"rows"`
+	Risks         []Risk       `json:"risks"`
+	Notes         []string     `json:"notes,omitempty"`
+}
+
+// MatrixRow is one principal's permissions, keyed by namespace. Namespaces it can't access are left out.
+type MatrixRow struct {
+	PrincipalType string            `json:"principal_type"`
+	PrincipalID   string            `json:"principal_id"`
+	Principal     string            `json:"principal"`
+	AccountRole   string            `json:"account_role,omitempty"`
+	Permissions   map[string]string `json:"permissions"`
+}
+
+// Risk is an access pattern worth a second look in a review
+type Risk struct {
+	Severity    string `json:"severity"`
+	Check       string `json:"check"`
+	PrincipalID string `json:"principal_id,omitempty"`
+	Principal   string `json:"principal,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	Detail      string `json:"detail"`
+}
+
+// BuildMatrix computes the access matrix of the account over the namespaces and flags risky patterns.
+// GroupMembers must hold the members of every group whose membership is readable.
+func BuildMatrix(namespaces []string, account *Account, opts MatrixOptions, now time.Time) *Matrix {
+	opts = opts.withDefaults()
+	namespaces = append([]string(nil), namespaces...)
+	sort.Strings(namespaces)
+	m := &Matrix{
+		GeneratedTime: now,
+		Namespaces:    namespaces,
+		Rows:          []*MatrixRow{},
+		Risks:         []Risk{},
+	}
+
+	memberOf := map[string][]*identity.UserGroup{}
+	for _, group := range account.Groups {
+		if !MembershipReadable(group) {
+			if isAccountAdmin(group.GetSpec().GetAccess(), nil) || len(group.GetSpec().GetAccess().GetNamespaceAccesses()) > 0 {
+				m.Notes = append(m.Notes, fmt.Sprintf("Google group %q (%s) grants access, but its members can't be read through the Cloud API and aren't in the matrix",
+					group.GetSpec().GetDisplayName(), group.GetId()))
+			}
+			continue
+		}
+		for _, userID := range account.GroupMembers[group.GetId()] {
+			memberOf[userID] = append(memberOf[userID], group)
+		}
+	}
+
+	var admins []string
+	for _, user := range account.Users {
+		row := &MatrixRow{
+			PrincipalType: "user",
+			PrincipalID:   user.GetId(),
+			Principal:     user.GetSpec().GetEmail(),
+			Permissions:   map[string]string{},
+		}
+		if role := user.GetSpec().GetAccess().GetAccountAccess().GetRole(); role != identity.AccountAccess_ROLE_UNSPECIFIED {
+			row.AccountRole = role.String()
+		}
+		for _, namespace := range namespaces {
+			if e := ExplainUser(user, memberOf[user.GetId()], namespace); e.HasAccess() {
+				row.Permissions[namespace] = e.EffectivePermission
+			}
+		}
+		if isAccountAdmin(user.GetSpec().GetAccess(), memberOf[user.GetId()]) {
+			admins = append(admins, row.Principal)
+		}
+		m.Rows = append(m.Rows, row)
+	}
+	for _, serviceAccount := range account.ServiceAccounts {
+		row := &MatrixRow{
+			PrincipalType: "service-account",
+			PrincipalID:   serviceAccount.GetId(),
+			Principal:     serviceAccount.GetSpec().GetName(),
+			Permissions:   map[string]string{},
+		}
+		saAccess := serviceAccount.GetSpec().GetAccess()
+		if role := saAccess.GetAccountAccess().GetRole(); role != identity.AccountAccess_ROLE_UNSPECIFIED {
+			row.AccountRole = role.String()
+		}
+		for _, namespace := range namespaces {
+			e := ExplainServiceAccount(serviceAccount, namespace)
+			if !e.HasAccess() {
+				continue
+			}
+			row.Permissions[namespace] = e.EffectivePermission
+			if e.effective == identity.NamespaceAccess_PERMISSION_ADMIN && opts.isProduction(namespace) {
+				m.Risks = append(m.Risks, Risk{
+					Severity:    SeverityHigh,
+					Check:       CheckServiceAccountAdminOnProduction,
+					PrincipalID: row.PrincipalID,
+					Principal:   row.Principal,
+					Namespace:   namespace,
+					Detail:      fmt.Sprintf("service account %q is admin on production namespace %s", row.Principal, namespace),
+				})
+			}
+		}
+		if isAccountAdmin(saAccess, nil) {
+			m.Risks = append(m.Risks, Risk{
+				Severity:    SeverityHigh,
+				Check:       CheckServiceAccountAccountAdmin,
+				PrincipalID: row.PrincipalID,
+				Principal:   row.Principal,
+				Detail:      fmt.Sprintf("service account %q has account role %s, which is admin on every namespace", row.Principal, row.AccountRole),
+			})
+		}
+		m.Rows = append(m.Rows, row)
+	}
+	if len(admins) > opts.MaxAccountAdmins {
+		sort.Strings(admins)
+		m.Risks = append(m.Risks, Risk{
+			Severity: SeverityMedium,
+			Check:    CheckManyAccountAdmins,
+			Detail: fmt.Sprintf("%d users are account owners or admins, more than %d: %s",
+				len(admins), opts.MaxAccountAdmins, strings.Join(admins, ", ")),
+		})
+	}
+
+	sort.SliceStable(m.Rows, func(i, j int) bool {
+		a, b := m.Rows[i], m.Rows[j]
+		if a.PrincipalType != b.PrincipalType {
+			return a.PrincipalType < b.PrincipalType
+		}
+		return a.Principal < b.Principal
+	})
+	sort.SliceStable(m.Risks, func(i, j int) bool {
+		return m.Risks[i].Severity == SeverityHigh && m.Risks[j].Severity != SeverityHigh
+	})
+	return m
+}
+
+// isAccountAdmin reports whether the access or one of the groups makes the principal an account owner or admin
+func isAccountAdmin(access *identity.Access, memberOf []*identity.UserGroup) bool {
+	if grantFromAccountRole(access.GetAccountAccess().GetRole()) != identity.NamespaceAccess_PERMISSION_UNSPECIFIED {
+		return true
+	}
+	for _, group := range memberOf {
+		if grantFromAccountRole(group.GetSpec().GetAccess().GetAccountAccess().GetRole()) != identity.NamespaceAccess_PERMISSION_UNSPECIFIED {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Matrix) header() []string {
+	return append([]string{"principal_type", "principal", "principal_id", "account_role"}, m.Namespaces...)
+}
+
+func (m *Matrix) row(r *MatrixRow) []string {
+	cells := []string{r.PrincipalType, r.Principal, r.PrincipalID, r.AccountRole}
+	for _, namespace := range m.Namespaces {
+		cells = append(cells, strings.TrimPrefix(r.Permissions[namespace], "PERMISSION_"))
+	}
+	return cells
+}
+
+// CSV renders the matrix with one row per principal and one column per namespace. Risks and notes
+// are left out so the file loads cleanly into a spreadsheet.
+func (m *Matrix) CSV() (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(m.header()); err != nil {
+		return "", err
+	}
+	for _, r := range m.Rows {
+		if err := w.Write(m.row(r)); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+// Markdown renders the risks, the matrix as a table and the notes
+func (m *Matrix) Markdown() string {
+	var b strings.Builder
+	b.WriteString("# Account access matrix\n\n")
+	fmt.Fprintf(&b, "Generated %s. %d principals, %d namespaces.\n\n", m.GeneratedTime.UTC().Format(time.RFC3339), len(m.Rows), len(m.Namespaces))
+	if len(m.Risks) > 0 {
+		b.WriteString("## Risks\n\n")
+		for _, risk := range m.Risks {
+			fmt.Fprintf(&b, "- **%s** %s: %s\n", risk.Severity, risk.Check, risk.Detail)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("## Matrix\n\n")
+	header := m.header()
+	fmt.Fprintf(&b, "| %s |\n", strings.Join(header, " | "))
+	fmt.Fprintf(&b, "|%s\n", strings.Repeat("---|", len(header)))
+	for _, r := range m.Rows {
+		cells := m.row(r)
+		for i, cell := range cells {
+			cells[i] = strings.ReplaceAll(cell, "|", "\\|")
+		}
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(cells, " | "))
+	}
+	if len(m.Notes) > 0 {
+		b.WriteString("\nNotes:\n\n")
+		for _, note := range m.Notes {
+			fmt.Fprintf(&b, "- %s\n", note)
+		}
+	}
+	return b.String()
+}
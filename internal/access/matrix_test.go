@@ -0,0 +1,113 @@
+package access
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.temporal.io/cloud-sdk/api/identity/v1"
+)
+
+func TestBuildMatrix(t *testing.T) {
+	namespaces := []string{"orders-prod.acct", "orders-dev.acct"}
+	admins := func(n int) []*identity.User {
+		var users []*identity.User
+		for i := 0; i < n; i++ {
+			id := string(rune('a' + i))
+			users = append(users, user(id, id+"@example.com", grants(identity.AccountAccess_ROLE_ADMIN, nil)))
+		}
+		return users
+	}
+	devAdmin := grants(identity.AccountAccess_ROLE_READ, map[string]identity.NamespaceAccess_Permission{"orders-dev.acct": admin})
+	prodAdmin := grants(identity.AccountAccess_ROLE_READ, map[string]identity.NamespaceAccess_Permission{"orders-prod.acct": admin})
+	tests := []struct {
+		name    string
+		account *Account
+		opts    MatrixOptions
+		want    []string
+	}{
+		{name: "no risks", account: &Account{Users: admins(3)}, want: []string{}},
+		{name: "too many admins", account: &Account{Users: admins(4)}, want: []string{"medium many_account_admins"}},
+		{name: "raised admin limit", account: &Account{Users: admins(4)}, opts: MatrixOptions{MaxAccountAdmins: 4}, want: []string{}},
+		{
+			name: "admins through a group",
+			account: &Account{
+				Users:        append(admins(3), user("g", "grouped@example.com", grants(identity.AccountAccess_ROLE_READ, nil))),
+				Groups:       []*identity.UserGroup{group("g1", "admins", grants(identity.AccountAccess_ROLE_ADMIN, nil))},
+				GroupMembers: map[string][]string{"g1": {"g"}},
+			},
+			want: []string{"medium many_account_admins"},
+		},
+		{
+			name:    "service account admin on production",
+			account: &Account{ServiceAccounts: []*identity.ServiceAccount{serviceAccount("sa1", "deployer", prodAdmin)}},
+			want:    []string{"high service_account_admin_on_production orders-prod.acct"},
+		},
+		{
+			name:    "service account admin on development",
+			account: &Account{ServiceAccounts: []*identity.ServiceAccount{serviceAccount("sa1", "deployer", devAdmin)}},
+			want:    []string{},
+		},
+		{
+			name:    "custom production pattern",
+			account: &Account{ServiceAccounts: []*identity.ServiceAccount{serviceAccount("sa1", "deployer", devAdmin)}},
+			opts:    MatrixOptions{ProductionPatterns: []string{"DEV"}},
+			want:    []string{"high service_account_admin_on_production orders-dev.acct"},
+		},
+		{
+			name: "service account account admin",
+			account: &Account{
+				Users:           admins(4),
+				ServiceAccounts: []*identity.ServiceAccount{serviceAccount("sa1", "ci", grants(identity.AccountAccess_ROLE_ADMIN, nil))},
+			},
+			want: []string{
+				"high service_account_admin_on_production orders-prod.acct",
+				"high service_account_account_admin",
+				"medium many_account_admins",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := BuildMatrix(namespaces, tt.account, tt.opts, now)
+			got := []string{}
+			for _, risk := range m.Risks {
+				got = append(got, strings.TrimSpace(risk.Severity+" "+risk.Check+" "+risk.Namespace))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("risks = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatrixRendering(t *testing.T) {
+	account := &Account{
+		Users: []*identity.User{
+			user("u1", "dev@example.com", grants(identity.AccountAccess_ROLE_DEVELOPER, map[string]identity.NamespaceAccess_Permission{"b.acct": write})),
+		},
+		ServiceAccounts: []*identity.ServiceAccount{
+			serviceAccount("sa1", "worker", grants(identity.AccountAccess_ROLE_READ, map[string]identity.NamespaceAccess_Permission{"a.acct": read})),
+		},
+		Groups: []*identity.UserGroup{
+			googleGroup("g1", "google", grants(identity.AccountAccess_ROLE_UNSPECIFIED, map[string]identity.NamespaceAccess_Permission{"a.acct": read})),
+		},
+	}
+	m := BuildMatrix([]string{"b.acct", "a.acct"}, account, MatrixOptions{}, now)
+	csv, err := m.CSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "principal_type,principal,principal_id,account_role,a.acct,b.acct\n" +
+		"service-account,worker,sa1,ROLE_READ,READ,\n" +
+		"user,dev@example.com,u1,ROLE_DEVELOPER,,WRITE\n"
+	if csv != want {
+		t.Errorf("CSV() =\n%s\nwant\n%s", csv, want)
+	}
+	if len(m.Notes) != 1 {
+		t.Errorf("notes = %v, want one about the Google group", m.Notes)
+	}
+	if markdown := m.Markdown(); !strings.Contains(markdown, "| user | dev@example.com | u1 | ROLE_DEVELOPER |  | WRITE |") {
+		t.Errorf("Markdown() =\n%s", markdown)
+	}
+}
@@ -0,0 +1,183 @@
+package access
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.temporal.io/cloud-sdk/api/identity/v1"
+)
+
+// Account is the account data a namespace report is computed from
+type Account struct {
+	Users           []*identity.User
+	ServiceAccounts []*identity.ServiceAccount
+	Groups          []*identity.UserGroup
+	// GroupMembers maps a group ID to the user IDs of its members. Only the granting groups
+	// whose membership is readable need to be present.
+	GroupMembers map[string][]string
+	ApiKeys      []*identity.ApiKey
+}
+
+// NamespaceReport lists every principal with effective access to a namespace
+type NamespaceReport struct {
+	Namespace     string         `json:"namespace"`
+	GeneratedTime time.Time      `json:"generated_time"`
+	Entries       []*ReportEntry `json:"entries"`
+	Notes         []string       `json:"notes,omitempty"`
+}
+
+// ReportEntry is a principal with access to the namespace
+type ReportEntry struct {
+	PrincipalType string         `json:"principal_type"`
+	PrincipalID   string         `json:"principal_id"`
+	Principal     string         `json:"principal"`
+	Permission    string         `json:"permission"`
+	Grants        []Grant        `json:"grants"`
+	ApiKeys       []ReportApiKey `json:"api_keys"`
+
+	permission identity.NamespaceAccess_Permission
+}
+
+// ReportApiKey is an API key that can act on the namespace with its owner's access
+type ReportApiKey struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+	State       string `json:"state"`
+	ExpiryTime  string `json:"expiry_time,omitempty"`
+}
+
+// BuildNamespaceReport computes the namespace report from the account data. Entries are ordered by
+// permission, then principal type and name, so the same account data always gives the same report.
+func BuildNamespaceReport(namespace string, account *Account, now time.Time) *NamespaceReport {
+	report := &NamespaceReport{
+		Namespace:     namespace,
+		GeneratedTime: now,
+		Entries:       []*ReportEntry{},
+	}
+
+	memberOf := map[string][]*identity.UserGroup{}
+	for _, group := range GrantingGroups(account.Groups, namespace) {
+		if !MembershipReadable(group) {
+			report.Notes = append(report.Notes, UnreadableGroupNote(group))
+			continue
+		}
+		for _, userID := range account.GroupMembers[group.GetId()] {
+			memberOf[userID] = append(memberOf[userID], group)
+		}
+	}
+
+	keysByOwner := map[string][]ReportApiKey{}
+	for _, key := range account.ApiKeys {
+		spec := key.GetSpec()
+		reportKey := ReportApiKey{
+			ID:          key.GetId(),
+			DisplayName: spec.GetDisplayName(),
+			State:       "active",
+		}
+		if spec.GetExpiryTime() != nil {
+			reportKey.ExpiryTime = spec.GetExpiryTime().AsTime().Format(time.RFC3339)
+		}
+		switch {
+		case spec.GetDisabled():
+			reportKey.State = "disabled"
+		case spec.GetExpiryTime() != nil && !spec.GetExpiryTime().AsTime().After(now):
+			reportKey.State = "expired"
+		}
+		keysByOwner[spec.GetOwnerId()] = append(keysByOwner[spec.GetOwnerId()], reportKey)
+	}
+
+	addEntry := func(e *Explanation) {
+		if !e.HasAccess() {
+			return
+		}
+		keys := keysByOwner[e.PrincipalID]
+		if keys == nil {
+			keys = []ReportApiKey{}
+		}
+		report.Entries = append(report.Entries, &ReportEntry{
+			PrincipalType: e.PrincipalType,
+			PrincipalID:   e.PrincipalID,
+			Principal:     e.Principal,
+			Permission:    e.EffectivePermission,
+			Grants:        e.Grants,
+			ApiKeys:       keys,
+			permission:    e.effective,
+		})
+	}
+	for _, user := range account.Users {
+		addEntry(ExplainUser(user, memberOf[user.GetId()], namespace))
+	}
+	for _, serviceAccount := range account.ServiceAccounts {
+		addEntry(ExplainServiceAccount(serviceAccount, namespace))
+	}
+
+	sort.SliceStable(report.Entries, func(i, j int) bool {
+		a, b := report.Entries[i], report.Entries[j]
+		if a.permission != b.permission {
+			return rank(a.permission) > rank(b.permission)
+		}
+		if a.PrincipalType != b.PrincipalType {
+			return a.PrincipalType < b.PrincipalType
+		}
+		return a.Principal < b.Principal
+	})
+	return report
+}
+
+// reportColumns are the columns of the CSV and markdown renderings
+var reportColumns = []string{"principal_type", "principal", "principal_id", "permission", "grants", "api_keys"}
+
+func (e *ReportEntry) row() []string {
+	grants := make([]string, 0, len(e.Grants))
+	for _, grant := range e.Grants {
+		grants = append(grants, fmt.Sprintf("%s: %s", grant.Permission, grant.Detail))
+	}
+	keys := make([]string, 0, len(e.ApiKeys))
+	for _, key := range e.ApiKeys {
+		keys = append(keys, fmt.Sprintf("%s (%s, %s)", key.DisplayName, key.ID, key.State))
+	}
+	return []string{e.PrincipalType, e.Principal, e.PrincipalID, e.Permission, strings.Join(grants, "; "), strings.Join(keys, "; ")}
+}
+
+// CSV renders the report with one row per principal
+func (r *NamespaceReport) CSV() (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(reportColumns); err != nil {
+		return "", err
+	}
+	for _, entry := range r.Entries {
+		if err := w.Write(entry.row()); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+// Markdown renders the report as a heading, a table with one row per principal and the notes
+func (r *NamespaceReport) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Access to namespace %s\n\n", r.Namespace)
+	fmt.Fprintf(&b, "Generated %s. %d principals have access.\n\n", r.GeneratedTime.UTC().Format(time.RFC3339), len(r.Entries))
+	fmt.Fprintf(&b, "| %s |\n", strings.Join(reportColumns, " | "))
+	fmt.Fprintf(&b, "|%s\n", strings.Repeat("---|", len(reportColumns)))
+	for _, entry := range r.Entries {
+		cells := entry.row()
+		for i, cell := range cells {
+			cells[i] = strings.ReplaceAll(cell, "|", "\\|")
+		}
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(cells, " | "))
+	}
+	if len(r.Notes) > 0 {
+		b.WriteString("\nNotes:\n\n")
+		for _, note := range r.Notes {
+			fmt.Fprintf(&b, "- %s\n", note)
+		}
+	}
+	return b.String()
+}
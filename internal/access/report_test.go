@@ -0,0 +1,88 @@
+package access
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.temporal.io/cloud-sdk/api/identity/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var now = time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+
+func apiKey(id, owner string, disabled bool, expiry time.Time) *identity.ApiKey {
+	return &identity.ApiKey{Id: id, Spec: &identity.ApiKeySpec{
+		OwnerId:     owner,
+		DisplayName: id,
+		Disabled:    disabled,
+		ExpiryTime:  timestamppb.New(expiry),
+	}}
+}
+
+func TestBuildNamespaceReport(t *testing.T) {
+	const ns = "orders.acct"
+	none := grants(identity.AccountAccess_ROLE_READ, nil)
+	account := &Account{
+		Users: []*identity.User{
+			user("u1", "reader@example.com", grants(identity.AccountAccess_ROLE_READ, map[string]identity.NamespaceAccess_Permission{ns: read})),
+			user("u2", "member@example.com", none),
+			user("u3", "owner@example.com", grants(identity.AccountAccess_ROLE_OWNER, nil)),
+			user("u4", "nobody@example.com", none),
+		},
+		ServiceAccounts: []*identity.ServiceAccount{
+			serviceAccount("sa1", "worker", grants(identity.AccountAccess_ROLE_READ, map[string]identity.NamespaceAccess_Permission{ns: write})),
+			serviceAccount("sa2", "other", grants(identity.AccountAccess_ROLE_READ, map[string]identity.NamespaceAccess_Permission{"other.acct": admin})),
+		},
+		Groups: []*identity.UserGroup{
+			group("g1", "writers", grants(identity.AccountAccess_ROLE_UNSPECIFIED, map[string]identity.NamespaceAccess_Permission{ns: write})),
+			googleGroup("g2", "google", grants(identity.AccountAccess_ROLE_UNSPECIFIED, map[string]identity.NamespaceAccess_Permission{ns: admin})),
+		},
+		GroupMembers: map[string][]string{"g1": {"u2"}},
+		ApiKeys: []*identity.ApiKey{
+			apiKey("k1", "sa1", false, now.Add(time.Hour)),
+			apiKey("k2", "sa1", true, now.Add(time.Hour)),
+			apiKey("k3", "u1", false, now.Add(-time.Hour)),
+			apiKey("k4", "u4", false, now.Add(time.Hour)),
+		},
+	}
+	report := BuildNamespaceReport(ns, account, now)
+
+	type entry struct {
+		principal, permission string
+		keys                  []string
+	}
+	var got []entry
+	for _, e := range report.Entries {
+		keys := []string{}
+		for _, key := range e.ApiKeys {
+			keys = append(keys, key.ID+" "+key.State)
+		}
+		got = append(got, entry{e.Principal, e.Permission, keys})
+	}
+	// Entries are ordered by permission, then principal type, then name
+	want := []entry{
+		{"owner@example.com", "PERMISSION_ADMIN", []string{}},
+		{"worker", "PERMISSION_WRITE", []string{"k1 active", "k2 disabled"}},
+		{"member@example.com", "PERMISSION_WRITE", []string{}},
+		{"reader@example.com", "PERMISSION_READ", []string{"k3 expired"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("entries = %+v, want %+v", got, want)
+	}
+	if len(report.Notes) != 1 || !strings.Contains(report.Notes[0], "google") {
+		t.Errorf("notes = %v, want one about the Google group", report.Notes)
+	}
+
+	csv, err := report.CSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(csv), "\n"); len(lines) != len(want)+1 || lines[0] != strings.Join(reportColumns, ",") {
+		t.Errorf("CSV() =\n%s", csv)
+	}
+	if markdown := report.Markdown(); !strings.Contains(markdown, "4 principals have access") || !strings.Contains(markdown, "Notes:") {
+		t.Errorf("Markdown() =\n%s", markdown)
+	}
+}
@@ -0,0 +1,275 @@
+package access
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"bechols/temcp/client/api"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+	"go.temporal.io/cloud-sdk/api/identity/v1"
+)
+
+// Resolver reads the account data an explanation or report needs straight from the Cloud API
+type Resolver struct {
+	client *api.Client
+}
+
+func New(client *api.Client) *Resolver {
+	return &Resolver{client: client}
+}
+
+// User explains the access of the user to the namespace
+func (r *Resolver) User(ctx context.Context, userID, namespace string) (*Explanation, error) {
+	resp, err := r.client.CloudService().GetUser(ctx, &cloudservice.GetUserRequest{UserId: userID})
+	if err != nil {
+		return nil, err
+	}
+	groups, err := r.groups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		memberOf []*identity.UserGroup
+		notes    []string
+	)
+	for _, group := range GrantingGroups(groups, namespace) {
+		if !MembershipReadable(group) {
+			notes = append(notes, UnreadableGroupNote(group))
+			continue
+		}
+		members, err := r.members(ctx, group.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			if member == userID {
+				memberOf = append(memberOf, group)
+				break
+			}
+		}
+	}
+	e := ExplainUser(resp.GetUser(), memberOf, namespace)
+	e.Notes = append(e.Notes, notes...)
+	return e, nil
+}
+
+// ServiceAccount explains the access of the service account to the namespace
+func (r *Resolver) ServiceAccount(ctx context.Context, serviceAccountID, namespace string) (*Explanation, error) {
+	resp, err := r.client.CloudService().GetServiceAccount(ctx, &cloudservice.GetServiceAccountRequest{ServiceAccountId: serviceAccountID})
+	if err != nil {
+		return nil, err
+	}
+	return ExplainServiceAccount(resp.GetServiceAccount(), namespace), nil
+}
+
+// NamespaceReport reports every principal with access to the namespace, the same report
+// as the NamespaceAccessReport workflow
+func (r *Resolver) NamespaceReport(ctx context.Context, namespace string) (*NamespaceReport, error) {
+	account := &Account{GroupMembers: map[string][]string{}}
+	var err error
+	if account.Users, err = r.users(ctx); err != nil {
+		return nil, err
+	}
+	if account.ServiceAccounts, err = r.serviceAccounts(ctx); err != nil {
+		return nil, err
+	}
+	if account.ApiKeys, err = r.apiKeys(ctx); err != nil {
+		return nil, err
+	}
+	if account.Groups, err = r.groups(ctx); err != nil {
+		return nil, err
+	}
+	for _, group := range GrantingGroups(account.Groups, namespace) {
+		if !MembershipReadable(group) {
+			continue
+		}
+		if account.GroupMembers[group.Id], err = r.members(ctx, group.Id); err != nil {
+			return nil, err
+		}
+	}
+	return BuildNamespaceReport(namespace, account, time.Now()), nil
+}
+
+// DefaultConcurrency is how many group member lists are read at once when building a matrix
+const DefaultConcurrency = 4
+
+// Matrix builds the access matrix of the whole account, the same matrix as the AccountAccessMatrix
+// workflow. Group members are read at most concurrency groups at a time.
+func (r *Resolver) Matrix(ctx context.Context, opts MatrixOptions, concurrency int) (*Matrix, error) {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	account := &Account{GroupMembers: map[string][]string{}}
+	var (
+		namespaces []string
+		err        error
+	)
+	if namespaces, err = r.namespaces(ctx); err != nil {
+		return nil, err
+	}
+	if account.Users, err = r.users(ctx); err != nil {
+		return nil, err
+	}
+	if account.ServiceAccounts, err = r.serviceAccounts(ctx); err != nil {
+		return nil, err
+	}
+	if account.Groups, err = r.groups(ctx); err != nil {
+		return nil, err
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		slots    = make(chan struct{}, concurrency)
+	)
+	for _, group := range account.Groups {
+		if !MembershipReadable(group) {
+			continue
+		}
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(groupID string) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			members, err := r.members(ctx, groupID)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			account.GroupMembers[groupID] = members
+		}(group.Id)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return BuildMatrix(namespaces, account, opts, time.Now()), nil
+}
+
+// namespaces returns the IDs of the account's namespaces
+func (r *Resolver) namespaces(ctx context.Context) ([]string, error) {
+	var (
+		namespaces []string
+		pageToken  string
+	)
+	for {
+		resp, err := r.client.CloudService().GetNamespaces(ctx, &cloudservice.GetNamespacesRequest{PageToken: pageToken})
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range resp.Namespaces {
+			namespaces = append(namespaces, ns.Namespace)
+		}
+		if resp.NextPageToken == "" {
+			return namespaces, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+func (r *Resolver) users(ctx context.Context) ([]*identity.User, error) {
+	var (
+		users     []*identity.User
+		pageToken string
+	)
+	for {
+		resp, err := r.client.CloudService().GetUsers(ctx, &cloudservice.GetUsersRequest{PageToken: pageToken})
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, resp.Users...)
+		if resp.NextPageToken == "" {
+			return users, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+func (r *Resolver) serviceAccounts(ctx context.Context) ([]*identity.ServiceAccount, error) {
+	var (
+		serviceAccounts []*identity.ServiceAccount
+		pageToken       string
+	)
+	for {
+		resp, err := r.client.CloudService().GetServiceAccounts(ctx, &cloudservice.GetServiceAccountsRequest{PageToken: pageToken})
+		if err != nil {
+			return nil, err
+		}
+		serviceAccounts = append(serviceAccounts, resp.ServiceAccount...)
+		if resp.NextPageToken == "" {
+			return serviceAccounts, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+func (r *Resolver) apiKeys(ctx context.Context) ([]*identity.ApiKey, error) {
+	var (
+		apiKeys   []*identity.ApiKey
+		pageToken string
+	)
+	for {
+		resp, err := r.client.CloudService().GetApiKeys(ctx, &cloudservice.GetApiKeysRequest{PageToken: pageToken})
+		if err != nil {
+			return nil, err
+		}
+		apiKeys = append(apiKeys, resp.ApiKeys...)
+		if resp.NextPageToken == "" {
+			return apiKeys, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+func (r *Resolver) groups(ctx context.Context) ([]*identity.UserGroup, error) {
+	var (
+		groups    []*identity.UserGroup
+		pageToken string
+	)
+	for {
+		resp, err := r.client.CloudService().GetUserGroups(ctx, &cloudservice.GetUserGroupsRequest{PageToken: pageToken})
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, resp.Groups...)
+		if resp.NextPageToken == "" {
+			return groups, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// members returns the user IDs of the group's members
+func (r *Resolver) members(ctx context.Context, groupID string) ([]string, error) {
+	var (
+		members   []string
+		pageToken string
+	)
+	for {
+		resp, err := r.client.CloudService().GetUserGroupMembers(ctx, &cloudservice.GetUserGroupMembersRequest{
+			GroupId:   groupID,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range resp.Members {
+			if userID := member.GetMemberId().GetUserId(); userID != "" {
+				members = append(members, userID)
+			}
+		}
+		if resp.NextPageToken == "" {
+			return members, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
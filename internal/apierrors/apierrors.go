@@ -0,0 +1,217 @@
+package apierrors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.temporal.io/sdk/temporal"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// CloudAPIRequestFailure is the application error type used by activities for non-retryable Cloud API errors.
+	// The gRPC code and message are carried as the error details so they survive the trip through a workflow.
+	CloudAPIRequestFailure = "temporal-cloud-api-request-failure"
+)
+
+type Category string
+
+const (
+	CategoryAuth         Category = "auth"
+	CategoryNotFound     Category = "not_found"
+	CategoryConflict     Category = "conflict"
+	CategoryQuota        Category = "quota"
+	CategoryInvalidInput Category = "invalid_input"
+	CategoryTransient    Category = "transient"
+	CategoryUnknown      Category = "unknown"
+)
+
+// Error is a Cloud API failure translated into something a caller can act on
+type Error struct {
+	Category    Category `json:"category"`
+	Retryable   bool     `json:"retryable"`
+	Code        string   `json:"code,omitempty"`
+	Message     string   `json:"message"`
+	Details     []string `json:"details,omitempty"`
+	Remediation string   `json:"remediation"`
+}
+
+// Translate maps an error returned by the Cloud API, either directly or through a workflow, to an Error.
+// refetchTool names the tool that re-reads the resource, and is used in the remediation for stale resource versions.
+func Translate(err error, refetchTool string) *Error {
+	code, message, details, ok := extractStatus(err)
+	if !ok {
+		var timeoutErr *temporal.TimeoutError
+		if errors.As(err, &timeoutErr) {
+			return &Error{
+				Category:    CategoryTransient,
+				Retryable:   true,
+				Message:     err.Error(),
+				Remediation: "The request timed out. Retry the call; if it keeps timing out, check Cloud API availability.",
+			}
+		}
+		return &Error{
+			Category:    CategoryUnknown,
+			Message:     err.Error(),
+			Remediation: "Inspect the message for details.",
+		}
+	}
+
+	out := &Error{
+		Code:    code.String(),
+		Message: message,
+		Details: details,
+	}
+	switch code {
+	case codes.Unauthenticated:
+		out.Category = CategoryAuth
+		out.Remediation = "The API key was rejected. Check that TEMPORAL_CLOUD_API_KEY is set to a valid, enabled and unexpired key."
+	case codes.PermissionDenied:
+		out.Category = CategoryAuth
+		out.Remediation = "The API key's owner lacks permission for this operation. Check its account role with temporal_get_account_access, or use a key owned by an account admin."
+	case codes.NotFound:
+		out.Category = CategoryNotFound
+		out.Remediation = "The resource does not exist. Check the identifier; list tools such as temporal_list_namespaces or temporal_list_users show valid values."
+	case codes.AlreadyExists:
+		out.Category = CategoryConflict
+		out.Remediation = "The resource already exists. Fetch the existing resource instead of creating it, or pick a different name."
+	case codes.Aborted:
+		out.Category = CategoryConflict
+		out.Retryable = true
+		out.Remediation = "The resource version is stale or another operation is in progress. " + refetch(refetchTool)
+	case codes.FailedPrecondition:
+		if !mentionsResourceVersion(append([]string{message}, details...)) {
+			out.Category = CategoryInvalidInput
+			out.Remediation = "The resource is not in a state that allows this operation. Check its state and any pending async operations before trying again."
+			break
+		}
+		out.Category = CategoryConflict
+		out.Retryable = true
+		out.Remediation = "The resource version is stale. " + refetch(refetchTool)
+	case codes.ResourceExhausted:
+		out.Category = CategoryQuota
+		out.Retryable = true
+		out.Remediation = "A rate limit or account quota was hit. Wait before retrying; if it is a quota, request an increase from Temporal support."
+	case codes.InvalidArgument, codes.OutOfRange:
+		out.Category = CategoryInvalidInput
+		out.Remediation = "The request was rejected as invalid. Fix the arguments named in the message and details, then retry."
+	case codes.Unimplemented:
+		out.Category = CategoryInvalidInput
+		out.Remediation = "The Cloud API does not support this operation for this account or API version."
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled, codes.Internal, codes.Unknown:
+		out.Category = CategoryTransient
+		out.Retryable = true
+		out.Remediation = "The Cloud API had a transient failure. Retry the call after a short wait."
+	default:
+		out.Category = CategoryUnknown
+		out.Remediation = "Inspect the message for details."
+	}
+	return out
+}
+
+func refetch(refetchTool string) string {
+	if refetchTool != "" {
+		return fmt.Sprintf("Re-fetch with %s and retry with its resource_version once pending async operations finish.", refetchTool)
+	}
+	return "Re-fetch the resource and retry with its resource_version once pending async operations finish."
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// IsConflict reports whether the error is a stale resource version or a clash with another operation in
+// progress, which a read-modify-write can recover from by re-reading the resource and trying again.
+// FailedPrecondition only counts when it names the resource version: other precondition failures, such as a
+// namespace in the wrong state, don't go away by trying again.
+func IsConflict(err error) bool {
+	code, message, details, ok := extractStatus(err)
+	if !ok {
+		return false
+	}
+	switch code {
+	case codes.Aborted:
+		return true
+	case codes.FailedPrecondition:
+		return mentionsResourceVersion(append([]string{message}, details...))
+	default:
+		return false
+	}
+}
+
+func mentionsResourceVersion(texts []string) bool {
+	for _, text := range texts {
+		text = strings.ToLower(text)
+		for _, name := range []string{"resource version", "resource_version", "resourceversion"} {
+			if strings.Contains(text, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NewCloudAPIRequestFailure wraps a non-retryable gRPC error in an application error that keeps the status code
+func NewCloudAPIRequestFailure(err error) error {
+	s, _ := status.FromError(err)
+	return temporal.NewNonRetryableApplicationError(
+		"CloudAPI request failed",
+		CloudAPIRequestFailure,
+		err,
+		uint32(s.Code()),
+		s.Message(),
+		statusDetails(s),
+	)
+}
+
+func extractStatus(err error) (codes.Code, string, []string, bool) {
+	var appErr *temporal.ApplicationError
+	if errors.As(err, &appErr) && appErr.Type() == CloudAPIRequestFailure && appErr.HasDetails() {
+		var (
+			code    uint32
+			message string
+			details []string
+		)
+		if appErr.Details(&code, &message, &details) == nil {
+			return codes.Code(code), message, details, true
+		}
+	}
+	if s, ok := status.FromError(err); ok && s.Code() != codes.OK {
+		return s.Code(), s.Message(), statusDetails(s), true
+	}
+	return codes.OK, "", nil, false
+}
+
+func statusDetails(s *status.Status) []string {
+	var out []string
+	for _, d := range s.Details() {
+		switch detail := d.(type) {
+		case *errdetails.BadRequest:
+			for _, v := range detail.GetFieldViolations() {
+				out = append(out, fmt.Sprintf("field %s: %s", v.GetField(), v.GetDescription()))
+			}
+		case *errdetails.PreconditionFailure:
+			for _, v := range detail.GetViolations() {
+				out = append(out, fmt.Sprintf("precondition %s %s: %s", v.GetType(), v.GetSubject(), v.GetDescription()))
+			}
+		case *errdetails.QuotaFailure:
+			for _, v := range detail.GetViolations() {
+				out = append(out, fmt.Sprintf("quota %s: %s", v.GetSubject(), v.GetDescription()))
+			}
+		case *errdetails.RetryInfo:
+			out = append(out, fmt.Sprintf("retry after %s", detail.GetRetryDelay().AsDuration()))
+		case *errdetails.ErrorInfo:
+			out = append(out, strings.TrimSpace(fmt.Sprintf("reason %s %s", detail.GetReason(), detail.GetDomain())))
+		case *errdetails.ResourceInfo:
+			out = append(out, fmt.Sprintf("resource %s %s: %s", detail.GetResourceType(), detail.GetResourceName(), detail.GetDescription()))
+		}
+	}
+	return out
+}
@@ -0,0 +1,75 @@
+package apierrors
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsConflict(t *testing.T) {
+	staleDetail, err := status.New(codes.FailedPrecondition, "precondition failed").WithDetails(&errdetails.PreconditionFailure{
+		Violations: []*errdetails.PreconditionFailure_Violation{{Type: "VERSION", Subject: "namespace", Description: "resource_version mismatch"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"aborted", status.Error(codes.Aborted, "another operation is in progress"), true},
+		{"stale resource version", status.Error(codes.FailedPrecondition, "Resource version is stale"), true},
+		{"stale resource version in details", staleDetail.Err(), true},
+		{"namespace in the wrong state", status.Error(codes.FailedPrecondition, "namespace is not active"), false},
+		{"not found", status.Error(codes.NotFound, "namespace not found"), false},
+		{"wrapped as an activity failure", NewCloudAPIRequestFailure(status.Error(codes.Aborted, "conflict")), true},
+		{"not a status", errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsConflict(tt.err); got != tt.want {
+				t.Errorf("IsConflict() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantCategory  Category
+		wantRetryable bool
+		wantCode      string
+	}{
+		{"unauthenticated", status.Error(codes.Unauthenticated, "bad key"), CategoryAuth, false, "Unauthenticated"},
+		{"permission denied", status.Error(codes.PermissionDenied, "not allowed"), CategoryAuth, false, "PermissionDenied"},
+		{"not found", status.Error(codes.NotFound, "namespace not found"), CategoryNotFound, false, "NotFound"},
+		{"already exists", status.Error(codes.AlreadyExists, "namespace exists"), CategoryConflict, false, "AlreadyExists"},
+		{"aborted", status.Error(codes.Aborted, "another operation is in progress"), CategoryConflict, true, "Aborted"},
+		{"stale resource version", status.Error(codes.FailedPrecondition, "resource version mismatch"), CategoryConflict, true, "FailedPrecondition"},
+		{"namespace in the wrong state", status.Error(codes.FailedPrecondition, "namespace is not active"), CategoryInvalidInput, false, "FailedPrecondition"},
+		{"quota", status.Error(codes.ResourceExhausted, "rate limited"), CategoryQuota, true, "ResourceExhausted"},
+		{"invalid argument", status.Error(codes.InvalidArgument, "bad name"), CategoryInvalidInput, false, "InvalidArgument"},
+		{"unimplemented", status.Error(codes.Unimplemented, "not supported"), CategoryInvalidInput, false, "Unimplemented"},
+		{"unavailable", status.Error(codes.Unavailable, "try again"), CategoryTransient, true, "Unavailable"},
+		{"wrapped as an activity failure", NewCloudAPIRequestFailure(status.Error(codes.FailedPrecondition, "namespace is not active")), CategoryInvalidInput, false, "FailedPrecondition"},
+		{"not a status", errors.New("boom"), CategoryUnknown, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Translate(tt.err, "temporal_get_namespace")
+			if got.Category != tt.wantCategory || got.Retryable != tt.wantRetryable || got.Code != tt.wantCode {
+				t.Errorf("Translate() = %s retryable %v code %q, want %s retryable %v code %q",
+					got.Category, got.Retryable, got.Code, tt.wantCategory, tt.wantRetryable, tt.wantCode)
+			}
+			if got.Remediation == "" {
+				t.Error("Translate() has no remediation")
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package cabundle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Certificate is a certificate of a bundle
+type Certificate struct {
+	// Fingerprint is the hex SHA-256 of the certificate's DER bytes, which identifies it across bundles
+	Fingerprint string    `json:"fingerprint"`
+	Subject     string    `json:"subject"`
+	Issuer      string    `json:"issuer"`
+	NotBefore   time.Time `json:"not_before"`
+	NotAfter    time.Time `json:"not_after"`
+	IsCA        bool      `json:"is_ca"`
+
+	der []byte
+}
+
+// Decode reads a bundle given as PEM text or as base64-encoded PEM, the way the Cloud API's JSON carries it
+func Decode(value string) ([]byte, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, errors.New("the CA bundle is empty")
+	}
+	if strings.HasPrefix(value, "-----BEGIN") {
+		return []byte(value + "\n"), nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New("the CA bundle is neither PEM nor base64-encoded PEM")
+	}
+	return decoded, nil
+}
+
+// Parse returns the certificates of the bundle. Anything other than CERTIFICATE PEM blocks is an error.
+func Parse(bundle []byte) ([]Certificate, error) {
+	var certificates []Certificate
+	for rest := bundle; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			if len(bytes.TrimSpace(rest)) > 0 {
+				return nil, errors.New("the CA bundle has data that isn't a PEM block")
+			}
+			return certificates, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("the CA bundle holds a %s PEM block, only CERTIFICATE blocks are allowed", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("certificate %d can't be parsed: %v", len(certificates)+1, err)
+		}
+		sum := sha256.Sum256(block.Bytes)
+		certificates = append(certificates, Certificate{
+			Fingerprint: hex.EncodeToString(sum[:]),
+			Subject:     cert.Subject.String(),
+			Issuer:      cert.Issuer.String(),
+			NotBefore:   cert.NotBefore,
+			NotAfter:    cert.NotAfter,
+			IsCA:        cert.IsCA,
+			der:         block.Bytes,
+		})
+	}
+}
+
+// Encode writes the certificates as a PEM bundle
+func Encode(certificates []Certificate) []byte {
+	var buf bytes.Buffer
+	for _, cert := range certificates {
+		_ = pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.der})
+	}
+	return buf.Bytes()
+}
+
+// Fingerprints returns the fingerprints of the certificates
+func Fingerprints(certificates []Certificate) []string {
+	fingerprints := make([]string, 0, len(certificates))
+	for _, cert := range certificates {
+		fingerprints = append(fingerprints, cert.Fingerprint)
+	}
+	return fingerprints
+}
+
+// NormalizeFingerprint lowercases a fingerprint and drops the colons some tools print between bytes
+func NormalizeFingerprint(fingerprint string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(fingerprint), ":", ""))
+}
+
+// Append adds the certificates of add that the bundle doesn't hold yet, and returns the new bundle and the
+// certificates added
+func Append(bundle, add []byte) ([]byte, []Certificate, error) {
+	current, err := Parse(bundle)
+	if err != nil {
+		return nil, nil, err
+	}
+	additions, err := Parse(add)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(additions) == 0 {
+		return nil, nil, errors.New("there are no certificates to add")
+	}
+	held := make(map[string]bool, len(current))
+	for _, cert := range current {
+		held[cert.Fingerprint] = true
+	}
+	added := []Certificate{}
+	for _, cert := range additions {
+		if !held[cert.Fingerprint] {
+			held[cert.Fingerprint] = true
+			current = append(current, cert)
+			added = append(added, cert)
+		}
+	}
+	return Encode(current), added, nil
+}
+
+// Remove drops the certificates with the fingerprints from the bundle, and returns the new bundle and the
+// certificates removed. It refuses to remove every certificate, which would lock all clients out.
+func Remove(bundle []byte, fingerprints []string) ([]byte, []Certificate, error) {
+	current, err := Parse(bundle)
+	if err != nil {
+		return nil, nil, err
+	}
+	drop := make(map[string]bool, len(fingerprints))
+	for _, fingerprint := range fingerprints {
+		drop[NormalizeFingerprint(fingerprint)] = true
+	}
+	var (
+		kept    []Certificate
+		removed = []Certificate{}
+	)
+	for _, cert := range current {
+		if drop[cert.Fingerprint] {
+			removed = append(removed, cert)
+		} else {
+			kept = append(kept, cert)
+		}
+	}
+	if len(kept) == 0 && len(removed) > 0 {
+		return nil, nil, errors.New("removing these certificates would leave the CA bundle empty")
+	}
+	return Encode(kept), removed, nil
+}
@@ -0,0 +1,235 @@
+package cabundle
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// selfSigned returns a PEM-encoded self-signed CA certificate with the common name
+func selfSigned(t *testing.T, commonName string) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func join(pems ...[]byte) []byte {
+	var bundle []byte
+	for _, p := range pems {
+		bundle = append(bundle, p...)
+	}
+	return bundle
+}
+
+func subjects(certificates []Certificate) []string {
+	names := []string{}
+	for _, cert := range certificates {
+		names = append(names, strings.TrimPrefix(cert.Subject, "CN="))
+	}
+	return names
+}
+
+func mustParse(t *testing.T, bundle []byte) []Certificate {
+	t.Helper()
+	certificates, err := Parse(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certificates
+}
+
+func TestParse(t *testing.T) {
+	a, b := selfSigned(t, "a"), selfSigned(t, "b")
+	tests := []struct {
+		name    string
+		bundle  []byte
+		want    []string
+		wantErr string
+	}{
+		{name: "one certificate", bundle: a, want: []string{"a"}},
+		{name: "certificates in order", bundle: join(b, a), want: []string{"b", "a"}},
+		{name: "surrounding whitespace", bundle: join([]byte("\n\n"), a, []byte("\n\n")), want: []string{"a"}},
+		{name: "empty", bundle: nil, want: []string{}},
+		{name: "trailing garbage", bundle: join(a, []byte("not pem")), wantErr: "isn't a PEM block"},
+		{
+			name:    "private key block",
+			bundle:  join(a, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte{1}})),
+			wantErr: "only CERTIFICATE blocks",
+		},
+		{
+			name:    "unparseable certificate",
+			bundle:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte{1, 2, 3}}),
+			wantErr: "certificate 1 can't be parsed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certificates, err := Parse(tt.bundle)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := subjects(certificates); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() subjects = %v, want %v", got, tt.want)
+			}
+			for _, cert := range certificates {
+				if len(cert.Fingerprint) != 64 || !cert.IsCA {
+					t.Errorf("certificate %s: fingerprint %q, is CA %v", cert.Subject, cert.Fingerprint, cert.IsCA)
+				}
+			}
+			if len(certificates) > 0 && string(Encode(certificates)) != strings.TrimSpace(string(tt.bundle))+"\n" {
+				t.Errorf("Encode(Parse()) doesn't round-trip:\n%s", Encode(certificates))
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	a := selfSigned(t, "a")
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"PEM", string(a), false},
+		{"PEM with surrounding whitespace", "\n" + string(a) + "  ", false},
+		{"base64-encoded PEM", base64.StdEncoding.EncodeToString(a), false},
+		{"empty", "  ", true},
+		{"neither", "not a bundle!", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle, err := Decode(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Decode() = %q, want an error", bundle)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := subjects(mustParse(t, bundle)); !reflect.DeepEqual(got, []string{"a"}) {
+				t.Errorf("Decode() bundle holds %v, want [a]", got)
+			}
+		})
+	}
+}
+
+func TestAppend(t *testing.T) {
+	a, b, c := selfSigned(t, "a"), selfSigned(t, "b"), selfSigned(t, "c")
+	tests := []struct {
+		name      string
+		bundle    []byte
+		add       []byte
+		want      []string
+		wantAdded []string
+		wantErr   bool
+	}{
+		{name: "append", bundle: a, add: b, want: []string{"a", "b"}, wantAdded: []string{"b"}},
+		{name: "skip held certificates", bundle: join(a, b), add: join(b, c), want: []string{"a", "b", "c"}, wantAdded: []string{"c"}},
+		{name: "everything held", bundle: join(a, b), add: a, want: []string{"a", "b"}, wantAdded: []string{}},
+		{name: "duplicates in the addition", bundle: a, add: join(c, c), want: []string{"a", "c"}, wantAdded: []string{"c"}},
+		{name: "nothing to add", bundle: a, add: nil, wantErr: true},
+		{name: "bad addition", bundle: a, add: []byte("junk"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle, added, err := Append(tt.bundle, tt.add)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Append() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := subjects(mustParse(t, bundle)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Append() bundle = %v, want %v", got, tt.want)
+			}
+			if got := subjects(added); !reflect.DeepEqual(got, tt.wantAdded) {
+				t.Errorf("Append() added = %v, want %v", got, tt.wantAdded)
+			}
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	a, b, c := selfSigned(t, "a"), selfSigned(t, "b"), selfSigned(t, "c")
+	bundle := join(a, b, c)
+	fingerprint := map[string]string{}
+	for _, cert := range mustParse(t, bundle) {
+		fingerprint[strings.TrimPrefix(cert.Subject, "CN=")] = cert.Fingerprint
+	}
+	// colons formats a fingerprint the way openssl prints it, upper case with colons between bytes
+	colons := func(fp string) string {
+		var parts []string
+		for i := 0; i < len(fp); i += 2 {
+			parts = append(parts, strings.ToUpper(fp[i:i+2]))
+		}
+		return strings.Join(parts, ":")
+	}
+	tests := []struct {
+		name         string
+		fingerprints []string
+		want         []string
+		wantRemoved  []string
+		wantErr      bool
+	}{
+		{name: "remove one", fingerprints: []string{fingerprint["b"]}, want: []string{"a", "c"}, wantRemoved: []string{"b"}},
+		{name: "remove two", fingerprints: []string{fingerprint["c"], fingerprint["a"]}, want: []string{"b"}, wantRemoved: []string{"a", "c"}},
+		{name: "openssl fingerprint", fingerprints: []string{colons(fingerprint["a"])}, want: []string{"b", "c"}, wantRemoved: []string{"a"}},
+		{name: "unknown fingerprint", fingerprints: []string{strings.Repeat("0", 64)}, want: []string{"a", "b", "c"}, wantRemoved: []string{}},
+		{name: "remove everything", fingerprints: []string{fingerprint["a"], fingerprint["b"], fingerprint["c"]}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, removed, err := Remove(bundle, tt.fingerprints)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Remove() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if names := subjects(mustParse(t, got)); !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("Remove() bundle = %v, want %v", names, tt.want)
+			}
+			if names := subjects(removed); !reflect.DeepEqual(names, tt.wantRemoved) {
+				t.Errorf("Remove() removed = %v, want %v", names, tt.wantRemoved)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package certgen
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Key algorithms
+	KeyAlgorithmECDSAP256 = "ecdsa-p256"
+	KeyAlgorithmECDSAP384 = "ecdsa-p384"
+	KeyAlgorithmRSA2048   = "rsa-2048"
+	KeyAlgorithmRSA4096   = "rsa-4096"
+
+	DefaultKeyAlgorithm = KeyAlgorithmECDSAP256
+	DefaultCAValidity   = 365 * 24 * time.Hour
+	DefaultLeafValidity = 30 * 24 * time.Hour
+
+	// Default file names
+	CACertFile     = "ca.pem"
+	CAKeyFile      = "ca.key"
+	ClientCertFile = "client.pem"
+	ClientKeyFile  = "client.key"
+)
+
+// KeyAlgorithms are the key algorithms GenerateCA and GenerateLeaf accept
+var KeyAlgorithms = []string{KeyAlgorithmECDSAP256, KeyAlgorithmECDSAP384, KeyAlgorithmRSA2048, KeyAlgorithmRSA4096}
+
+// Options configure a generated certificate
+type Options struct {
+	// Organization is the subject organization, required
+	Organization string
+	// CommonName is the subject common name, the organization by default
+	CommonName string
+	// Validity is how long the certificate is valid from now
+	Validity time.Duration
+	// KeyAlgorithm is one of KeyAlgorithms, DefaultKeyAlgorithm by default
+	KeyAlgorithm string
+}
+
+// Pair is a certificate with its private key, parsed and in PEM
+type Pair struct {
+	Cert    *x509.Certificate
+	Key     crypto.Signer
+	CertPEM []byte
+	KeyPEM  []byte
+}
+
+// Fingerprint returns the hex SHA-256 of the certificate's DER bytes, the fingerprint the CA rotation tools use
+func (p *Pair) Fingerprint() string {
+	sum := sha256.Sum256(p.Cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseValidity reads a validity like 365d, 1y or 720h. Days and years are 24 hours and 365 days.
+func ParseValidity(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(value, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(value, "y"):
+		unit = 365 * 24 * time.Hour
+	}
+	if unit != 0 {
+		count, err := strconv.Atoi(value[:len(value)-1])
+		if err != nil || count <= 0 {
+			return 0, fmt.Errorf("validity %q must be like 365d, 1y or 720h", value)
+		}
+		return time.Duration(count) * unit, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("validity %q must be like 365d, 1y or 720h", value)
+	}
+	return d, nil
+}
+
+// GenerateCA generates a self-signed CA certificate for signing client certificates
+func GenerateCA(opts Options) (*Pair, error) {
+	return generate(opts, DefaultCAValidity, nil)
+}
+
+// GenerateLeaf generates a client certificate signed by the CA. It can't outlive the CA.
+func GenerateLeaf(ca *Pair, opts Options) (*Pair, error) {
+	return generate(opts, DefaultLeafValidity, ca)
+}
+
+func generate(opts Options, defaultValidity time.Duration, ca *Pair) (*Pair, error) {
+	if strings.TrimSpace(opts.Organization) == "" {
+		return nil, errors.New("organization is required")
+	}
+	if opts.CommonName == "" {
+		opts.CommonName = opts.Organization
+	}
+	if opts.Validity <= 0 {
+		opts.Validity = defaultValidity
+	}
+	key, err := generateKey(opts.KeyAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{opts.Organization}, CommonName: opts.CommonName},
+		NotBefore:             now,
+		NotAfter:              now.Add(opts.Validity),
+		BasicConstraintsValid: true,
+	}
+	parent, signer := template, crypto.Signer(key)
+	if ca == nil {
+		template.IsCA = true
+		template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+	} else {
+		if template.NotAfter.After(ca.Cert.NotAfter) {
+			return nil, fmt.Errorf("the certificate would be valid until %s, after its CA expires on %s",
+				template.NotAfter.Format(time.RFC3339), ca.Cert.NotAfter.Format(time.RFC3339))
+		}
+		template.KeyUsage = x509.KeyUsageDigitalSignature
+		if _, ok := key.(*rsa.PrivateKey); ok {
+			template.KeyUsage |= x509.KeyUsageKeyEncipherment
+		}
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+		parent, signer = ca.Cert, ca.Key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), signer)
+	if err != nil {
+		return nil, fmt.Errorf("creating certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("encoding private key: %w", err)
+	}
+	return &Pair{
+		Cert:    cert,
+		Key:     key,
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		KeyPEM:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
+
+func generateKey(algorithm string) (crypto.Signer, error) {
+	switch algorithm {
+	case "", KeyAlgorithmECDSAP256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case KeyAlgorithmECDSAP384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case KeyAlgorithmRSA2048:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case KeyAlgorithmRSA4096:
+		return rsa.GenerateKey(rand.Reader, 4096)
+	default:
+		return nil, fmt.Errorf("key algorithm %q is not one of %s", algorithm, strings.Join(KeyAlgorithms, ", "))
+	}
+}
+
+// LoadPair reads a CA certificate and its private key from PEM files, to sign client certificates with an existing CA
+func LoadPair(certFile, keyFile string) (*Pair, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil || certBlock.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("%s holds no PEM certificate", certFile)
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", certFile, err)
+	}
+	if !cert.IsCA {
+		return nil, fmt.Errorf("%s is not a CA certificate", certFile)
+	}
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, fmt.Errorf("%s holds no PEM private key", keyFile)
+	}
+	var parsed any
+	switch keyBlock.Type {
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(keyBlock.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	default:
+		parsed, err = x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", keyFile, err)
+	}
+	key, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%s holds an unsupported private key", keyFile)
+	}
+	return &Pair{Cert: cert, Key: key, CertPEM: certPEM, KeyPEM: keyPEM}, nil
+}
+
+// Write writes the pair's certificate and key, creating missing directories readable by the owner only.
+// Certificates are world-readable and keys are readable by the owner only. Existing files are kept unless
+// overwrite is set.
+func Write(certPath, keyPath string, pair *Pair, overwrite bool) error {
+	for _, path := range []string{certPath, keyPath} {
+		// Check both files first so a clash doesn't leave half a pair behind
+		if _, err := os.Stat(path); err == nil && !overwrite {
+			return fmt.Errorf("%s already exists, pass overwrite to replace it", path)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return err
+		}
+	}
+	// The certificate goes first so a failed write never leaves a new private key behind, and a certificate
+	// without its key is removed again
+	if err := writeFile(certPath, pair.CertPEM, 0o644, overwrite); err != nil {
+		return err
+	}
+	if err := writeFile(keyPath, pair.KeyPEM, 0o600, overwrite); err != nil {
+		os.Remove(certPath)
+		return err
+	}
+	return nil
+}
+
+func writeFile(path string, data []byte, perm os.FileMode, overwrite bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if overwrite {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, perm)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%s already exists, pass overwrite to replace it", path)
+	}
+	if err != nil {
+		return err
+	}
+	// OpenFile only applies the mode to new files, so tighten an overwritten file too
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,181 @@
+package certgen
+
+import (
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseValidity(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "365d", want: 365 * 24 * time.Hour},
+		{value: "1y", want: 365 * 24 * time.Hour},
+		{value: " 2y ", want: 2 * 365 * 24 * time.Hour},
+		{value: "720h", want: 720 * time.Hour},
+		{value: "90m", want: 90 * time.Minute},
+		{value: "0d", wantErr: true},
+		{value: "-1y", wantErr: true},
+		{value: "-5h", wantErr: true},
+		{value: "d", wantErr: true},
+		{value: "1w", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseValidity(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseValidity() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseValidity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func mustCA(t *testing.T, opts Options) *Pair {
+	t.Helper()
+	ca, err := GenerateCA(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ca
+}
+
+func TestGenerate(t *testing.T) {
+	ca := mustCA(t, Options{Organization: "example", Validity: 48 * time.Hour})
+	if !ca.Cert.IsCA || ca.Cert.Subject.CommonName != "example" {
+		t.Errorf("CA is CA %v with common name %q", ca.Cert.IsCA, ca.Cert.Subject.CommonName)
+	}
+	if len(ca.Fingerprint()) != 64 {
+		t.Errorf("Fingerprint() = %q, want 64 hex digits", ca.Fingerprint())
+	}
+
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr string
+	}{
+		{name: "default key", opts: Options{Organization: "example", CommonName: "worker", Validity: time.Hour}},
+		{name: "ecdsa-p384", opts: Options{Organization: "example", Validity: time.Hour, KeyAlgorithm: KeyAlgorithmECDSAP384}},
+		{name: "rsa-2048", opts: Options{Organization: "example", Validity: time.Hour, KeyAlgorithm: KeyAlgorithmRSA2048}},
+		{name: "no organization", opts: Options{Organization: " "}, wantErr: "organization is required"},
+		{name: "unknown key algorithm", opts: Options{Organization: "example", KeyAlgorithm: "dsa"}, wantErr: "is not one of"},
+		{name: "outlives the CA", opts: Options{Organization: "example", Validity: 72 * time.Hour}, wantErr: "after its CA expires"},
+		{name: "default validity outlives the CA", opts: Options{Organization: "example"}, wantErr: "after its CA expires"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leaf, err := GenerateLeaf(ca, tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("GenerateLeaf() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if leaf.Cert.IsCA {
+				t.Error("the client certificate is a CA")
+			}
+			roots := x509.NewCertPool()
+			roots.AddCert(ca.Cert)
+			if _, err := leaf.Cert.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+				t.Errorf("the client certificate doesn't verify against its CA: %v", err)
+			}
+		})
+	}
+}
+
+func TestWriteAndLoadPair(t *testing.T) {
+	ca := mustCA(t, Options{Organization: "example"})
+	dir := filepath.Join(t.TempDir(), "certs")
+	certPath, keyPath := filepath.Join(dir, CACertFile), filepath.Join(dir, CAKeyFile)
+	if err := Write(certPath, keyPath, ca, false); err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]os.FileMode{dir: 0o700, certPath: 0o644, keyPath: 0o600} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("%s has mode %v, want %v", path, got, want)
+		}
+	}
+
+	loaded, err := LoadPair(certPath, keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Fingerprint() != ca.Fingerprint() {
+		t.Errorf("LoadPair() fingerprint = %s, want %s", loaded.Fingerprint(), ca.Fingerprint())
+	}
+	if _, err := GenerateLeaf(loaded, Options{Organization: "example"}); err != nil {
+		t.Errorf("signing with the loaded CA: %v", err)
+	}
+
+	other := mustCA(t, Options{Organization: "other"})
+	if err := Write(certPath, keyPath, other, false); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Write() over existing files error = %v, want one saying they exist", err)
+	}
+	if err := os.Chmod(keyPath, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Write(certPath, keyPath, other, true); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("overwritten key has mode %v, want 0600", info.Mode().Perm())
+	}
+	if loaded, err := LoadPair(certPath, keyPath); err != nil || loaded.Fingerprint() != other.Fingerprint() {
+		t.Errorf("LoadPair() after overwrite = %v, %v, want the new CA", loaded, err)
+	}
+}
+
+func TestLoadPairErrors(t *testing.T) {
+	ca := mustCA(t, Options{Organization: "example", Validity: 48 * time.Hour})
+	leaf, err := GenerateLeaf(ca, Options{Organization: "example", Validity: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	caCert, caKey := write("ca.pem", ca.CertPEM), write("ca.key", ca.KeyPEM)
+	tests := []struct {
+		name    string
+		cert    string
+		key     string
+		wantErr string
+	}{
+		{name: "missing certificate", cert: filepath.Join(dir, "missing.pem"), key: caKey, wantErr: "no such file"},
+		{name: "not PEM", cert: write("junk.pem", []byte("junk")), key: caKey, wantErr: "holds no PEM certificate"},
+		{name: "not a CA", cert: write("client.pem", leaf.CertPEM), key: caKey, wantErr: "is not a CA certificate"},
+		{name: "key not PEM", cert: caCert, key: write("junk.key", []byte("junk")), wantErr: "holds no PEM private key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadPair(tt.cert, tt.key); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadPair() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
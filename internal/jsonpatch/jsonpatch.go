@@ -0,0 +1,85 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// Merge applies a JSON merge patch (RFC 7386) to a decoded JSON document and returns the result.
+// Objects in the patch are merged key by key, a null removes the key, and anything else replaces
+// the target value. The target is not modified.
+func Merge(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	merged := make(map[string]interface{}, len(targetObject))
+	for k, v := range targetObject {
+		merged[k] = v
+	}
+	for k, v := range patchObject {
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+		merged[k] = Merge(merged[k], v)
+	}
+	return merged
+}
+
+// Change is a field that differs between two JSON documents. Before or After is nil when the
+// field is only present on one side.
+type Change struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Diff lists the fields that differ between two decoded JSON documents, ordered by field. Objects
+// are compared field by field and reported with dotted paths; arrays and scalars are compared whole.
+func Diff(before, after interface{}) []Change {
+	changes := []Change{}
+	diff("", before, after, &changes)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+func diff(field string, before, after interface{}, changes *[]Change) {
+	beforeObject, beforeIsObject := before.(map[string]interface{})
+	afterObject, afterIsObject := after.(map[string]interface{})
+	if beforeIsObject && afterIsObject {
+		for k, v := range beforeObject {
+			diff(join(field, k), v, afterObject[k], changes)
+		}
+		for k, v := range afterObject {
+			if _, ok := beforeObject[k]; !ok {
+				diff(join(field, k), nil, v, changes)
+			}
+		}
+		return
+	}
+	if !reflect.DeepEqual(before, after) {
+		*changes = append(*changes, Change{Field: field, Before: before, After: after})
+	}
+}
+
+func join(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// Decode parses JSON into a generic document of maps, slices and scalars
+func Decode(data []byte) (interface{}, error) {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
@@ -0,0 +1,104 @@
+package jsonpatch
+
+import (
+	"reflect"
+	"testing"
+)
+
+func mustDecode(t *testing.T, data string) interface{} {
+	t.Helper()
+	doc, err := Decode([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		patch  string
+		want   string
+	}{
+		{"replace a field", `{"a":1,"b":2}`, `{"b":3}`, `{"a":1,"b":3}`},
+		{"null removes a field", `{"a":1,"b":2}`, `{"b":null}`, `{"a":1}`},
+		{"nested objects merge", `{"spec":{"a":1,"b":{"c":2}}}`, `{"spec":{"b":{"d":3}}}`, `{"spec":{"a":1,"b":{"c":2,"d":3}}}`},
+		{"arrays are replaced whole", `{"a":[1,2,3]}`, `{"a":[4]}`, `{"a":[4]}`},
+		{"object replaces a scalar", `{"a":1}`, `{"a":{"b":2}}`, `{"a":{"b":2}}`},
+		{"non-object patch replaces the target", `{"a":1}`, `[1]`, `[1]`},
+		{"add a field", `{}`, `{"a":"x"}`, `{"a":"x"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := mustDecode(t, tt.target)
+			got := Merge(target, mustDecode(t, tt.patch))
+			if want := mustDecode(t, tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("Merge() = %v, want %v", got, want)
+			}
+			if !reflect.DeepEqual(target, mustDecode(t, tt.target)) {
+				t.Errorf("Merge() modified the target: %v", target)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   []Change
+	}{
+		{"equal documents", `{"a":1,"b":{"c":[1,2]}}`, `{"a":1,"b":{"c":[1,2]}}`, []Change{}},
+		{"changed field", `{"a":1}`, `{"a":2}`, []Change{{Field: "a", Before: 1.0, After: 2.0}}},
+		{
+			"nested fields use dotted paths in order",
+			`{"spec":{"b":1,"a":{"x":true}}}`,
+			`{"spec":{"b":2,"a":{"x":false}}}`,
+			[]Change{{Field: "spec.a.x", Before: true, After: false}, {Field: "spec.b", Before: 1.0, After: 2.0}},
+		},
+		{"added and removed fields", `{"a":1}`, `{"b":2}`, []Change{{Field: "a", Before: 1.0}, {Field: "b", After: 2.0}}},
+		{"arrays compare whole", `{"a":[1,2]}`, `{"a":[2,1]}`, []Change{{Field: "a", Before: []interface{}{1.0, 2.0}, After: []interface{}{2.0, 1.0}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(mustDecode(t, tt.before), mustDecode(t, tt.after)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMergeDiffRoundTrip checks that the diff of a merged document holds exactly the patched fields
+func TestMergeDiffRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		patch  string
+		fields []string
+	}{
+		{"no-op patch", `{"a":1,"b":{"c":2}}`, `{"a":1}`, nil},
+		{"empty patch", `{"a":1}`, `{}`, nil},
+		{"scalar and nested change", `{"a":1,"b":{"c":2,"d":3}}`, `{"a":5,"b":{"d":4}}`, []string{"a", "b.d"}},
+		{"remove and add", `{"a":1,"b":2}`, `{"a":null,"c":{"d":1}}`, []string{"a", "c"}},
+		{"removing a missing field", `{"a":1}`, `{"z":null}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := mustDecode(t, tt.target)
+			after := Merge(before, mustDecode(t, tt.patch))
+			changes := Diff(before, after)
+			var fields []string
+			for _, change := range changes {
+				fields = append(fields, change.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("fields changed = %v, want %v", fields, tt.fields)
+			}
+			if again := Diff(after, Merge(after, mustDecode(t, tt.patch))); len(again) != 0 {
+				t.Errorf("applying the patch twice changed %+v", again)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package nexusendpoint
+
+import (
+	"fmt"
+	"sort"
+
+	"go.temporal.io/cloud-sdk/api/nexus/v1"
+	"go.temporal.io/sdk/converter"
+)
+
+// Config is a Nexus endpoint spec in plain fields. Endpoint specs hold oneofs, which don't survive the
+// JSON payload converter outside a proto message, so workflow inputs and outputs carry a Config instead.
+type Config struct {
+	Name string `json:"name"`
+	// TargetNamespace is the ID of the namespace whose workers handle the endpoint's operations
+	TargetNamespace string `json:"target_namespace"`
+	TargetTaskQueue string `json:"target_task_queue"`
+	// AllowedCallerNamespaces are the IDs of the namespaces allowed to call the endpoint
+	AllowedCallerNamespaces []string `json:"allowed_caller_namespaces"`
+	// Description is markdown shown in the Cloud UI
+	Description string `json:"description,omitempty"`
+}
+
+// Spec returns the Cloud API spec of the config. The description is stored as a JSON payload, the way
+// the Cloud UI stores it.
+func (c Config) Spec() (*nexus.EndpointSpec, error) {
+	spec := &nexus.EndpointSpec{
+		Name: c.Name,
+		TargetSpec: &nexus.EndpointTargetSpec{
+			Variant: &nexus.EndpointTargetSpec_WorkerTargetSpec{
+				WorkerTargetSpec: &nexus.WorkerTargetSpec{
+					NamespaceId: c.TargetNamespace,
+					TaskQueue:   c.TargetTaskQueue,
+				},
+			},
+		},
+	}
+	for _, namespace := range c.AllowedCallerNamespaces {
+		spec.PolicySpecs = append(spec.PolicySpecs, &nexus.EndpointPolicySpec{
+			Variant: &nexus.EndpointPolicySpec_AllowedCloudNamespacePolicySpec{
+				AllowedCloudNamespacePolicySpec: &nexus.AllowedCloudNamespacePolicySpec{NamespaceId: namespace},
+			},
+		})
+	}
+	if c.Description != "" {
+		payload, err := converter.GetDefaultDataConverter().ToPayload(c.Description)
+		if err != nil {
+			return nil, fmt.Errorf("encoding description: %w", err)
+		}
+		spec.Description = payload
+	}
+	return spec, nil
+}
+
+// FromSpec returns the config of a Cloud API spec. Descriptions that aren't a string payload are left empty.
+func FromSpec(spec *nexus.EndpointSpec) Config {
+	c := Config{
+		Name:                    spec.GetName(),
+		TargetNamespace:         spec.GetTargetSpec().GetWorkerTargetSpec().GetNamespaceId(),
+		TargetTaskQueue:         spec.GetTargetSpec().GetWorkerTargetSpec().GetTaskQueue(),
+		AllowedCallerNamespaces: []string{},
+	}
+	for _, policy := range spec.GetPolicySpecs() {
+		if namespace := policy.GetAllowedCloudNamespacePolicySpec().GetNamespaceId(); namespace != "" {
+			c.AllowedCallerNamespaces = append(c.AllowedCallerNamespaces, namespace)
+		}
+	}
+	if spec.GetDescription() != nil {
+		_ = converter.GetDefaultDataConverter().FromPayload(spec.GetDescription(), &c.Description)
+	} else if spec.GetDescriptionDeprecated() != "" {
+		c.Description = spec.GetDescriptionDeprecated()
+	}
+	return c
+}
+
+// Equal reports whether the configs describe the same endpoint, ignoring the order of the caller namespaces
+func (c Config) Equal(other Config) bool {
+	if c.Name != other.Name || c.TargetNamespace != other.TargetNamespace || c.TargetTaskQueue != other.TargetTaskQueue ||
+		c.Description != other.Description || len(c.AllowedCallerNamespaces) != len(other.AllowedCallerNamespaces) {
+		return false
+	}
+	a, b := sorted(c.AllowedCallerNamespaces), sorted(other.AllowedCallerNamespaces)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sorted(values []string) []string {
+	out := append([]string(nil), values...)
+	sort.Strings(out)
+	return out
+}
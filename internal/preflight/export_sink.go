@@ -0,0 +1,108 @@
+package preflight
+
+import (
+	"regexp"
+	"strings"
+
+	"go.temporal.io/cloud-sdk/api/namespace/v1"
+)
+
+var (
+	exportSinkNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+	// Bucket names are 3 to 63 lowercase letters, digits, dots and hyphens on both S3 and GCS
+	bucketNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9.\-_]{1,61}[a-z0-9]$`)
+
+	awsAccountIDPattern = regexp.MustCompile(`^[0-9]{12}$`)
+
+	// iamRoleNamePattern matches the role name, not the role ARN
+	iamRoleNamePattern = regexp.MustCompile(`^[\w+=,.@-]{1,64}$`)
+
+	kmsArnPattern = regexp.MustCompile(`^arn:aws[a-z-]*:kms:[a-z0-9-]+:[0-9]{12}:(key|alias)/.+$`)
+
+	gcpProjectIDPattern = regexp.MustCompile(`^[a-z][a-z0-9-]{4,28}[a-z0-9]$`)
+)
+
+// CheckExportSink checks an export sink spec before it is validated by or sent to the Cloud API, so
+// missing or malformed S3 and GCS fields are reported together
+func CheckExportSink(spec *namespace.ExportSinkSpec) *Report {
+	r := &Report{Valid: true, Issues: []Issue{}}
+
+	switch {
+	case spec.GetName() == "":
+		r.add("name", SeverityError, "name is required")
+	case !exportSinkNamePattern.MatchString(spec.GetName()):
+		r.add("name", SeverityError, "%q must hold only letters, digits, hyphens and underscores", spec.GetName())
+	}
+
+	switch {
+	case spec.GetS3() != nil && spec.GetGcs() != nil:
+		r.add("s3", SeverityError, "set either s3 or gcs, not both")
+	case spec.GetS3() != nil:
+		checkS3Sink(r, spec)
+	case spec.GetGcs() != nil:
+		checkGCSSink(r, spec)
+	default:
+		r.add("s3", SeverityError, "set s3 or gcs to say where exports go")
+	}
+
+	if !spec.GetEnabled() {
+		r.add("enabled", SeverityWarning, "the sink is disabled, nothing is exported until enabled is true")
+	}
+	return r
+}
+
+func checkS3Sink(r *Report, spec *namespace.ExportSinkSpec) {
+	s3 := spec.GetS3()
+	switch {
+	case s3.GetRoleName() == "":
+		r.add("s3.role_name", SeverityError, "role_name is required: the IAM role Temporal Cloud assumes to write to the bucket")
+	case strings.HasPrefix(s3.GetRoleName(), "arn:"):
+		r.add("s3.role_name", SeverityError, "role_name is the role's name, not its ARN; use %q", s3.GetRoleName()[strings.LastIndex(s3.GetRoleName(), "/")+1:])
+	case !iamRoleNamePattern.MatchString(s3.GetRoleName()):
+		r.add("s3.role_name", SeverityError, "%q is not a valid IAM role name", s3.GetRoleName())
+	}
+	checkBucketName(r, "s3.bucket_name", s3.GetBucketName())
+	if s3.GetRegion() == "" {
+		r.add("s3.region", SeverityError, "region is required: the AWS region of the bucket, e.g. us-east-1")
+	}
+	switch {
+	case s3.GetAwsAccountId() == "":
+		r.add("s3.aws_account_id", SeverityError, "aws_account_id is required: the AWS account of the bucket and role")
+	case !awsAccountIDPattern.MatchString(s3.GetAwsAccountId()):
+		r.add("s3.aws_account_id", SeverityError, "%q must be a 12 digit AWS account ID", s3.GetAwsAccountId())
+	}
+	if s3.GetKmsArn() != "" && !kmsArnPattern.MatchString(s3.GetKmsArn()) {
+		r.add("s3.kms_arn", SeverityError, "%q is not a KMS key ARN", s3.GetKmsArn())
+	}
+}
+
+func checkGCSSink(r *Report, spec *namespace.ExportSinkSpec) {
+	gcs := spec.GetGcs()
+	if gcs.GetSaId() == "" {
+		r.add("gcs.sa_id", SeverityError, "sa_id is required: the service account Temporal Cloud impersonates to write to the bucket")
+	} else if strings.Contains(gcs.GetSaId(), "@") {
+		r.add("gcs.sa_id", SeverityError, "sa_id is the service account ID, the part of %q before the @", gcs.GetSaId())
+	}
+	checkBucketName(r, "gcs.bucket_name", gcs.GetBucketName())
+	switch {
+	case gcs.GetGcpProjectId() == "":
+		r.add("gcs.gcp_project_id", SeverityError, "gcp_project_id is required: the project of the bucket and service account")
+	case !gcpProjectIDPattern.MatchString(gcs.GetGcpProjectId()):
+		r.add("gcs.gcp_project_id", SeverityError, "%q is not a valid GCP project ID", gcs.GetGcpProjectId())
+	}
+	if gcs.GetRegion() == "" {
+		r.add("gcs.region", SeverityError, "region is required: the GCP region of the bucket, e.g. us-central1")
+	}
+}
+
+func checkBucketName(r *Report, field, name string) {
+	switch {
+	case name == "":
+		r.add(field, SeverityError, "bucket_name is required")
+	case strings.HasPrefix(name, "s3://") || strings.HasPrefix(name, "gs://"):
+		r.add(field, SeverityError, "bucket_name is the bucket's name without the %s prefix", name[:5])
+	case !bucketNamePattern.MatchString(name):
+		r.add(field, SeverityError, "%q is not a valid bucket name", name)
+	}
+}
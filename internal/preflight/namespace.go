@@ -0,0 +1,231 @@
+package preflight
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"bechols/temcp/internal/cabundle"
+	"go.temporal.io/cloud-sdk/api/namespace/v1"
+)
+
+const (
+	// Issue severities: errors fail the create, warnings are reported alongside it
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+
+	MinRetentionDays = 1
+	MaxRetentionDays = 90
+
+	// certificateExpiryWarning is how close to expiry a CA certificate gets a warning
+	certificateExpiryWarning = 30 * 24 * time.Hour
+)
+
+var (
+	// Namespace names are 2 to 39 lowercase letters, digits and hyphens, starting with a letter and
+	// not ending with a hyphen
+	namespaceNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]{0,37}[a-z0-9]$`)
+
+	searchAttributeNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+	// customSearchAttributeTypes are the type names the deprecated custom_search_attributes map accepts
+	customSearchAttributeTypes = map[string]bool{
+		"text": true, "keyword": true, "int": true, "double": true, "bool": true, "datetime": true, "keywordlist": true,
+	}
+)
+
+// Issue is one problem found with a spec
+type Issue struct {
+	Field    string `json:"field"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// Report is the outcome of a preflight check. Valid is false when any issue is an error.
+type Report struct {
+	Valid  bool    `json:"valid"`
+	Issues []Issue `json:"issues"`
+}
+
+func (r *Report) add(field, severity, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, Issue{Field: field, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	if severity == SeverityError {
+		r.Valid = false
+	}
+}
+
+// FirstError returns the message of the report's first error, or "" if it has none
+func (r *Report) FirstError() string {
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			return issue.Message
+		}
+	}
+	return ""
+}
+
+// NamespaceEnvironment is what a namespace spec is checked against in the account
+type NamespaceEnvironment struct {
+	// Regions are the IDs of the regions namespaces can be created in
+	Regions []string
+	// ExistingNamespaces are the IDs of namespaces that already have the spec's name
+	ExistingNamespaces []string
+}
+
+// CheckNamespace checks a namespace spec before it is sent to CreateNamespace, so the problems the
+// Cloud API would reject one at a time are all reported together
+func CheckNamespace(spec *namespace.NamespaceSpec, env NamespaceEnvironment, now time.Time) *Report {
+	r := &Report{Valid: true, Issues: []Issue{}}
+
+	switch {
+	case spec.GetName() == "":
+		r.add("name", SeverityError, "name is required")
+	case !namespaceNamePattern.MatchString(spec.GetName()):
+		r.add("name", SeverityError, "%q must be 2 to 39 lowercase letters, digits and hyphens, start with a letter and not end with a hyphen", spec.GetName())
+	case strings.Contains(spec.GetName(), "--"):
+		r.add("name", SeverityError, "%q must not contain consecutive hyphens", spec.GetName())
+	}
+	if len(env.ExistingNamespaces) > 0 {
+		r.add("name", SeverityError, "a namespace named %q already exists: %s", spec.GetName(), strings.Join(env.ExistingNamespaces, ", "))
+	}
+
+	checkRegions(r, spec.GetRegions(), env.Regions)
+
+	if days := spec.GetRetentionDays(); days < MinRetentionDays || days > MaxRetentionDays {
+		r.add("retention_days", SeverityError, "retention_days is %d, it must be between %d and %d", days, MinRetentionDays, MaxRetentionDays)
+	}
+
+	mtls := spec.GetMtlsAuth()
+	if !spec.GetApiKeyAuth().GetEnabled() && !mtls.GetEnabled() {
+		r.add("api_key_auth", SeverityError, "enable API key auth, mTLS auth or both, or no client can connect")
+	}
+	if mtls.GetEnabled() || len(mtls.GetAcceptedClientCa()) > 0 || mtls.GetAcceptedClientCaDeprecated() != "" {
+		checkCABundle(r, mtls, now)
+	}
+
+	if codec := spec.GetCodecServer(); codec.GetEndpoint() != "" {
+		checkCodecEndpoint(r, codec.GetEndpoint())
+	}
+
+	checkSearchAttributes(r, spec)
+
+	sort.SliceStable(r.Issues, func(i, j int) bool { return r.Issues[i].Field < r.Issues[j].Field })
+	return r
+}
+
+func checkRegions(r *Report, regions, available []string) {
+	if len(regions) == 0 {
+		r.add("regions", SeverityError, "at least one region is required")
+		return
+	}
+	known := make(map[string]bool, len(available))
+	for _, id := range available {
+		known[id] = true
+	}
+	seen := map[string]bool{}
+	for _, region := range regions {
+		if seen[region] {
+			r.add("regions", SeverityError, "region %s is listed twice", region)
+		}
+		seen[region] = true
+		if len(available) > 0 && !known[region] {
+			sorted := append([]string(nil), available...)
+			sort.Strings(sorted)
+			r.add("regions", SeverityError, "%s is not an available region, use one of: %s", region, strings.Join(sorted, ", "))
+		}
+	}
+}
+
+// checkCABundle parses every PEM block of the accepted client CA bundle
+func checkCABundle(r *Report, mtls *namespace.MtlsAuthSpec, now time.Time) {
+	bundle := mtls.GetAcceptedClientCa()
+	if len(bundle) == 0 && mtls.GetAcceptedClientCaDeprecated() != "" {
+		decoded, err := base64.StdEncoding.DecodeString(mtls.GetAcceptedClientCaDeprecated())
+		if err != nil {
+			r.add("mtls_auth.accepted_client_ca_deprecated", SeverityError, "not valid base64: %v", err)
+			return
+		}
+		bundle = decoded
+	}
+	if len(bundle) == 0 {
+		r.add("mtls_auth.accepted_client_ca", SeverityError, "mTLS auth is enabled but no accepted client CA certificates are set")
+		return
+	}
+	checkCertificates(r, "mtls_auth.accepted_client_ca", bundle, now)
+}
+
+// CheckCABundle checks a PEM CA bundle for client certificates, reporting issues against the field
+func CheckCABundle(field string, bundle []byte, now time.Time) *Report {
+	r := &Report{Valid: true, Issues: []Issue{}}
+	if len(bundle) == 0 {
+		r.add(field, SeverityError, "no CA certificates are set")
+		return r
+	}
+	checkCertificates(r, field, bundle, now)
+	return r
+}
+
+// checkCertificates parses the bundle with cabundle.Parse and adds the findings that only matter before the
+// bundle is accepted: expired, not yet valid and soon to expire certificates, and certificates that aren't CAs
+func checkCertificates(r *Report, field string, bundle []byte, now time.Time) {
+	certificates, err := cabundle.Parse(bundle)
+	if err != nil {
+		r.add(field, SeverityError, "%v", err)
+		return
+	}
+	if len(certificates) == 0 {
+		r.add(field, SeverityError, "the CA bundle holds no PEM certificates; pass the PEM file's contents, base64 encoded")
+		return
+	}
+	for _, cert := range certificates {
+		switch {
+		case now.After(cert.NotAfter):
+			r.add(field, SeverityError, "certificate %q expired on %s", cert.Subject, cert.NotAfter.Format(time.RFC3339))
+		case cert.NotAfter.Sub(now) < certificateExpiryWarning:
+			r.add(field, SeverityWarning, "certificate %q expires on %s", cert.Subject, cert.NotAfter.Format(time.RFC3339))
+		}
+		if now.Before(cert.NotBefore) {
+			r.add(field, SeverityWarning, "certificate %q is not valid until %s", cert.Subject, cert.NotBefore.Format(time.RFC3339))
+		}
+		if !cert.IsCA {
+			r.add(field, SeverityWarning, "certificate %q is not a CA certificate", cert.Subject)
+		}
+	}
+}
+
+func checkCodecEndpoint(r *Report, endpoint string) {
+	const field = "codec_server.endpoint"
+	u, err := url.Parse(endpoint)
+	switch {
+	case err != nil:
+		r.add(field, SeverityError, "%q is not a valid URL: %v", endpoint, err)
+	case u.Scheme != "https":
+		r.add(field, SeverityError, "%q must be an https URL", endpoint)
+	case u.Host == "":
+		r.add(field, SeverityError, "%q has no host", endpoint)
+	}
+}
+
+func checkSearchAttributes(r *Report, spec *namespace.NamespaceSpec) {
+	for name, saType := range spec.GetSearchAttributes() {
+		field := "search_attributes." + name
+		checkSearchAttributeName(r, field, name)
+		if saType == namespace.NamespaceSpec_SEARCH_ATTRIBUTE_TYPE_UNSPECIFIED || namespace.NamespaceSpec_SearchAttributeType_name[int32(saType)] == "" {
+			r.add(field, SeverityError, "unknown search attribute type %d", saType)
+		}
+	}
+	for name, saType := range spec.GetCustomSearchAttributes() {
+		field := "custom_search_attributes." + name
+		checkSearchAttributeName(r, field, name)
+		if !customSearchAttributeTypes[strings.ToLower(saType)] {
+			r.add(field, SeverityError, "unknown search attribute type %q, use Text, Keyword, Int, Double, Bool, Datetime or KeywordList", saType)
+		}
+		if _, ok := spec.GetSearchAttributes()[name]; ok {
+			r.add(field, SeverityError, "%s is set in both search_attributes and custom_search_attributes", name)
+		}
+	}
+}
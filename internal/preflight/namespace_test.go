@@ -0,0 +1,197 @@
+package preflight
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.temporal.io/cloud-sdk/api/namespace/v1"
+)
+
+var now = time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+
+// certificate returns a PEM-encoded self-signed certificate valid from notBefore to notAfter
+func certificate(t *testing.T, isCA bool, notBefore, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+// issues returns the report's issues as "field severity" strings, to compare without the messages
+func issues(r *Report) []string {
+	got := []string{}
+	for _, issue := range r.Issues {
+		got = append(got, issue.Field+" "+issue.Severity)
+	}
+	return got
+}
+
+func TestCheckNamespace(t *testing.T) {
+	ca := certificate(t, true, now.Add(-time.Hour), now.AddDate(1, 0, 0))
+	valid := func() *namespace.NamespaceSpec {
+		return &namespace.NamespaceSpec{
+			Name:          "orders",
+			Regions:       []string{"aws-us-east-1"},
+			RetentionDays: 30,
+			ApiKeyAuth:    &namespace.ApiKeyAuthSpec{Enabled: true},
+		}
+	}
+	env := NamespaceEnvironment{Regions: []string{"aws-us-east-1", "aws-us-west-2"}}
+	tests := []struct {
+		name   string
+		modify func(spec *namespace.NamespaceSpec)
+		env    *NamespaceEnvironment
+		want   []string
+	}{
+		{name: "valid", modify: func(*namespace.NamespaceSpec) {}, want: []string{}},
+		{name: "missing name", modify: func(s *namespace.NamespaceSpec) { s.Name = "" }, want: []string{"name error"}},
+		{name: "uppercase name", modify: func(s *namespace.NamespaceSpec) { s.Name = "Orders" }, want: []string{"name error"}},
+		{name: "name ending in a hyphen", modify: func(s *namespace.NamespaceSpec) { s.Name = "orders-" }, want: []string{"name error"}},
+		{name: "consecutive hyphens", modify: func(s *namespace.NamespaceSpec) { s.Name = "or--ders" }, want: []string{"name error"}},
+		{
+			name:   "name taken",
+			modify: func(*namespace.NamespaceSpec) {},
+			env:    &NamespaceEnvironment{Regions: env.Regions, ExistingNamespaces: []string{"orders.acct"}},
+			want:   []string{"name error"},
+		},
+		{name: "no regions", modify: func(s *namespace.NamespaceSpec) { s.Regions = nil }, want: []string{"regions error"}},
+		{
+			name:   "unknown and duplicate regions",
+			modify: func(s *namespace.NamespaceSpec) { s.Regions = []string{"aws-us-east-1", "aws-us-east-1", "mars-1"} },
+			want:   []string{"regions error", "regions error"},
+		},
+		{name: "retention too long", modify: func(s *namespace.NamespaceSpec) { s.RetentionDays = 91 }, want: []string{"retention_days error"}},
+		{name: "no auth", modify: func(s *namespace.NamespaceSpec) { s.ApiKeyAuth = nil }, want: []string{"api_key_auth error"}},
+		{
+			name: "mTLS without a CA",
+			modify: func(s *namespace.NamespaceSpec) {
+				s.MtlsAuth = &namespace.MtlsAuthSpec{Enabled: true}
+			},
+			want: []string{"mtls_auth.accepted_client_ca error"},
+		},
+		{
+			name: "mTLS with a CA",
+			modify: func(s *namespace.NamespaceSpec) {
+				s.ApiKeyAuth = nil
+				s.MtlsAuth = &namespace.MtlsAuthSpec{Enabled: true, AcceptedClientCa: ca}
+			},
+			want: []string{},
+		},
+		{
+			name: "deprecated base64 CA",
+			modify: func(s *namespace.NamespaceSpec) {
+				s.MtlsAuth = &namespace.MtlsAuthSpec{Enabled: true, AcceptedClientCaDeprecated: base64.StdEncoding.EncodeToString(ca)}
+			},
+			want: []string{},
+		},
+		{
+			name: "deprecated CA that isn't base64",
+			modify: func(s *namespace.NamespaceSpec) {
+				s.MtlsAuth = &namespace.MtlsAuthSpec{Enabled: true, AcceptedClientCaDeprecated: "not base64!"}
+			},
+			want: []string{"mtls_auth.accepted_client_ca_deprecated error"},
+		},
+		{
+			name: "http codec server",
+			modify: func(s *namespace.NamespaceSpec) {
+				s.CodecServer = &namespace.CodecServerSpec{Endpoint: "http://codec.example.com"}
+			},
+			want: []string{"codec_server.endpoint error"},
+		},
+		{
+			name: "search attributes",
+			modify: func(s *namespace.NamespaceSpec) {
+				s.SearchAttributes = map[string]namespace.NamespaceSpec_SearchAttributeType{
+					"CustomerId": namespace.NamespaceSpec_SEARCH_ATTRIBUTE_TYPE_KEYWORD,
+					"WorkflowId": namespace.NamespaceSpec_SEARCH_ATTRIBUTE_TYPE_KEYWORD,
+				}
+				s.CustomSearchAttributes = map[string]string{"CustomerId": "Keyword", "Region": "Geo"}
+			},
+			want: []string{"custom_search_attributes.CustomerId error", "custom_search_attributes.Region error", "search_attributes.WorkflowId error"},
+		},
+		{
+			name: "every problem at once",
+			modify: func(s *namespace.NamespaceSpec) {
+				s.Name, s.Regions, s.RetentionDays, s.ApiKeyAuth = "-", nil, 0, nil
+			},
+			want: []string{"api_key_auth error", "name error", "regions error", "retention_days error"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := valid()
+			tt.modify(spec)
+			e := env
+			if tt.env != nil {
+				e = *tt.env
+			}
+			report := CheckNamespace(spec, e, now)
+			if got := issues(report); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("issues = %v, want %v (%+v)", got, tt.want, report.Issues)
+			}
+			if wantValid := len(tt.want) == 0; report.Valid != wantValid {
+				t.Errorf("Valid = %v, want %v", report.Valid, wantValid)
+			}
+		})
+	}
+}
+
+func TestCheckCABundle(t *testing.T) {
+	const field = "bundle"
+	tests := []struct {
+		name   string
+		bundle []byte
+		want   []string
+		valid  bool
+	}{
+		{name: "valid CA", bundle: certificate(t, true, now.Add(-time.Hour), now.AddDate(1, 0, 0)), want: []string{}, valid: true},
+		{name: "empty", bundle: nil, want: []string{"bundle error"}},
+		{name: "not PEM", bundle: []byte("not pem"), want: []string{"bundle error"}},
+		{name: "expired", bundle: certificate(t, true, now.AddDate(-1, 0, 0), now.Add(-time.Hour)), want: []string{"bundle error"}},
+		{name: "expires soon", bundle: certificate(t, true, now.Add(-time.Hour), now.AddDate(0, 0, 7)), want: []string{"bundle warning"}, valid: true},
+		{name: "not yet valid", bundle: certificate(t, true, now.Add(time.Hour), now.AddDate(1, 0, 0)), want: []string{"bundle warning"}, valid: true},
+		{name: "not a CA", bundle: certificate(t, false, now.Add(-time.Hour), now.AddDate(1, 0, 0)), want: []string{"bundle warning"}, valid: true},
+		{
+			name:   "private key block",
+			bundle: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte{1}}),
+			want:   []string{"bundle error"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := CheckCABundle(field, tt.bundle, now)
+			if got := issues(report); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("issues = %v, want %v (%+v)", got, tt.want, report.Issues)
+			}
+			if report.Valid != tt.valid {
+				t.Errorf("Valid = %v, want %v", report.Valid, tt.valid)
+			}
+			if (report.FirstError() == "") != tt.valid {
+				t.Errorf("FirstError() = %q with Valid %v", report.FirstError(), report.Valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package preflight
+
+import (
+	"regexp"
+	"strings"
+
+	"go.temporal.io/cloud-sdk/api/nexus/v1"
+)
+
+var (
+	// Nexus endpoint names are letters, digits and hyphens, starting with a letter and not ending with a hyphen
+	nexusEndpointNamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]*[a-zA-Z0-9]$`)
+
+	// namespaceIDPattern matches a full namespace ID, name.account
+	namespaceIDPattern = regexp.MustCompile(`^[a-z0-9-]+\.[a-z0-9]+$`)
+)
+
+// MaxNexusEndpointNameLength is the longest Nexus endpoint name the Cloud API accepts
+const MaxNexusEndpointNameLength = 200
+
+// CheckNexusEndpoint checks a Nexus endpoint spec. Namespaces are the IDs of the account's namespaces;
+// the target and caller namespaces must be among them.
+func CheckNexusEndpoint(spec *nexus.EndpointSpec, namespaces []string) *Report {
+	r := &Report{Valid: true, Issues: []Issue{}}
+
+	switch name := spec.GetName(); {
+	case name == "":
+		r.add("name", SeverityError, "name is required")
+	case len(name) > MaxNexusEndpointNameLength:
+		r.add("name", SeverityError, "name is %d characters, at most %d are allowed", len(name), MaxNexusEndpointNameLength)
+	case !nexusEndpointNamePattern.MatchString(name):
+		r.add("name", SeverityError, "%q must be letters, digits and hyphens, start with a letter and not end with a hyphen", name)
+	}
+
+	known := make(map[string]bool, len(namespaces))
+	for _, namespace := range namespaces {
+		known[namespace] = true
+	}
+	checkNamespace := func(field, namespace string) {
+		switch {
+		case !namespaceIDPattern.MatchString(namespace):
+			r.add(field, SeverityError, "%q is not a namespace ID, use the full name.account form", namespace)
+		case !known[namespace]:
+			r.add(field, SeverityError, "namespace %s doesn't exist in the account", namespace)
+		}
+	}
+
+	target := spec.GetTargetSpec().GetWorkerTargetSpec()
+	if target.GetNamespaceId() == "" {
+		r.add("target_namespace", SeverityError, "target_namespace is required")
+	} else {
+		checkNamespace("target_namespace", target.GetNamespaceId())
+	}
+	if strings.TrimSpace(target.GetTaskQueue()) == "" {
+		r.add("target_task_queue", SeverityError, "target_task_queue is required")
+	}
+
+	seen := map[string]bool{}
+	callers := 0
+	for _, policy := range spec.GetPolicySpecs() {
+		namespace := policy.GetAllowedCloudNamespacePolicySpec().GetNamespaceId()
+		if namespace == "" {
+			continue
+		}
+		callers++
+		if seen[namespace] {
+			r.add("allowed_caller_namespaces", SeverityWarning, "%s is listed twice", namespace)
+			continue
+		}
+		seen[namespace] = true
+		checkNamespace("allowed_caller_namespaces", namespace)
+	}
+	if callers == 0 {
+		r.add("allowed_caller_namespaces", SeverityWarning, "no caller namespaces are allowed, so nothing can call the endpoint")
+	}
+	return r
+}
@@ -0,0 +1,123 @@
+package preflight
+
+import (
+	"sort"
+	"strings"
+
+	"go.temporal.io/cloud-sdk/api/namespace/v1"
+	"go.temporal.io/cloud-sdk/api/region/v1"
+)
+
+// MaxNamespaceRegions is how many regions a namespace can be in: the active region and one replica
+const MaxNamespaceRegions = 2
+
+// CheckAddRegion checks that the region can be added to the namespace as a replica. Regions are
+// the account's available regions from GetRegions.
+func CheckAddRegion(ns *namespace.Namespace, regionID string, regions []*region.Region) *Report {
+	r := &Report{Valid: true, Issues: []Issue{}}
+	current := ns.GetSpec().GetRegions()
+	target := FindRegion(regions, regionID)
+	switch {
+	case target == nil:
+		r.add("region", SeverityError, "%s is not an available region, use one of: %s", regionID, regionIDs(regions))
+	case ContainsRegion(current, regionID):
+		r.add("region", SeverityError, "namespace %s is already in region %s", ns.GetNamespace(), regionID)
+	case len(current) >= MaxNamespaceRegions:
+		r.add("region", SeverityError, "namespace %s is already in %d regions (%s), remove the replica before adding another",
+			ns.GetNamespace(), len(current), strings.Join(current, ", "))
+	}
+	if target != nil {
+		for _, id := range current {
+			existing := FindRegion(regions, id)
+			if existing == nil || existing.GetCloudProvider() == target.GetCloudProvider() {
+				continue
+			}
+			r.add("region", SeverityWarning, "%s is on %s but the namespace's region %s is on %s; replicating across cloud providers needs multi-cloud replication on the account",
+				regionID, target.GetCloudProvider(), id, existing.GetCloudProvider())
+		}
+	}
+	checkRegionsSettled(r, ns)
+	return r
+}
+
+// CheckRemoveRegion checks that the region can be removed from the namespace. Only a passive
+// replica can be removed; the active region has to be failed over first.
+func CheckRemoveRegion(ns *namespace.Namespace, regionID string) *Report {
+	r := &Report{Valid: true, Issues: []Issue{}}
+	current := ns.GetSpec().GetRegions()
+	switch {
+	case !ContainsRegion(current, regionID):
+		r.add("region", SeverityError, "namespace %s is not in region %s, it is in: %s", ns.GetNamespace(), regionID, strings.Join(current, ", "))
+	case len(current) < MaxNamespaceRegions:
+		r.add("region", SeverityError, "%s is the only region of namespace %s", regionID, ns.GetNamespace())
+	case regionID == ns.GetActiveRegion():
+		r.add("region", SeverityError, "%s is the active region of namespace %s, fail over to the replica before removing it", regionID, ns.GetNamespace())
+	}
+	checkRegionsSettled(r, ns)
+	return r
+}
+
+// CheckFailover checks that the namespace can fail over to the region, which must be one of its
+// passive replicas
+func CheckFailover(ns *namespace.Namespace, regionID string) *Report {
+	r := &Report{Valid: true, Issues: []Issue{}}
+	current := ns.GetSpec().GetRegions()
+	switch {
+	case !ContainsRegion(current, regionID):
+		r.add("region", SeverityError, "namespace %s is not in region %s, it is in: %s", ns.GetNamespace(), regionID, strings.Join(current, ", "))
+	case regionID == ns.GetActiveRegion():
+		r.add("region", SeverityError, "%s is already the active region of namespace %s", regionID, ns.GetNamespace())
+	default:
+		if state := ns.GetRegionStatus()[regionID].GetState(); state != namespace.NamespaceRegionStatus_STATE_PASSIVE &&
+			state != namespace.NamespaceRegionStatus_STATE_UNSPECIFIED {
+			r.add("region", SeverityError, "region %s is %s, only a passive replica can take over", regionID, state)
+		}
+	}
+	return r
+}
+
+// checkRegionsSettled reports regions of the namespace that are still being added or removed, or failed
+func checkRegionsSettled(r *Report, ns *namespace.Namespace) {
+	ids := make([]string, 0, len(ns.GetRegionStatus()))
+	for id := range ns.GetRegionStatus() {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		switch state := ns.GetRegionStatus()[id].GetState(); state {
+		case namespace.NamespaceRegionStatus_STATE_ADDING, namespace.NamespaceRegionStatus_STATE_REMOVING:
+			r.add("region_status."+id, SeverityError, "region %s is %s, wait for it to finish", id, state)
+		case namespace.NamespaceRegionStatus_STATE_FAILED:
+			r.add("region_status."+id, SeverityWarning, "region %s is %s", id, state)
+		}
+	}
+}
+
+// FindRegion returns the region with the ID, or nil if it isn't one of the regions
+func FindRegion(regions []*region.Region, id string) *region.Region {
+	for _, r := range regions {
+		if r.GetId() == id {
+			return r
+		}
+	}
+	return nil
+}
+
+func regionIDs(regions []*region.Region) string {
+	ids := make([]string, 0, len(regions))
+	for _, r := range regions {
+		ids = append(ids, r.GetId())
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ", ")
+}
+
+// ContainsRegion reports whether the region ID is one of the IDs
+func ContainsRegion(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package preflight
+
+import (
+	"reflect"
+	"testing"
+
+	"go.temporal.io/cloud-sdk/api/namespace/v1"
+	"go.temporal.io/cloud-sdk/api/region/v1"
+)
+
+var regions = []*region.Region{
+	{Id: "aws-us-east-1", CloudProvider: region.Region_CLOUD_PROVIDER_AWS},
+	{Id: "aws-us-west-2", CloudProvider: region.Region_CLOUD_PROVIDER_AWS},
+	{Id: "gcp-us-central1", CloudProvider: region.Region_CLOUD_PROVIDER_GCP},
+}
+
+// replicated returns a namespace in the regions, active in the first, with the region states
+func replicated(ids []string, states ...namespace.NamespaceRegionStatus_State) *namespace.Namespace {
+	ns := &namespace.Namespace{
+		Namespace:    "orders.acct",
+		Spec:         &namespace.NamespaceSpec{Regions: ids},
+		ActiveRegion: ids[0],
+		RegionStatus: map[string]*namespace.NamespaceRegionStatus{},
+	}
+	for i, state := range states {
+		ns.RegionStatus[ids[i]] = &namespace.NamespaceRegionStatus{State: state}
+	}
+	return ns
+}
+
+const (
+	active   = namespace.NamespaceRegionStatus_STATE_ACTIVE
+	passive  = namespace.NamespaceRegionStatus_STATE_PASSIVE
+	adding   = namespace.NamespaceRegionStatus_STATE_ADDING
+	removing = namespace.NamespaceRegionStatus_STATE_REMOVING
+	failed   = namespace.NamespaceRegionStatus_STATE_FAILED
+)
+
+func TestCheckAddRegion(t *testing.T) {
+	tests := []struct {
+		name   string
+		ns     *namespace.Namespace
+		region string
+		want   []string
+	}{
+		{"same cloud", replicated([]string{"aws-us-east-1"}, active), "aws-us-west-2", []string{}},
+		{"across clouds", replicated([]string{"aws-us-east-1"}, active), "gcp-us-central1", []string{"region warning"}},
+		{"unknown region", replicated([]string{"aws-us-east-1"}, active), "mars-1", []string{"region error"}},
+		{"already in the region", replicated([]string{"aws-us-east-1"}, active), "aws-us-east-1", []string{"region error"}},
+		{"already replicated", replicated([]string{"aws-us-east-1", "aws-us-west-2"}, active, passive), "gcp-us-central1", []string{"region error", "region warning", "region warning"}},
+		{"region still being added", replicated([]string{"aws-us-east-1"}, adding), "aws-us-west-2", []string{"region_status.aws-us-east-1 error"}},
+		{"failed region", replicated([]string{"aws-us-east-1"}, failed), "aws-us-west-2", []string{"region_status.aws-us-east-1 warning"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := issues(CheckAddRegion(tt.ns, tt.region, regions)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckRemoveRegion(t *testing.T) {
+	pair := []string{"aws-us-east-1", "aws-us-west-2"}
+	tests := []struct {
+		name   string
+		ns     *namespace.Namespace
+		region string
+		want   []string
+	}{
+		{"passive replica", replicated(pair, active, passive), "aws-us-west-2", []string{}},
+		{"active region", replicated(pair, active, passive), "aws-us-east-1", []string{"region error"}},
+		{"only region", replicated([]string{"aws-us-east-1"}, active), "aws-us-east-1", []string{"region error"}},
+		{"not in the region", replicated(pair, active, passive), "gcp-us-central1", []string{"region error"}},
+		{"replica being removed", replicated(pair, active, removing), "aws-us-west-2", []string{"region_status.aws-us-west-2 error"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := issues(CheckRemoveRegion(tt.ns, tt.region)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckFailover(t *testing.T) {
+	pair := []string{"aws-us-east-1", "aws-us-west-2"}
+	tests := []struct {
+		name   string
+		ns     *namespace.Namespace
+		region string
+		want   []string
+	}{
+		{"passive replica", replicated(pair, active, passive), "aws-us-west-2", []string{}},
+		{"state not reported", replicated(pair), "aws-us-west-2", []string{}},
+		{"already active", replicated(pair, active, passive), "aws-us-east-1", []string{"region error"}},
+		{"replica still being added", replicated(pair, active, adding), "aws-us-west-2", []string{"region error"}},
+		{"not in the region", replicated(pair, active, passive), "gcp-us-central1", []string{"region error"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := issues(CheckFailover(tt.ns, tt.region)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
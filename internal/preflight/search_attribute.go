@@ -0,0 +1,139 @@
+package preflight
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.temporal.io/cloud-sdk/api/namespace/v1"
+)
+
+// reservedSearchAttributePrefix starts the names of search attributes Temporal adds itself
+const reservedSearchAttributePrefix = "Temporal"
+
+// reservedSearchAttributes are the system search attributes every namespace has. Custom search
+// attributes can't reuse their names.
+var reservedSearchAttributes = map[string]bool{
+	"BatcherNamespace":     true,
+	"BatcherUser":          true,
+	"BinaryChecksums":      true,
+	"BuildIds":             true,
+	"CloseTime":            true,
+	"ExecutionDuration":    true,
+	"ExecutionStatus":      true,
+	"ExecutionTime":        true,
+	"HistoryLength":        true,
+	"HistorySizeBytes":     true,
+	"NamespaceId":          true,
+	"ParentRunId":          true,
+	"ParentWorkflowId":     true,
+	"RootRunId":            true,
+	"RootWorkflowId":       true,
+	"RunId":                true,
+	"StartTime":            true,
+	"StateTransitionCount": true,
+	"TaskQueue":            true,
+	"WorkflowId":           true,
+	"WorkflowType":         true,
+}
+
+// SearchAttribute is a custom search attribute of a namespace
+type SearchAttribute struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Deprecated is true for attributes set through the deprecated custom_search_attributes map
+	Deprecated bool `json:"deprecated,omitempty"`
+}
+
+// SearchAttributes lists the custom search attributes of the spec by name, from both the search_attributes
+// map and the deprecated custom_search_attributes map
+func SearchAttributes(spec *namespace.NamespaceSpec) []SearchAttribute {
+	attributes := []SearchAttribute{}
+	for name, saType := range spec.GetSearchAttributes() {
+		attributes = append(attributes, SearchAttribute{Name: name, Type: SearchAttributeTypeName(saType)})
+	}
+	for name, saType := range spec.GetCustomSearchAttributes() {
+		if _, ok := spec.GetSearchAttributes()[name]; ok {
+			continue
+		}
+		attributes = append(attributes, SearchAttribute{Name: name, Type: saType, Deprecated: true})
+	}
+	sort.Slice(attributes, func(i, j int) bool { return attributes[i].Name < attributes[j].Name })
+	return attributes
+}
+
+// SearchAttributeTypeName returns the short name of the type, e.g. Keyword or KeywordList
+func SearchAttributeTypeName(saType namespace.NamespaceSpec_SearchAttributeType) string {
+	var b strings.Builder
+	for _, word := range strings.Split(strings.TrimPrefix(saType.String(), "SEARCH_ATTRIBUTE_TYPE_"), "_") {
+		if word != "" {
+			b.WriteString(word[:1] + strings.ToLower(word[1:]))
+		}
+	}
+	return b.String()
+}
+
+// ParseSearchAttributeType parses a type name such as Keyword, keyword_list or SEARCH_ATTRIBUTE_TYPE_DATETIME
+func ParseSearchAttributeType(name string) (namespace.NamespaceSpec_SearchAttributeType, error) {
+	normalized := strings.ToUpper(strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.TrimPrefix(strings.ToUpper(name), "SEARCH_ATTRIBUTE_TYPE_")))
+	for value, typeName := range namespace.NamespaceSpec_SearchAttributeType_name {
+		saType := namespace.NamespaceSpec_SearchAttributeType(value)
+		if saType == namespace.NamespaceSpec_SEARCH_ATTRIBUTE_TYPE_UNSPECIFIED {
+			continue
+		}
+		if strings.ReplaceAll(strings.TrimPrefix(typeName, "SEARCH_ATTRIBUTE_TYPE_"), "_", "") == normalized {
+			return saType, nil
+		}
+	}
+	return namespace.NamespaceSpec_SEARCH_ATTRIBUTE_TYPE_UNSPECIFIED,
+		fmt.Errorf("unknown search attribute type %q, use Text, Keyword, Int, Double, Bool, Datetime or KeywordList", name)
+}
+
+// checkSearchAttributeName adds an issue when the name isn't a valid custom search attribute name
+func checkSearchAttributeName(r *Report, field, name string) {
+	switch {
+	case !searchAttributeNamePattern.MatchString(name):
+		r.add(field, SeverityError, "search attribute names must start with a letter and hold only letters, digits and underscores")
+	case reservedSearchAttributes[name]:
+		r.add(field, SeverityError, "%s is a system search attribute", name)
+	case strings.HasPrefix(name, reservedSearchAttributePrefix):
+		r.add(field, SeverityError, "search attribute names starting with %q are reserved for Temporal", reservedSearchAttributePrefix)
+	}
+}
+
+// CheckAddSearchAttribute checks that the search attribute can be added to the spec
+func CheckAddSearchAttribute(spec *namespace.NamespaceSpec, name string, saType namespace.NamespaceSpec_SearchAttributeType) *Report {
+	r := &Report{Valid: true, Issues: []Issue{}}
+	checkSearchAttributeName(r, "name", name)
+	if saType == namespace.NamespaceSpec_SEARCH_ATTRIBUTE_TYPE_UNSPECIFIED || namespace.NamespaceSpec_SearchAttributeType_name[int32(saType)] == "" {
+		r.add("type", SeverityError, "unknown search attribute type %d", saType)
+	}
+	for _, existing := range SearchAttributes(spec) {
+		if existing.Name == name {
+			r.add("name", SeverityError, "search attribute %s already exists with type %s", name, existing.Type)
+		}
+	}
+	return r
+}
+
+// CheckRenameSearchAttribute checks that the custom search attribute can be renamed
+func CheckRenameSearchAttribute(spec *namespace.NamespaceSpec, name, newName string) *Report {
+	r := &Report{Valid: true, Issues: []Issue{}}
+	var found bool
+	for _, existing := range SearchAttributes(spec) {
+		switch existing.Name {
+		case name:
+			found = true
+		case newName:
+			r.add("new_name", SeverityError, "search attribute %s already exists with type %s", newName, existing.Type)
+		}
+	}
+	if !found {
+		r.add("name", SeverityError, "the namespace has no custom search attribute %s", name)
+	}
+	if name == newName {
+		r.add("new_name", SeverityError, "the new name is the same as the current name")
+	}
+	checkSearchAttributeName(r, "new_name", newName)
+	return r
+}
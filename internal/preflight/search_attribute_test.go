@@ -0,0 +1,91 @@
+package preflight
+
+import (
+	"reflect"
+	"testing"
+
+	"go.temporal.io/cloud-sdk/api/namespace/v1"
+)
+
+func TestParseSearchAttributeType(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    namespace.NamespaceSpec_SearchAttributeType
+		wantErr bool
+	}{
+		{name: "Keyword", want: namespace.NamespaceSpec_SEARCH_ATTRIBUTE_TYPE_KEYWORD},
+		{name: "keyword_list", want: namespace.NamespaceSpec_SEARCH_ATTRIBUTE_TYPE_KEYWORD_LIST},
+		{name: "KeywordList", want: namespace.NamespaceSpec_SEARCH_ATTRIBUTE_TYPE_KEYWORD_LIST},
+		{name: "SEARCH_ATTRIBUTE_TYPE_DATETIME", want: namespace.NamespaceSpec_SEARCH_ATTRIBUTE_TYPE_DATETIME},
+		{name: "unspecified", wantErr: true},
+		{name: "Geo", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSearchAttributeType(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSearchAttributeType() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSearchAttributeType() = %v, want %v", got, tt.want)
+			}
+			if !tt.wantErr {
+				if round, _ := ParseSearchAttributeType(SearchAttributeTypeName(got)); round != got {
+					t.Errorf("%s doesn't round-trip through SearchAttributeTypeName, got %v", got, round)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchAttributes(t *testing.T) {
+	spec := &namespace.NamespaceSpec{
+		SearchAttributes: map[string]namespace.NamespaceSpec_SearchAttributeType{
+			"OrderId": namespace.NamespaceSpec_SEARCH_ATTRIBUTE_TYPE_KEYWORD,
+			"Tags":    namespace.NamespaceSpec_SEARCH_ATTRIBUTE_TYPE_KEYWORD_LIST,
+		},
+		CustomSearchAttributes: map[string]string{"Amount": "Double", "OrderId": "Keyword"},
+	}
+	want := []SearchAttribute{
+		{Name: "Amount", Type: "Double", Deprecated: true},
+		{Name: "OrderId", Type: "Keyword"},
+		{Name: "Tags", Type: "KeywordList"},
+	}
+	if got := SearchAttributes(spec); !reflect.DeepEqual(got, want) {
+		t.Errorf("SearchAttributes() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCheckSearchAttributeChanges(t *testing.T) {
+	spec := &namespace.NamespaceSpec{
+		SearchAttributes: map[string]namespace.NamespaceSpec_SearchAttributeType{
+			"OrderId": namespace.NamespaceSpec_SEARCH_ATTRIBUTE_TYPE_KEYWORD,
+		},
+		CustomSearchAttributes: map[string]string{"Amount": "Double"},
+	}
+	keyword := namespace.NamespaceSpec_SEARCH_ATTRIBUTE_TYPE_KEYWORD
+	tests := []struct {
+		name   string
+		report *Report
+		want   []string
+	}{
+		{"add", CheckAddSearchAttribute(spec, "CustomerId", keyword), []string{}},
+		{"add an existing name", CheckAddSearchAttribute(spec, "Amount", keyword), []string{"name error"}},
+		{"add a system name", CheckAddSearchAttribute(spec, "WorkflowId", keyword), []string{"name error"}},
+		{"add a reserved prefix", CheckAddSearchAttribute(spec, "TemporalThing", keyword), []string{"name error"}},
+		{"add an invalid name", CheckAddSearchAttribute(spec, "1st", keyword), []string{"name error"}},
+		{"add without a type", CheckAddSearchAttribute(spec, "CustomerId", namespace.NamespaceSpec_SEARCH_ATTRIBUTE_TYPE_UNSPECIFIED), []string{"type error"}},
+		{"rename", CheckRenameSearchAttribute(spec, "OrderId", "PurchaseId"), []string{}},
+		{"rename a missing attribute", CheckRenameSearchAttribute(spec, "Missing", "PurchaseId"), []string{"name error"}},
+		{"rename onto an existing name", CheckRenameSearchAttribute(spec, "OrderId", "Amount"), []string{"new_name error"}},
+		{"rename to the same name", CheckRenameSearchAttribute(spec, "OrderId", "OrderId"), []string{"new_name error"}},
+		{"rename to a system name", CheckRenameSearchAttribute(spec, "OrderId", "RunId"), []string{"new_name error"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := issues(tt.report); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("issues = %v, want %v (%+v)", got, tt.want, tt.report.Issues)
+			}
+		})
+	}
+}
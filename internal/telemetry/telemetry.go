@@ -0,0 +1,133 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.temporal.io/sdk/contrib/opentelemetry"
+	"go.temporal.io/sdk/interceptor"
+)
+
+const (
+	instrumentationName = "bechols/temcp"
+
+	// supported trace exporters
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+	ExporterOTLP   = "otlp"
+
+	// DefaultFilePath is where the file exporter writes spans when no path is set, relative to the working directory
+	DefaultFilePath = "temcp-traces.jsonl"
+)
+
+type (
+	Options struct {
+		// The trace exporter to use: none, stdout, file or otlp, defaults to none
+		Exporter string
+		// The file spans are written to when using the file exporter, defaults to DefaultFilePath
+		FilePath string
+		// The writer spans are written to when using the stdout exporter, defaults to os.Stdout.
+		// The MCP server talks JSON-RPC over stdout, so it should pass os.Stderr here.
+		ConsoleWriter io.Writer
+		// The service name reported on every span
+		ServiceName string
+	}
+
+	ShutdownFunc func(context.Context) error
+)
+
+// Setup installs the global tracer provider and propagator for the given options.
+// The returned shutdown func flushes pending spans and must be called before exit.
+func Setup(ctx context.Context, opts Options) (ShutdownFunc, error) {
+	exporter, closer, err := newExporter(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	if exporter == nil {
+		// tracing disabled, leave the global noop tracer provider in place
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewSchemaless(attribute.String("service.name", opts.ServiceName)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+
+	return func(ctx context.Context) error {
+		err := tp.Shutdown(ctx)
+		if closer != nil {
+			if closeErr := closer.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		return err
+	}, nil
+}
+
+func newExporter(ctx context.Context, opts Options) (sdktrace.SpanExporter, io.Closer, error) {
+	switch opts.Exporter {
+	case "", ExporterNone:
+		return nil, nil, nil
+	case ExporterStdout:
+		w := opts.ConsoleWriter
+		if w == nil {
+			w = os.Stdout
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(w))
+		return exporter, nil, err
+	case ExporterFile:
+		if opts.FilePath == "" {
+			opts.FilePath = DefaultFilePath
+		}
+		f, err := os.OpenFile(opts.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open trace file %s: %w", opts.FilePath, err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, nil, err
+		}
+		return exporter, f, nil
+	case ExporterOTLP:
+		// endpoint, headers and protocol are read from the standard OTEL_EXPORTER_OTLP_* variables
+		exporter, err := otlptracehttp.New(ctx)
+		return exporter, nil, err
+	default:
+		return nil, nil, fmt.Errorf("unknown trace exporter %q, expected one of none, stdout, file or otlp", opts.Exporter)
+	}
+}
+
+// Tracer returns the tracer used for temcp spans
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// NewTemporalInterceptor returns the Temporal SDK interceptor that propagates the
+// trace context from the caller into workflows and activities.
+func NewTemporalInterceptor() (interceptor.Interceptor, error) {
+	return opentelemetry.NewTracingInterceptor(opentelemetry.TracerOptions{})
+}
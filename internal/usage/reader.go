@@ -0,0 +1,59 @@
+package usage
+
+import (
+	"context"
+
+	"bechols/temcp/client/api"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+	usagev1 "go.temporal.io/cloud-sdk/api/usage/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Reader reads usage straight from the Cloud API
+type Reader struct {
+	client *api.Client
+}
+
+func New(client *api.Client) *Reader {
+	return &Reader{client: client}
+}
+
+// Report builds the usage report, the same report as the UsageReport workflow
+func (r *Reader) Report(ctx context.Context, opts Options) (*Report, error) {
+	current, err := r.Summaries(ctx, opts.Period)
+	if err != nil {
+		return nil, err
+	}
+	var previous []*usagev1.Summary
+	if opts.ComparePrevious {
+		if previous, err = r.Summaries(ctx, opts.Period.Previous()); err != nil {
+			return nil, err
+		}
+	}
+	return Build(opts, current, previous), nil
+}
+
+// Summaries pages through the daily usage summaries of the period
+func (r *Reader) Summaries(ctx context.Context, period Period) ([]*usagev1.Summary, error) {
+	req := Request(period)
+	var summaries []*usagev1.Summary
+	for {
+		resp, err := r.client.CloudService().GetUsage(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, resp.GetSummaries()...)
+		if resp.GetNextPageToken() == "" {
+			return summaries, nil
+		}
+		req.PageToken = resp.GetNextPageToken()
+	}
+}
+
+// Request returns the usage request for the first page of the period
+func Request(period Period) *cloudservice.GetUsageRequest {
+	return &cloudservice.GetUsageRequest{
+		StartTimeInclusive: timestamppb.New(period.Start),
+		EndTimeExclusive:   timestamppb.New(period.End),
+	}
+}
@@ -0,0 +1,347 @@
+package usage
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	usagev1 "go.temporal.io/cloud-sdk/api/usage/v1"
+)
+
+// dayLayout is how days are written in periods and reports. Usage is reported per UTC day.
+const dayLayout = "2006-01-02"
+
+// Period is a range of whole UTC days, start inclusive and end exclusive
+type Period struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// MonthPeriod returns the calendar month
+func MonthPeriod(year int, month time.Month) Period {
+	start := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return Period{Start: start, End: start.AddDate(0, 1, 0)}
+}
+
+// DaysPeriod returns the days from first to last, both inclusive, given as YYYY-MM-DD
+func DaysPeriod(first, last string) (Period, error) {
+	start, err := time.Parse(dayLayout, first)
+	if err != nil {
+		return Period{}, fmt.Errorf("start date %q is not YYYY-MM-DD", first)
+	}
+	end, err := time.Parse(dayLayout, last)
+	if err != nil {
+		return Period{}, fmt.Errorf("end date %q is not YYYY-MM-DD", last)
+	}
+	if end.Before(start) {
+		return Period{}, fmt.Errorf("end date %s is before start date %s", last, first)
+	}
+	return Period{Start: start, End: end.AddDate(0, 0, 1)}, nil
+}
+
+// Previous returns the period right before p: the previous calendar month if p is a calendar month,
+// otherwise a period with as many days
+func (p Period) Previous() Period {
+	if p.Start.Day() == 1 && p.End.Equal(p.Start.AddDate(0, 1, 0)) {
+		return MonthPeriod(p.Start.Year(), p.Start.Month()-1)
+	}
+	days := int(p.End.Sub(p.Start).Hours() / 24)
+	return Period{Start: p.Start.AddDate(0, 0, -days), End: p.Start}
+}
+
+func (p Period) String() string {
+	return fmt.Sprintf("%s to %s", p.Start.Format(dayLayout), p.End.AddDate(0, 0, -1).Format(dayLayout))
+}
+
+// Options select what a usage report covers
+type Options struct {
+	Period Period `json:"period"`
+	// Namespaces limits the report to these namespace IDs, all namespaces if empty
+	Namespaces []string `json:"namespaces,omitempty"`
+	// ComparePrevious adds the usage of the previous period and the change from it to the totals
+	ComparePrevious bool `json:"compare_previous"`
+}
+
+// Report is the usage of a period per namespace, day and metric, with totals per namespace and metric
+type Report struct {
+	Period   Period  `json:"period"`
+	Previous *Period `json:"previous,omitempty"`
+	Daily    []Row   `json:"daily"`
+	Totals   []Total `json:"totals"`
+	// AccountTotals are the totals per metric over the namespaces in the report
+	AccountTotals []Total  `json:"account_totals"`
+	Notes         []string `json:"notes,omitempty"`
+}
+
+// Row is a namespace's usage of a metric on a day
+type Row struct {
+	Namespace string  `json:"namespace"`
+	Day       string  `json:"day"`
+	Metric    string  `json:"metric"`
+	Unit      string  `json:"unit"`
+	Value     float64 `json:"value"`
+}
+
+// Total is the usage of a metric over the period, and over the previous period when the report compares.
+// Namespace is empty in account totals.
+type Total struct {
+	Namespace     string   `json:"namespace,omitempty"`
+	Metric        string   `json:"metric"`
+	Unit          string   `json:"unit"`
+	Value         float64  `json:"value"`
+	PreviousValue *float64 `json:"previous_value,omitempty"`
+	Delta         *float64 `json:"delta,omitempty"`
+	// DeltaPercent is left out when the previous value is zero
+	DeltaPercent *float64 `json:"delta_percent,omitempty"`
+}
+
+// MetricName returns the report name of a record type, e.g. actions for RECORD_TYPE_ACTIONS
+func MetricName(t usagev1.RecordType) string {
+	return strings.ToLower(strings.TrimPrefix(t.String(), "RECORD_TYPE_"))
+}
+
+// UnitName returns the report name of a record unit, e.g. byte_seconds for RECORD_UNIT_BYTE_SECONDS
+func UnitName(u usagev1.RecordUnit) string {
+	return strings.ToLower(strings.TrimPrefix(u.String(), "RECORD_UNIT_"))
+}
+
+// totalKey identifies a total. Account totals are flagged rather than keyed by an empty namespace, which is
+// also what records without a namespace group are reported under.
+type totalKey struct {
+	namespace, metric string
+	account           bool
+}
+
+// dailyKey identifies a daily row
+type dailyKey struct {
+	namespace, day, metric string
+}
+
+// Build computes the report from the usage summaries of the period and, when comparing, of the previous period.
+// Rows and totals are ordered by namespace, metric and day, so the same summaries always give the same report.
+func Build(opts Options, current, previous []*usagev1.Summary) *Report {
+	report := &Report{Period: opts.Period, Daily: []Row{}, Totals: []Total{}, AccountTotals: []Total{}}
+	include := func(string) bool { return true }
+	if len(opts.Namespaces) > 0 {
+		wanted := make(map[string]bool, len(opts.Namespaces))
+		for _, namespace := range opts.Namespaces {
+			wanted[namespace] = true
+		}
+		include = func(namespace string) bool { return wanted[namespace] }
+	}
+
+	var (
+		totals     = map[totalKey]*Total{}
+		daily      = map[dailyKey]*Row{}
+		units      = map[string]string{}
+		incomplete []string
+	)
+	addTotal := func(key totalKey, value float64, isPrevious bool) {
+		total, ok := totals[key]
+		if !ok {
+			total = &Total{Namespace: key.namespace, Metric: key.metric}
+			totals[key] = total
+		}
+		if !isPrevious {
+			total.Value += value
+			return
+		}
+		if total.PreviousValue == nil {
+			total.PreviousValue = new(float64)
+		}
+		*total.PreviousValue += value
+	}
+	walk := func(summaries []*usagev1.Summary, isPrevious bool) {
+		for _, summary := range summaries {
+			day := summary.GetStartTime().AsTime().UTC().Format(dayLayout)
+			if summary.GetIncomplete() && !isPrevious {
+				incomplete = append(incomplete, day)
+			}
+			for _, group := range summary.GetRecordGroups() {
+				namespace := groupNamespace(group)
+				if !include(namespace) {
+					continue
+				}
+				for _, record := range group.GetRecords() {
+					metric := MetricName(record.GetType())
+					units[metric] = UnitName(record.GetUnit())
+					if !isPrevious {
+						// A day can hold several records of a metric for a namespace, which add up to one row
+						key := dailyKey{namespace, day, metric}
+						row, ok := daily[key]
+						if !ok {
+							row = &Row{Namespace: namespace, Day: day, Metric: metric, Unit: units[metric]}
+							daily[key] = row
+						}
+						row.Value += record.GetValue()
+					}
+					addTotal(totalKey{namespace: namespace, metric: metric}, record.GetValue(), isPrevious)
+					addTotal(totalKey{metric: metric, account: true}, record.GetValue(), isPrevious)
+				}
+			}
+		}
+	}
+	walk(current, false)
+	if opts.ComparePrevious {
+		previousPeriod := opts.Period.Previous()
+		report.Previous = &previousPeriod
+		walk(previous, true)
+	}
+
+	for _, row := range daily {
+		report.Daily = append(report.Daily, *row)
+	}
+	for key, total := range totals {
+		total.Unit = units[total.Metric]
+		if opts.ComparePrevious {
+			if total.PreviousValue == nil {
+				total.PreviousValue = new(float64)
+			}
+			delta := total.Value - *total.PreviousValue
+			total.Delta = &delta
+			if *total.PreviousValue != 0 {
+				percent := delta / *total.PreviousValue * 100
+				total.DeltaPercent = &percent
+			}
+		}
+		if key.account {
+			report.AccountTotals = append(report.AccountTotals, *total)
+		} else {
+			report.Totals = append(report.Totals, *total)
+		}
+	}
+
+	sort.Slice(report.Daily, func(i, j int) bool {
+		a, b := report.Daily[i], report.Daily[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Metric != b.Metric {
+			return a.Metric < b.Metric
+		}
+		return a.Day < b.Day
+	})
+	sortTotals(report.Totals)
+	sortTotals(report.AccountTotals)
+	if len(incomplete) > 0 {
+		report.Notes = append(report.Notes, fmt.Sprintf("Usage for %s is incomplete and may still change.", strings.Join(incomplete, ", ")))
+	}
+	return report
+}
+
+func groupNamespace(group *usagev1.RecordGroup) string {
+	for _, groupBy := range group.GetGroupBys() {
+		if groupBy.GetKey() == usagev1.GroupByKey_GROUP_BY_KEY_NAMESPACE {
+			return groupBy.GetValue()
+		}
+	}
+	return ""
+}
+
+func sortTotals(totals []Total) {
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Namespace != totals[j].Namespace {
+			return totals[i].Namespace < totals[j].Namespace
+		}
+		return totals[i].Metric < totals[j].Metric
+	})
+}
+
+// Columns of the CSV and markdown renderings
+var (
+	dailyColumns = []string{"namespace", "day", "metric", "unit", "value"}
+	totalColumns = []string{"namespace", "metric", "unit", "value", "previous_value", "delta", "delta_percent"}
+)
+
+// row renders the total; account totals show (all) as their namespace
+func (t Total) row(account bool) []string {
+	namespace := t.Namespace
+	if account {
+		namespace = "(all)"
+	}
+	return []string{namespace, t.Metric, t.Unit, formatValue(t.Value), formatOptional(t.PreviousValue, -1), formatOptional(t.Delta, -1), formatOptional(t.DeltaPercent, 1)}
+}
+
+func (r Row) row() []string {
+	return []string{r.Namespace, r.Day, r.Metric, r.Unit, formatValue(r.Value)}
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func formatOptional(v *float64, precision int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', precision, 64)
+}
+
+// totalRows renders the totals followed by the account totals
+func (r *Report) totalRows() [][]string {
+	rows := make([][]string, 0, len(r.Totals)+len(r.AccountTotals))
+	for _, total := range r.Totals {
+		rows = append(rows, total.row(false))
+	}
+	for _, total := range r.AccountTotals {
+		rows = append(rows, total.row(true))
+	}
+	return rows
+}
+
+// CSV renders the daily rows, or the totals followed by the account totals
+func (r *Report) CSV(daily bool) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	var rows [][]string
+	if daily {
+		rows = append(rows, dailyColumns)
+		for _, row := range r.Daily {
+			rows = append(rows, row.row())
+		}
+	} else {
+		rows = append(rows, totalColumns)
+		rows = append(rows, r.totalRows()...)
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Markdown renders the report as a heading, a table of totals and, if daily is set, a table of daily rows
+func (r *Report) Markdown(daily bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Usage %s\n\n", r.Period)
+	if r.Previous != nil {
+		fmt.Fprintf(&b, "Compared with %s.\n\n", *r.Previous)
+	}
+	writeTable := func(columns []string, rows [][]string) {
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(columns, " | "))
+		fmt.Fprintf(&b, "|%s\n", strings.Repeat("---|", len(columns)))
+		for _, cells := range rows {
+			fmt.Fprintf(&b, "| %s |\n", strings.Join(cells, " | "))
+		}
+	}
+	b.WriteString("## Totals\n\n")
+	writeTable(totalColumns, r.totalRows())
+	if daily {
+		b.WriteString("\n## Daily\n\n")
+		rows := make([][]string, 0, len(r.Daily))
+		for _, row := range r.Daily {
+			rows = append(rows, row.row())
+		}
+		writeTable(dailyColumns, rows)
+	}
+	if len(r.Notes) > 0 {
+		b.WriteString("\nNotes:\n\n")
+		for _, note := range r.Notes {
+			fmt.Fprintf(&b, "- %s\n", note)
+		}
+	}
+	return b.String()
+}
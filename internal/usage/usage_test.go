@@ -0,0 +1,179 @@
+package usage
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	usagev1 "go.temporal.io/cloud-sdk/api/usage/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func summary(day string, groups ...*usagev1.RecordGroup) *usagev1.Summary {
+	start, _ := time.Parse(dayLayout, day)
+	return &usagev1.Summary{StartTime: timestamppb.New(start), EndTime: timestamppb.New(start.AddDate(0, 0, 1)), RecordGroups: groups}
+}
+
+// group returns a record group of actions for the namespace, or without a namespace group-by if namespace is empty
+func group(namespace string, actions ...float64) *usagev1.RecordGroup {
+	g := &usagev1.RecordGroup{}
+	if namespace != "" {
+		g.GroupBys = []*usagev1.GroupBy{{Key: usagev1.GroupByKey_GROUP_BY_KEY_NAMESPACE, Value: namespace}}
+	}
+	for _, value := range actions {
+		g.Records = append(g.Records, &usagev1.Record{
+			Type:  usagev1.RecordType_RECORD_TYPE_ACTIONS,
+			Unit:  usagev1.RecordUnit_RECORD_UNIT_NUMBER,
+			Value: value,
+		})
+	}
+	return g
+}
+
+func TestBuild(t *testing.T) {
+	period, err := DaysPeriod("2026-09-01", "2026-09-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name              string
+		opts              Options
+		current, previous []*usagev1.Summary
+		wantDaily         []Row
+		wantTotals        map[string]float64
+		wantAccount       float64
+		wantDeltaPercent  *float64
+	}{
+		{
+			name: "records of a day add up to one row",
+			opts: Options{Period: period},
+			current: []*usagev1.Summary{
+				summary("2026-09-01", group("a.acct", 10, 5), group("b.acct", 1)),
+				summary("2026-09-02", group("a.acct", 2)),
+			},
+			wantDaily: []Row{
+				{Namespace: "a.acct", Day: "2026-09-01", Metric: "actions", Unit: "number", Value: 15},
+				{Namespace: "a.acct", Day: "2026-09-02", Metric: "actions", Unit: "number", Value: 2},
+				{Namespace: "b.acct", Day: "2026-09-01", Metric: "actions", Unit: "number", Value: 1},
+			},
+			wantTotals:  map[string]float64{"a.acct": 17, "b.acct": 1},
+			wantAccount: 18,
+		},
+		{
+			name:    "records without a namespace aren't counted twice in the account total",
+			opts:    Options{Period: period},
+			current: []*usagev1.Summary{summary("2026-09-01", group("", 7), group("a.acct", 3))},
+			wantDaily: []Row{
+				{Namespace: "", Day: "2026-09-01", Metric: "actions", Unit: "number", Value: 7},
+				{Namespace: "a.acct", Day: "2026-09-01", Metric: "actions", Unit: "number", Value: 3},
+			},
+			wantTotals:  map[string]float64{"": 7, "a.acct": 3},
+			wantAccount: 10,
+		},
+		{
+			name:        "namespace filter",
+			opts:        Options{Period: period, Namespaces: []string{"b.acct"}},
+			current:     []*usagev1.Summary{summary("2026-09-01", group("a.acct", 10), group("b.acct", 4))},
+			wantDaily:   []Row{{Namespace: "b.acct", Day: "2026-09-01", Metric: "actions", Unit: "number", Value: 4}},
+			wantTotals:  map[string]float64{"b.acct": 4},
+			wantAccount: 4,
+		},
+		{
+			name:             "compare with the previous period",
+			opts:             Options{Period: period, ComparePrevious: true},
+			current:          []*usagev1.Summary{summary("2026-09-01", group("a.acct", 15))},
+			previous:         []*usagev1.Summary{summary("2026-08-30", group("a.acct", 10))},
+			wantDaily:        []Row{{Namespace: "a.acct", Day: "2026-09-01", Metric: "actions", Unit: "number", Value: 15}},
+			wantTotals:       map[string]float64{"a.acct": 15},
+			wantAccount:      15,
+			wantDeltaPercent: ptr(50.0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Build(tt.opts, tt.current, tt.previous)
+			if len(report.Daily) != len(tt.wantDaily) {
+				t.Fatalf("daily = %+v, want %+v", report.Daily, tt.wantDaily)
+			}
+			for i := range tt.wantDaily {
+				if report.Daily[i] != tt.wantDaily[i] {
+					t.Errorf("daily[%d] = %+v, want %+v", i, report.Daily[i], tt.wantDaily[i])
+				}
+			}
+			if len(report.Totals) != len(tt.wantTotals) {
+				t.Fatalf("totals = %+v, want %v", report.Totals, tt.wantTotals)
+			}
+			for _, total := range report.Totals {
+				if want, ok := tt.wantTotals[total.Namespace]; !ok || total.Value != want {
+					t.Errorf("total of %q = %v, want %v", total.Namespace, total.Value, want)
+				}
+			}
+			if len(report.AccountTotals) != 1 || report.AccountTotals[0].Value != tt.wantAccount {
+				t.Fatalf("account totals = %+v, want one total of %v", report.AccountTotals, tt.wantAccount)
+			}
+			if got := report.AccountTotals[0].DeltaPercent; (got == nil) != (tt.wantDeltaPercent == nil) ||
+				(got != nil && *got != *tt.wantDeltaPercent) {
+				t.Errorf("account delta percent = %v, want %v", got, tt.wantDeltaPercent)
+			}
+		})
+	}
+}
+
+func TestPeriod(t *testing.T) {
+	tests := []struct {
+		name         string
+		period       Period
+		wantString   string
+		wantPrevious string
+	}{
+		{"calendar month", MonthPeriod(2026, time.March), "2026-03-01 to 2026-03-31", "2026-02-01 to 2026-02-28"},
+		{"january", MonthPeriod(2026, time.January), "2026-01-01 to 2026-01-31", "2025-12-01 to 2025-12-31"},
+		{"date range", mustDays(t, "2026-03-10", "2026-03-16"), "2026-03-10 to 2026-03-16", "2026-03-03 to 2026-03-09"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.period.String(); got != tt.wantString {
+				t.Errorf("String() = %q, want %q", got, tt.wantString)
+			}
+			if got := tt.period.Previous().String(); got != tt.wantPrevious {
+				t.Errorf("Previous() = %q, want %q", got, tt.wantPrevious)
+			}
+		})
+	}
+	if _, err := DaysPeriod("2026-03-16", "2026-03-10"); err == nil {
+		t.Error("DaysPeriod accepted an end date before the start date")
+	}
+}
+
+func TestCSVAccountTotals(t *testing.T) {
+	report := Build(Options{Period: MonthPeriod(2026, time.September)}, []*usagev1.Summary{
+		summary("2026-09-01", group("", 7), group("a.acct", 3)),
+	}, nil)
+	csv, err := report.CSV(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "namespace,metric,unit,value,previous_value,delta,delta_percent\n" +
+		",actions,number,7,,,\n" +
+		"a.acct,actions,number,3,,,\n" +
+		"(all),actions,number,10,,,\n"
+	if csv != want {
+		t.Errorf("CSV() =\n%s\nwant\n%s", csv, want)
+	}
+	if markdown := report.Markdown(false); !strings.Contains(markdown, "| (all) | actions | number | 10 |") {
+		t.Errorf("Markdown() has no account total row:\n%s", markdown)
+	}
+}
+
+func mustDays(t *testing.T, first, last string) Period {
+	t.Helper()
+	p, err := DaysPeriod(first, last)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
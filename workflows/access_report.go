@@ -0,0 +1,198 @@
+package workflows
+
+import (
+	"fmt"
+
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+
+	"bechols/temcp/internal/access"
+	"bechols/temcp/internal/validator"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+	"go.temporal.io/cloud-sdk/api/identity/v1"
+)
+
+const (
+	// access report workflow types
+	NamespaceAccessReportWorkflowType = workflowPrefix + "namespace-access-report"
+	AccountAccessMatrixWorkflowType   = workflowPrefix + "account-access-matrix"
+
+	defaultAccessMatrixConcurrency = 4
+)
+
+type (
+	NamespaceAccessReportInput struct {
+		Namespace string `required:"true" json:"namespace"`
+	}
+
+	AccountAccessMatrixInput struct {
+		Options access.MatrixOptions `json:"options"`
+		// Concurrency caps how many group member lists are read at once, default 4
+		Concurrency int `json:"concurrency"`
+	}
+
+	AccessReportWorkflows interface {
+		// Access Report Workflows
+		NamespaceAccessReport(ctx workflow.Context, in *NamespaceAccessReportInput) (*access.NamespaceReport, error)
+		AccountAccessMatrix(ctx workflow.Context, in *AccountAccessMatrixInput) (*access.Matrix, error)
+	}
+)
+
+func registerAccessReportWorkflows(w worker.Worker, wf AccessReportWorkflows) {
+	for k, v := range map[string]any{
+		NamespaceAccessReportWorkflowType: wf.NamespaceAccessReport,
+		AccountAccessMatrixWorkflowType:   wf.AccountAccessMatrix,
+	} {
+		w.RegisterWorkflowWithOptions(v, workflow.RegisterOptions{Name: k})
+	}
+}
+
+// Report every user and service account with effective access to a namespace, with the source of
+// each grant and the API keys that can act with that access
+func (w *workflows) NamespaceAccessReport(ctx workflow.Context, in *NamespaceAccessReportInput) (*access.NamespaceReport, error) {
+	if err := validator.ValidateStruct(in); err != nil {
+		return nil, fmt.Errorf("invalid input: %s", err)
+	}
+	account := &access.Account{GroupMembers: map[string][]string{}}
+	var err error
+	if account.Users, err = w.getAllUsers(ctx, "", ""); err != nil {
+		return nil, err
+	}
+	if account.ServiceAccounts, err = w.GetAllServiceAccounts(ctx); err != nil {
+		return nil, err
+	}
+	if account.ApiKeys, err = w.GetAllApiKeys(ctx, &cloudservice.GetApiKeysRequest{}); err != nil {
+		return nil, err
+	}
+	if account.Groups, err = w.getAllUserGroups(ctx); err != nil {
+		return nil, err
+	}
+	for _, group := range access.GrantingGroups(account.Groups, in.Namespace) {
+		if !access.MembershipReadable(group) {
+			continue
+		}
+		if account.GroupMembers[group.Id], err = w.getAllUserGroupMemberIDs(ctx, group.Id); err != nil {
+			return nil, err
+		}
+	}
+	return access.BuildNamespaceReport(in.Namespace, account, workflow.Now(ctx)), nil
+}
+
+// Build the access matrix of every user and service account over every namespace, and flag risky
+// patterns. The account is listed in parallel and group members are read in a bounded fan-out.
+func (w *workflows) AccountAccessMatrix(ctx workflow.Context, in *AccountAccessMatrixInput) (*access.Matrix, error) {
+	concurrency := in.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultAccessMatrixConcurrency
+	}
+	var (
+		account    = &access.Account{GroupMembers: map[string][]string{}}
+		namespaces []string
+		errs       []error
+		wg         = workflow.NewWaitGroup(ctx)
+	)
+	list := func(fn func(ctx workflow.Context) error) {
+		wg.Add(1)
+		workflow.Go(ctx, func(ctx workflow.Context) {
+			defer wg.Done()
+			if err := fn(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		})
+	}
+	list(func(ctx workflow.Context) error {
+		all, err := w.GetAllNamespaces(ctx)
+		for _, ns := range all {
+			namespaces = append(namespaces, ns.Namespace)
+		}
+		return err
+	})
+	list(func(ctx workflow.Context) (err error) {
+		account.Users, err = w.getAllUsers(ctx, "", "")
+		return err
+	})
+	list(func(ctx workflow.Context) (err error) {
+		account.ServiceAccounts, err = w.GetAllServiceAccounts(ctx)
+		return err
+	})
+	list(func(ctx workflow.Context) (err error) {
+		account.Groups, err = w.getAllUserGroups(ctx)
+		return err
+	})
+	wg.Wait(ctx)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	slots := workflow.NewSemaphore(ctx, int64(concurrency))
+	for _, group := range account.Groups {
+		if !access.MembershipReadable(group) {
+			continue
+		}
+		if err := slots.Acquire(ctx, 1); err != nil {
+			return nil, err
+		}
+		groupID := group.Id
+		list(func(ctx workflow.Context) error {
+			defer slots.Release(1)
+			members, err := w.getAllUserGroupMemberIDs(ctx, groupID)
+			account.GroupMembers[groupID] = members
+			return err
+		})
+	}
+	wg.Wait(ctx)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return access.BuildMatrix(namespaces, account, in.Options, workflow.Now(ctx)), nil
+}
+
+// getAllUserGroups pages through the user groups. It isn't a workflow of its own because group
+// specs hold oneofs, which don't survive a workflow result outside a proto message.
+func (w *workflows) getAllUserGroups(ctx workflow.Context) ([]*identity.UserGroup, error) {
+	var (
+		groups    = make([]*identity.UserGroup, 0)
+		pageToken = ""
+	)
+	for {
+		resp, err := w.GetUserGroups(ctx, &cloudservice.GetUserGroupsRequest{
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, resp.Groups...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	return groups, nil
+}
+
+// getAllUserGroupMemberIDs returns the user IDs of a group's members
+func (w *workflows) getAllUserGroupMemberIDs(ctx workflow.Context, groupID string) ([]string, error) {
+	var (
+		userIDs   = make([]string, 0)
+		pageToken = ""
+	)
+	for {
+		resp, err := w.GetUserGroupMembers(ctx, &cloudservice.GetUserGroupMembersRequest{
+			GroupId:   groupID,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range resp.Members {
+			if userID := member.GetMemberId().GetUserId(); userID != "" {
+				userIDs = append(userIDs, userID)
+			}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	return userIDs, nil
+}
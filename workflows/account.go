@@ -0,0 +1,122 @@
+package workflows
+
+import (
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+
+	"bechols/temcp/internal/validator"
+	"bechols/temcp/workflows/activities"
+	"go.temporal.io/cloud-sdk/api/account/v1"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+)
+
+const (
+	// account workflow types
+	GetAccountWorkflowType            = workflowPrefix + "get-account"
+	UpdateAccountWorkflowType         = workflowPrefix + "update-account"
+	RotateMetricsClientCAWorkflowType = workflowPrefix + "rotate-metrics-client-ca"
+)
+
+type (
+	RotateMetricsClientCAInput struct {
+		// NewCA is the PEM bundle of the CA to rotate to
+		NewCA []byte `required:"true" json:"new_ca"`
+		// RemoveFingerprints are the SHA-256 fingerprints of the certificates to remove after the overlap.
+		// If empty, every certificate that isn't part of the new CA is removed.
+		RemoveFingerprints []string `json:"remove_fingerprints"`
+		// Overlap is how long both CAs are accepted, DefaultCARotationOverlap by default
+		Overlap time.Duration `json:"overlap"`
+		// OperationTimeout bounds the wait for each account update, namespaceUpdateTimeout by default
+		OperationTimeout time.Duration `json:"operation_timeout"`
+	}
+
+	AccountWorkflows interface {
+		// Account Workflows
+		GetAccount(ctx workflow.Context, in *cloudservice.GetAccountRequest) (*cloudservice.GetAccountResponse, error)
+		UpdateAccount(ctx workflow.Context, in *cloudservice.UpdateAccountRequest) (*cloudservice.UpdateAccountResponse, error)
+		RotateMetricsClientCA(ctx workflow.Context, in *RotateMetricsClientCAInput) (*CARotationReport, error)
+	}
+)
+
+func registerAccountWorkflows(w worker.Worker, wf AccountWorkflows) {
+	for k, v := range map[string]any{
+		GetAccountWorkflowType:            wf.GetAccount,
+		UpdateAccountWorkflowType:         wf.UpdateAccount,
+		RotateMetricsClientCAWorkflowType: wf.RotateMetricsClientCA,
+	} {
+		w.RegisterWorkflowWithOptions(v, workflow.RegisterOptions{Name: k})
+	}
+}
+
+// Get the account
+func (w *workflows) GetAccount(ctx workflow.Context, in *cloudservice.GetAccountRequest) (*cloudservice.GetAccountResponse, error) {
+	return activities.GetAccount(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Update the account
+func (w *workflows) UpdateAccount(ctx workflow.Context, in *cloudservice.UpdateAccountRequest) (*cloudservice.UpdateAccountResponse, error) {
+	return activities.UpdateAccount(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Rotate the CA that clients of the account's metrics endpoint authenticate with: append the new CA, wait
+// for the overlap so clients can switch to certificates it issued, then remove the old CA. Cancelling the
+// rotation during the overlap leaves both CAs in place.
+func (w *workflows) RotateMetricsClientCA(ctx workflow.Context, in *RotateMetricsClientCAInput) (*CARotationReport, error) {
+	if err := validator.ValidateStruct(in); err != nil {
+		return nil, fmt.Errorf("invalid input: %s", err)
+	}
+	if len(in.NewCA) == 0 {
+		return nil, temporal.NewNonRetryableApplicationError("new CA is required", "InvalidInput", nil)
+	}
+	if in.Overlap <= 0 {
+		in.Overlap = DefaultCARotationOverlap
+	}
+	if in.OperationTimeout <= 0 {
+		in.OperationTimeout = namespaceUpdateTimeout
+	}
+
+	report := newCARotationReport(ctx, "account metrics endpoint", in.Overlap)
+	if err := workflow.SetQueryHandler(ctx, CARotationReportQuery, func() (*CARotationReport, error) {
+		return report, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	target := caBundleTarget{
+		get: func(ctx workflow.Context) ([]byte, string, error) {
+			resp, err := w.GetAccount(ctx, &cloudservice.GetAccountRequest{})
+			if err != nil {
+				return nil, "", err
+			}
+			if resp.GetAccount().GetSpec().GetMetrics() == nil {
+				return nil, "", temporal.NewNonRetryableApplicationError(
+					"the account's metrics endpoint isn't enabled, enable it with a CA instead of rotating", "InvalidInput", nil)
+			}
+			return resp.GetAccount().GetSpec().GetMetrics().GetAcceptedClientCa(), resp.GetAccount().GetResourceVersion(), nil
+		},
+		put: func(ctx workflow.Context, bundle []byte, resourceVersion string) (string, error) {
+			resp, err := w.UpdateAccount(ctx, &cloudservice.UpdateAccountRequest{
+				Spec:            &account.AccountSpec{Metrics: &account.MetricsSpec{AcceptedClientCa: bundle}},
+				ResourceVersion: resourceVersion,
+			})
+			if err != nil {
+				return "", err
+			}
+			return resp.GetAsyncOperation().GetId(), nil
+		},
+	}
+	overlap := func(ctx workflow.Context) {
+		_ = workflow.Sleep(ctx, in.Overlap)
+	}
+	if err := w.rotateCA(ctx, report, target, in.NewCA, in.RemoveFingerprints, false, overlap, in.OperationTimeout); err != nil {
+		report.Phase = CARotationPhaseFailed
+		report.Error = err.Error()
+		report.EndTime = workflow.Now(ctx)
+		return report, err
+	}
+	return report, nil
+}
@@ -0,0 +1,20 @@
+package activities
+
+import (
+	"context"
+
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+)
+
+func (a *Activities) GetAccount(ctx context.Context, in *cloudservice.GetAccountRequest) (*cloudservice.GetAccountResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().GetAccount)
+}
+
+func (a *Activities) UpdateAccount(ctx context.Context, in *cloudservice.UpdateAccountRequest) (*cloudservice.UpdateAccountResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().UpdateAccount)
+}
+
+var (
+	GetAccount    = executeActivityFn[*cloudservice.GetAccountRequest, *cloudservice.GetAccountResponse](activitiesPrefix + "GetAccount")
+	UpdateAccount = executeActivityFn[*cloudservice.UpdateAccountRequest, *cloudservice.UpdateAccountResponse](activitiesPrefix + "UpdateAccount")
+)
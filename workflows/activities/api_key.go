@@ -0,0 +1,42 @@
+package activities
+
+import (
+	"context"
+
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+)
+
+func (a *Activities) GetApiKey(ctx context.Context, in *cloudservice.GetApiKeyRequest) (*cloudservice.GetApiKeyResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().GetApiKey)
+}
+
+func (a *Activities) GetApiKeys(ctx context.Context, in *cloudservice.GetApiKeysRequest) (*cloudservice.GetApiKeysResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().GetApiKeys)
+}
+
+// CreateApiKey drops the new key's token from its result, activity results are stored in the workflow history in
+// plaintext. Only a direct CreateApiKey call returns the token.
+func (a *Activities) CreateApiKey(ctx context.Context, in *cloudservice.CreateApiKeyRequest) (*cloudservice.CreateApiKeyResponse, error) {
+	resp, err := executeCloudAPIRequest(ctx, in, a.client.CloudService().CreateApiKey)
+	if err != nil {
+		return nil, err
+	}
+	resp.Token = ""
+	return resp, nil
+}
+
+func (a *Activities) UpdateApiKey(ctx context.Context, in *cloudservice.UpdateApiKeyRequest) (*cloudservice.UpdateApiKeyResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().UpdateApiKey)
+}
+
+func (a *Activities) DeleteApiKey(ctx context.Context, in *cloudservice.DeleteApiKeyRequest) (*cloudservice.DeleteApiKeyResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().DeleteApiKey)
+}
+
+var (
+	GetApiKey    = executeActivityFn[*cloudservice.GetApiKeyRequest, *cloudservice.GetApiKeyResponse](activitiesPrefix + "GetApiKey")
+	GetApiKeys   = executeActivityFn[*cloudservice.GetApiKeysRequest, *cloudservice.GetApiKeysResponse](activitiesPrefix + "GetApiKeys")
+	CreateApiKey = executeActivityFn[*cloudservice.CreateApiKeyRequest, *cloudservice.CreateApiKeyResponse](activitiesPrefix + "CreateApiKey")
+	UpdateApiKey = executeActivityFn[*cloudservice.UpdateApiKeyRequest, *cloudservice.UpdateApiKeyResponse](activitiesPrefix + "UpdateApiKey")
+	DeleteApiKey = executeActivityFn[*cloudservice.DeleteApiKeyRequest, *cloudservice.DeleteApiKeyResponse](activitiesPrefix + "DeleteApiKey")
+)
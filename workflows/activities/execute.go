@@ -3,7 +3,7 @@ package activities
 import (
 	"context"
 
-	"go.temporal.io/sdk/temporal"
+	"bechols/temcp/internal/apierrors"
 	"go.temporal.io/sdk/workflow"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -11,7 +11,7 @@ import (
 )
 
 const (
-	CloudAPIRequestFailure = "temporal-cloud-api-request-failure"
+	CloudAPIRequestFailure = apierrors.CloudAPIRequestFailure
 )
 
 type (
@@ -47,11 +47,7 @@ func executeCloudAPIRequest[Req any, Resp any](
 			codes.Unauthenticated:
 
 			// all these type of errors are application level errors and should fail the activity immediately
-			return out, temporal.NewNonRetryableApplicationError(
-				"CloudAPI request failed",
-				CloudAPIRequestFailure,
-				err,
-			)
+			return out, apierrors.NewCloudAPIRequestFailure(err)
 		}
 	}
 	// probably transient errors, let the activity retry
@@ -0,0 +1,40 @@
+package activities
+
+import (
+	"context"
+
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+)
+
+func (a *Activities) CreateNamespaceExportSink(ctx context.Context, in *cloudservice.CreateNamespaceExportSinkRequest) (*cloudservice.CreateNamespaceExportSinkResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().CreateNamespaceExportSink)
+}
+
+func (a *Activities) GetNamespaceExportSink(ctx context.Context, in *cloudservice.GetNamespaceExportSinkRequest) (*cloudservice.GetNamespaceExportSinkResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().GetNamespaceExportSink)
+}
+
+func (a *Activities) GetNamespaceExportSinks(ctx context.Context, in *cloudservice.GetNamespaceExportSinksRequest) (*cloudservice.GetNamespaceExportSinksResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().GetNamespaceExportSinks)
+}
+
+func (a *Activities) UpdateNamespaceExportSink(ctx context.Context, in *cloudservice.UpdateNamespaceExportSinkRequest) (*cloudservice.UpdateNamespaceExportSinkResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().UpdateNamespaceExportSink)
+}
+
+func (a *Activities) DeleteNamespaceExportSink(ctx context.Context, in *cloudservice.DeleteNamespaceExportSinkRequest) (*cloudservice.DeleteNamespaceExportSinkResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().DeleteNamespaceExportSink)
+}
+
+func (a *Activities) ValidateNamespaceExportSink(ctx context.Context, in *cloudservice.ValidateNamespaceExportSinkRequest) (*cloudservice.ValidateNamespaceExportSinkResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().ValidateNamespaceExportSink)
+}
+
+var (
+	CreateNamespaceExportSink   = executeActivityFn[*cloudservice.CreateNamespaceExportSinkRequest, *cloudservice.CreateNamespaceExportSinkResponse](activitiesPrefix + "CreateNamespaceExportSink")
+	GetNamespaceExportSink      = executeActivityFn[*cloudservice.GetNamespaceExportSinkRequest, *cloudservice.GetNamespaceExportSinkResponse](activitiesPrefix + "GetNamespaceExportSink")
+	GetNamespaceExportSinks     = executeActivityFn[*cloudservice.GetNamespaceExportSinksRequest, *cloudservice.GetNamespaceExportSinksResponse](activitiesPrefix + "GetNamespaceExportSinks")
+	UpdateNamespaceExportSink   = executeActivityFn[*cloudservice.UpdateNamespaceExportSinkRequest, *cloudservice.UpdateNamespaceExportSinkResponse](activitiesPrefix + "UpdateNamespaceExportSink")
+	DeleteNamespaceExportSink   = executeActivityFn[*cloudservice.DeleteNamespaceExportSinkRequest, *cloudservice.DeleteNamespaceExportSinkResponse](activitiesPrefix + "DeleteNamespaceExportSink")
+	ValidateNamespaceExportSink = executeActivityFn[*cloudservice.ValidateNamespaceExportSinkRequest, *cloudservice.ValidateNamespaceExportSinkResponse](activitiesPrefix + "ValidateNamespaceExportSink")
+)
@@ -26,10 +26,32 @@ func (a *Activities) DeleteNamespace(ctx context.Context, in *cloudservice.Delet
 	return executeCloudAPIRequest(ctx, in, a.client.CloudService().DeleteNamespace)
 }
 
+func (a *Activities) AddNamespaceRegion(ctx context.Context, in *cloudservice.AddNamespaceRegionRequest) (*cloudservice.AddNamespaceRegionResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().AddNamespaceRegion)
+}
+
+func (a *Activities) DeleteNamespaceRegion(ctx context.Context, in *cloudservice.DeleteNamespaceRegionRequest) (*cloudservice.DeleteNamespaceRegionResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().DeleteNamespaceRegion)
+}
+
+func (a *Activities) FailoverNamespaceRegion(ctx context.Context, in *cloudservice.FailoverNamespaceRegionRequest) (*cloudservice.FailoverNamespaceRegionResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().FailoverNamespaceRegion)
+}
+
+func (a *Activities) RenameCustomSearchAttribute(ctx context.Context, in *cloudservice.RenameCustomSearchAttributeRequest) (*cloudservice.RenameCustomSearchAttributeResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().RenameCustomSearchAttribute)
+}
+
 var (
 	GetNamespace    = executeActivityFn[*cloudservice.GetNamespaceRequest, *cloudservice.GetNamespaceResponse](activitiesPrefix + "GetNamespace")
 	GetNamespaces   = executeActivityFn[*cloudservice.GetNamespacesRequest, *cloudservice.GetNamespacesResponse](activitiesPrefix + "GetNamespaces")
 	CreateNamespace = executeActivityFn[*cloudservice.CreateNamespaceRequest, *cloudservice.CreateNamespaceResponse](activitiesPrefix + "CreateNamespace")
 	UpdateNamespace = executeActivityFn[*cloudservice.UpdateNamespaceRequest, *cloudservice.UpdateNamespaceResponse](activitiesPrefix + "UpdateNamespace")
 	DeleteNamespace = executeActivityFn[*cloudservice.DeleteNamespaceRequest, *cloudservice.DeleteNamespaceResponse](activitiesPrefix + "DeleteNamespace")
+
+	AddNamespaceRegion      = executeActivityFn[*cloudservice.AddNamespaceRegionRequest, *cloudservice.AddNamespaceRegionResponse](activitiesPrefix + "AddNamespaceRegion")
+	DeleteNamespaceRegion   = executeActivityFn[*cloudservice.DeleteNamespaceRegionRequest, *cloudservice.DeleteNamespaceRegionResponse](activitiesPrefix + "DeleteNamespaceRegion")
+	FailoverNamespaceRegion = executeActivityFn[*cloudservice.FailoverNamespaceRegionRequest, *cloudservice.FailoverNamespaceRegionResponse](activitiesPrefix + "FailoverNamespaceRegion")
+
+	RenameCustomSearchAttribute = executeActivityFn[*cloudservice.RenameCustomSearchAttributeRequest, *cloudservice.RenameCustomSearchAttributeResponse](activitiesPrefix + "RenameCustomSearchAttribute")
 )
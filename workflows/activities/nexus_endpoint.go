@@ -0,0 +1,35 @@
+package activities
+
+import (
+	"context"
+
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+)
+
+func (a *Activities) GetNexusEndpoint(ctx context.Context, in *cloudservice.GetNexusEndpointRequest) (*cloudservice.GetNexusEndpointResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().GetNexusEndpoint)
+}
+
+func (a *Activities) GetNexusEndpoints(ctx context.Context, in *cloudservice.GetNexusEndpointsRequest) (*cloudservice.GetNexusEndpointsResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().GetNexusEndpoints)
+}
+
+func (a *Activities) CreateNexusEndpoint(ctx context.Context, in *cloudservice.CreateNexusEndpointRequest) (*cloudservice.CreateNexusEndpointResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().CreateNexusEndpoint)
+}
+
+func (a *Activities) UpdateNexusEndpoint(ctx context.Context, in *cloudservice.UpdateNexusEndpointRequest) (*cloudservice.UpdateNexusEndpointResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().UpdateNexusEndpoint)
+}
+
+func (a *Activities) DeleteNexusEndpoint(ctx context.Context, in *cloudservice.DeleteNexusEndpointRequest) (*cloudservice.DeleteNexusEndpointResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().DeleteNexusEndpoint)
+}
+
+var (
+	GetNexusEndpoint    = executeActivityFn[*cloudservice.GetNexusEndpointRequest, *cloudservice.GetNexusEndpointResponse](activitiesPrefix + "GetNexusEndpoint")
+	GetNexusEndpoints   = executeActivityFn[*cloudservice.GetNexusEndpointsRequest, *cloudservice.GetNexusEndpointsResponse](activitiesPrefix + "GetNexusEndpoints")
+	CreateNexusEndpoint = executeActivityFn[*cloudservice.CreateNexusEndpointRequest, *cloudservice.CreateNexusEndpointResponse](activitiesPrefix + "CreateNexusEndpoint")
+	UpdateNexusEndpoint = executeActivityFn[*cloudservice.UpdateNexusEndpointRequest, *cloudservice.UpdateNexusEndpointResponse](activitiesPrefix + "UpdateNexusEndpoint")
+	DeleteNexusEndpoint = executeActivityFn[*cloudservice.DeleteNexusEndpointRequest, *cloudservice.DeleteNexusEndpointResponse](activitiesPrefix + "DeleteNexusEndpoint")
+)
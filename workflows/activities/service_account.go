@@ -0,0 +1,35 @@
+package activities
+
+import (
+	"context"
+
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+)
+
+func (a *Activities) GetServiceAccount(ctx context.Context, in *cloudservice.GetServiceAccountRequest) (*cloudservice.GetServiceAccountResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().GetServiceAccount)
+}
+
+func (a *Activities) GetServiceAccounts(ctx context.Context, in *cloudservice.GetServiceAccountsRequest) (*cloudservice.GetServiceAccountsResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().GetServiceAccounts)
+}
+
+func (a *Activities) CreateServiceAccount(ctx context.Context, in *cloudservice.CreateServiceAccountRequest) (*cloudservice.CreateServiceAccountResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().CreateServiceAccount)
+}
+
+func (a *Activities) UpdateServiceAccount(ctx context.Context, in *cloudservice.UpdateServiceAccountRequest) (*cloudservice.UpdateServiceAccountResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().UpdateServiceAccount)
+}
+
+func (a *Activities) DeleteServiceAccount(ctx context.Context, in *cloudservice.DeleteServiceAccountRequest) (*cloudservice.DeleteServiceAccountResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().DeleteServiceAccount)
+}
+
+var (
+	GetServiceAccount    = executeActivityFn[*cloudservice.GetServiceAccountRequest, *cloudservice.GetServiceAccountResponse](activitiesPrefix + "GetServiceAccount")
+	GetServiceAccounts   = executeActivityFn[*cloudservice.GetServiceAccountsRequest, *cloudservice.GetServiceAccountsResponse](activitiesPrefix + "GetServiceAccounts")
+	CreateServiceAccount = executeActivityFn[*cloudservice.CreateServiceAccountRequest, *cloudservice.CreateServiceAccountResponse](activitiesPrefix + "CreateServiceAccount")
+	UpdateServiceAccount = executeActivityFn[*cloudservice.UpdateServiceAccountRequest, *cloudservice.UpdateServiceAccountResponse](activitiesPrefix + "UpdateServiceAccount")
+	DeleteServiceAccount = executeActivityFn[*cloudservice.DeleteServiceAccountRequest, *cloudservice.DeleteServiceAccountResponse](activitiesPrefix + "DeleteServiceAccount")
+)
@@ -0,0 +1,15 @@
+package activities
+
+import (
+	"context"
+
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+)
+
+func (a *Activities) GetUsage(ctx context.Context, in *cloudservice.GetUsageRequest) (*cloudservice.GetUsageResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().GetUsage)
+}
+
+var (
+	GetUsage = executeActivityFn[*cloudservice.GetUsageRequest, *cloudservice.GetUsageResponse](activitiesPrefix + "GetUsage")
+)
@@ -0,0 +1,55 @@
+package activities
+
+import (
+	"context"
+
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+)
+
+func (a *Activities) GetUserGroup(ctx context.Context, in *cloudservice.GetUserGroupRequest) (*cloudservice.GetUserGroupResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().GetUserGroup)
+}
+
+func (a *Activities) GetUserGroups(ctx context.Context, in *cloudservice.GetUserGroupsRequest) (*cloudservice.GetUserGroupsResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().GetUserGroups)
+}
+
+func (a *Activities) CreateUserGroup(ctx context.Context, in *cloudservice.CreateUserGroupRequest) (*cloudservice.CreateUserGroupResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().CreateUserGroup)
+}
+
+func (a *Activities) UpdateUserGroup(ctx context.Context, in *cloudservice.UpdateUserGroupRequest) (*cloudservice.UpdateUserGroupResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().UpdateUserGroup)
+}
+
+func (a *Activities) DeleteUserGroup(ctx context.Context, in *cloudservice.DeleteUserGroupRequest) (*cloudservice.DeleteUserGroupResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().DeleteUserGroup)
+}
+
+func (a *Activities) SetUserGroupNamespaceAccess(ctx context.Context, in *cloudservice.SetUserGroupNamespaceAccessRequest) (*cloudservice.SetUserGroupNamespaceAccessResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().SetUserGroupNamespaceAccess)
+}
+
+func (a *Activities) AddUserGroupMember(ctx context.Context, in *cloudservice.AddUserGroupMemberRequest) (*cloudservice.AddUserGroupMemberResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().AddUserGroupMember)
+}
+
+func (a *Activities) RemoveUserGroupMember(ctx context.Context, in *cloudservice.RemoveUserGroupMemberRequest) (*cloudservice.RemoveUserGroupMemberResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().RemoveUserGroupMember)
+}
+
+func (a *Activities) GetUserGroupMembers(ctx context.Context, in *cloudservice.GetUserGroupMembersRequest) (*cloudservice.GetUserGroupMembersResponse, error) {
+	return executeCloudAPIRequest(ctx, in, a.client.CloudService().GetUserGroupMembers)
+}
+
+var (
+	GetUserGroup                = executeActivityFn[*cloudservice.GetUserGroupRequest, *cloudservice.GetUserGroupResponse](activitiesPrefix + "GetUserGroup")
+	GetUserGroups               = executeActivityFn[*cloudservice.GetUserGroupsRequest, *cloudservice.GetUserGroupsResponse](activitiesPrefix + "GetUserGroups")
+	CreateUserGroup             = executeActivityFn[*cloudservice.CreateUserGroupRequest, *cloudservice.CreateUserGroupResponse](activitiesPrefix + "CreateUserGroup")
+	UpdateUserGroup             = executeActivityFn[*cloudservice.UpdateUserGroupRequest, *cloudservice.UpdateUserGroupResponse](activitiesPrefix + "UpdateUserGroup")
+	DeleteUserGroup             = executeActivityFn[*cloudservice.DeleteUserGroupRequest, *cloudservice.DeleteUserGroupResponse](activitiesPrefix + "DeleteUserGroup")
+	SetUserGroupNamespaceAccess = executeActivityFn[*cloudservice.SetUserGroupNamespaceAccessRequest, *cloudservice.SetUserGroupNamespaceAccessResponse](activitiesPrefix + "SetUserGroupNamespaceAccess")
+	AddUserGroupMember          = executeActivityFn[*cloudservice.AddUserGroupMemberRequest, *cloudservice.AddUserGroupMemberResponse](activitiesPrefix + "AddUserGroupMember")
+	RemoveUserGroupMember       = executeActivityFn[*cloudservice.RemoveUserGroupMemberRequest, *cloudservice.RemoveUserGroupMemberResponse](activitiesPrefix + "RemoveUserGroupMember")
+	GetUserGroupMembers         = executeActivityFn[*cloudservice.GetUserGroupMembersRequest, *cloudservice.GetUserGroupMembersResponse](activitiesPrefix + "GetUserGroupMembers")
+)
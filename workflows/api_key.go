@@ -0,0 +1,315 @@
+package workflows
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+	"google.golang.org/protobuf/proto"
+
+	"bechols/temcp/internal/validator"
+	"bechols/temcp/workflows/activities"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+	"go.temporal.io/cloud-sdk/api/identity/v1"
+)
+
+const (
+	apiKeyUpdateTimeout = 10 * time.Minute
+
+	// api key management workflow types
+	GetApiKeyWorkflowType        = workflowPrefix + "get-api-key"
+	GetApiKeysWorkflowType       = workflowPrefix + "get-api-keys"
+	GetAllApiKeysWorkflowType    = workflowPrefix + "get-all-api-keys"
+	CreateApiKeyWorkflowType     = workflowPrefix + "create-api-key"
+	UpdateApiKeyWorkflowType     = workflowPrefix + "update-api-key"
+	DeleteApiKeyWorkflowType     = workflowPrefix + "delete-api-key"
+	ReconcileApiKeyWorkflowType  = workflowPrefix + "reconcile-api-key"
+	ReconcileApiKeysWorkflowType = workflowPrefix + "reconcile-api-keys"
+
+	// ReconcileOutcomeMissing is the outcome of a spec with no matching key, reconcile never creates keys
+	ReconcileOutcomeMissing = "missing"
+)
+
+type (
+	ReconcileApiKeyInput struct {
+		Spec *identity.ApiKeySpec `required:"true" json:"spec"`
+	}
+	ReconcileApiKeyOutput struct {
+		ApiKey  *identity.ApiKey `json:"api_key"`
+		Outcome ReconcileOutcome `json:"outcome"`
+		Error   string           `json:"error"`
+	}
+
+	ReconcileApiKeysInput struct {
+		Specs []*identity.ApiKeySpec `required:"true" json:"specs"`
+		// DeleteUnaccounted deletes the keys of the owners named in Specs that no spec accounts for.
+		// Keys of other owners are never touched.
+		DeleteUnaccounted bool `json:"delete_unaccounted"`
+	}
+	ReconcileApiKeysOutput struct {
+		Results []*ReconcileApiKeyOutput `json:"results"`
+	}
+
+	ApiKeyWorkflows interface {
+		// API Key Management Workflows
+		GetApiKey(ctx workflow.Context, in *cloudservice.GetApiKeyRequest) (*cloudservice.GetApiKeyResponse, error)
+		GetApiKeys(ctx workflow.Context, in *cloudservice.GetApiKeysRequest) (*cloudservice.GetApiKeysResponse, error)
+		GetAllApiKeys(ctx workflow.Context, in *cloudservice.GetApiKeysRequest) ([]*identity.ApiKey, error)
+		CreateApiKey(ctx workflow.Context, in *cloudservice.CreateApiKeyRequest) (*cloudservice.CreateApiKeyResponse, error)
+		UpdateApiKey(ctx workflow.Context, in *cloudservice.UpdateApiKeyRequest) (*cloudservice.UpdateApiKeyResponse, error)
+		DeleteApiKey(ctx workflow.Context, in *cloudservice.DeleteApiKeyRequest) (*cloudservice.DeleteApiKeyResponse, error)
+		ReconcileApiKey(ctx workflow.Context, in *ReconcileApiKeyInput) (*ReconcileApiKeyOutput, error)
+		ReconcileApiKeys(ctx workflow.Context, in *ReconcileApiKeysInput) (*ReconcileApiKeysOutput, error)
+	}
+)
+
+func (o *ReconcileApiKeyOutput) setError(err error) {
+	var applicationErr *temporal.ApplicationError
+	if errors.As(err, &applicationErr) {
+		o.Error = applicationErr.Error()
+		o.Outcome = ReconcileOutcomeError
+	}
+}
+
+func registerApiKeyWorkflows(w worker.Worker, wf ApiKeyWorkflows) {
+	for k, v := range map[string]any{
+		GetApiKeyWorkflowType:        wf.GetApiKey,
+		GetApiKeysWorkflowType:       wf.GetApiKeys,
+		GetAllApiKeysWorkflowType:    wf.GetAllApiKeys,
+		CreateApiKeyWorkflowType:     wf.CreateApiKey,
+		UpdateApiKeyWorkflowType:     wf.UpdateApiKey,
+		DeleteApiKeyWorkflowType:     wf.DeleteApiKey,
+		ReconcileApiKeyWorkflowType:  wf.ReconcileApiKey,
+		ReconcileApiKeysWorkflowType: wf.ReconcileApiKeys,
+	} {
+		w.RegisterWorkflowWithOptions(v, workflow.RegisterOptions{Name: k})
+	}
+}
+
+// Get an api key
+func (w *workflows) GetApiKey(ctx workflow.Context, in *cloudservice.GetApiKeyRequest) (*cloudservice.GetApiKeyResponse, error) {
+	return activities.GetApiKey(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Get multiple api keys
+func (w *workflows) GetApiKeys(ctx workflow.Context, in *cloudservice.GetApiKeysRequest) (*cloudservice.GetApiKeysResponse, error) {
+	return activities.GetApiKeys(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Get all api keys matching the request's owner filter, the request's page token is ignored
+func (w *workflows) GetAllApiKeys(ctx workflow.Context, in *cloudservice.GetApiKeysRequest) ([]*identity.ApiKey, error) {
+	var (
+		apiKeys   = make([]*identity.ApiKey, 0)
+		pageToken = ""
+	)
+	for {
+		resp, err := w.GetApiKeys(ctx, &cloudservice.GetApiKeysRequest{
+			OwnerId:   in.GetOwnerId(),
+			OwnerType: in.GetOwnerType(),
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		apiKeys = append(apiKeys, resp.ApiKeys...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	return apiKeys, nil
+}
+
+// Create an api key. The response has no token, so that the token never reaches the workflow history.
+// Without an async operation ID the ID is derived from the key's owner and display name, so a retried
+// activity is the same operation to the Cloud API instead of a second key.
+func (w *workflows) CreateApiKey(ctx workflow.Context, in *cloudservice.CreateApiKeyRequest) (*cloudservice.CreateApiKeyResponse, error) {
+	if in.GetAsyncOperationId() == "" {
+		in = proto.Clone(in).(*cloudservice.CreateApiKeyRequest)
+		in.AsyncOperationId = ApiKeyCreateOperationID(in.GetSpec().GetOwnerType(), in.GetSpec().GetOwnerId(), in.GetSpec().GetDisplayName())
+	}
+	return activities.CreateApiKey(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// ApiKeyCreateOperationID returns the async operation ID that creates the owner's key with the display name
+func ApiKeyCreateOperationID(ownerType identity.OwnerType, ownerID, displayName string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte("temcp:api-key:"+ownerType.String()+":"+ownerID+":"+displayName)).String()
+}
+
+// Update an api key
+func (w *workflows) UpdateApiKey(ctx workflow.Context, in *cloudservice.UpdateApiKeyRequest) (*cloudservice.UpdateApiKeyResponse, error) {
+	return activities.UpdateApiKey(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Delete an api key
+func (w *workflows) DeleteApiKey(ctx workflow.Context, in *cloudservice.DeleteApiKeyRequest) (*cloudservice.DeleteApiKeyResponse, error) {
+	return activities.DeleteApiKey(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// apiKeyMatches reports whether the key is the one the spec describes, keys are identified by owner and display name
+func apiKeyMatches(key *identity.ApiKey, spec *identity.ApiKeySpec) bool {
+	return key.GetSpec().GetOwnerId() == spec.GetOwnerId() &&
+		key.GetSpec().GetOwnerType() == spec.GetOwnerType() &&
+		key.GetSpec().GetDisplayName() == spec.GetDisplayName()
+}
+
+// apiKeyChanged reports whether the mutable parts of the key differ from the spec
+func apiKeyChanged(key *identity.ApiKey, spec *identity.ApiKeySpec) bool {
+	return key.GetSpec().GetDescription() != spec.GetDescription() ||
+		key.GetSpec().GetDisabled() != spec.GetDisabled() ||
+		!proto.Equal(key.GetSpec().GetExpiryTime(), spec.GetExpiryTime())
+}
+
+func (w *workflows) reconcileApiKey(ctx workflow.Context, spec *identity.ApiKeySpec, apiKey *identity.ApiKey) (*ReconcileApiKeyOutput, error) {
+	var (
+		keyID     string
+		asyncOpID string
+		out       = &ReconcileApiKeyOutput{}
+		err       error
+	)
+	defer func() {
+		if err != nil {
+			out.setError(err)
+		}
+		if apiKey != nil {
+			out.ApiKey = apiKey
+		} else if spec != nil {
+			out.ApiKey = &identity.ApiKey{
+				Id:   keyID,
+				Spec: spec,
+			}
+		}
+	}()
+	if apiKey == nil {
+		// no api key found. a key created here would be useless: its token is the only way to use it, and
+		// keeping the token out of the workflow history means the workflow never sees it.
+		out.Outcome = ReconcileOutcomeMissing
+		out.Error = fmt.Sprintf("no api key with display name %s for owner %s, create it with temporal_create_api_key", spec.GetDisplayName(), spec.GetOwnerId())
+		return out, nil
+
+	} else if apiKeyChanged(apiKey, spec) {
+		var updateResp *cloudservice.UpdateApiKeyResponse
+		// api key found, and the mutable fields don't match, update it. the owner can't be changed.
+		updated := proto.Clone(apiKey.Spec).(*identity.ApiKeySpec)
+		updated.Description = spec.Description
+		updated.Disabled = spec.Disabled
+		updated.ExpiryTime = spec.ExpiryTime
+		updateResp, err = w.UpdateApiKey(ctx, &cloudservice.UpdateApiKeyRequest{
+			KeyId:           apiKey.Id,
+			Spec:            updated,
+			ResourceVersion: apiKey.ResourceVersion,
+		})
+		if err != nil {
+			return out, err
+		}
+		keyID = apiKey.Id
+		asyncOpID = updateResp.AsyncOperation.Id
+		out.Outcome = ReconcileOutcomeUpdated
+
+	} else {
+		// nothing to change
+		out.Outcome = ReconcileOutcomeUnchanged
+		return out, nil
+	}
+
+	if asyncOpID != "" {
+		// wait for the operation to complete
+		_, err = w.WaitForAsyncOperation(ctx, &WaitForAsyncOperationInput{
+			AsyncOperationID: asyncOpID,
+			Timeout:          apiKeyUpdateTimeout,
+		})
+		if err != nil {
+			return out, err
+		}
+	}
+	var getResp *cloudservice.GetApiKeyResponse
+	getResp, err = w.GetApiKey(ctx, &cloudservice.GetApiKeyRequest{
+		KeyId: keyID,
+	})
+	if err != nil {
+		return out, err
+	}
+	apiKey = getResp.ApiKey
+	return out, nil
+}
+
+// Reconcile an api key, update the key of the owner with the spec's display name, or report it missing if there is none.
+func (w *workflows) ReconcileApiKey(ctx workflow.Context, in *ReconcileApiKeyInput) (*ReconcileApiKeyOutput, error) {
+	if err := validator.ValidateStruct(in); err != nil {
+		return nil, fmt.Errorf("invalid input: %s", err)
+	}
+	apiKeys, err := w.GetAllApiKeys(ctx, &cloudservice.GetApiKeysRequest{
+		OwnerId:   in.Spec.OwnerId,
+		OwnerType: in.Spec.OwnerType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var apiKey *identity.ApiKey
+	for _, k := range apiKeys {
+		if !apiKeyMatches(k, in.Spec) {
+			continue
+		}
+		if apiKey != nil {
+			return nil, fmt.Errorf("multiple api keys found with display name %s for owner %s", in.Spec.DisplayName, in.Spec.OwnerId)
+		}
+		apiKey = k
+	}
+	out, err := w.reconcileApiKey(ctx, in.Spec, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Reconcile multiple api keys by owner and display name, update existing keys, report missing keys,
+// and optionally delete the unaccounted keys of the owners named in the specs.
+func (w *workflows) ReconcileApiKeys(ctx workflow.Context, in *ReconcileApiKeysInput) (*ReconcileApiKeysOutput, error) {
+	if err := validator.ValidateStruct(in); err != nil {
+		return nil, fmt.Errorf("invalid input: %s", err)
+	}
+	apiKeys, err := w.GetAllApiKeys(ctx, &cloudservice.GetApiKeysRequest{})
+	if err != nil {
+		return nil, err
+	}
+	owners := map[string]bool{}
+	for _, spec := range in.Specs {
+		owners[spec.OwnerId] = true
+	}
+	out := &ReconcileApiKeysOutput{}
+	for i := range in.Specs {
+		var apiKey *identity.ApiKey
+		for j, k := range apiKeys {
+			if apiKeyMatches(k, in.Specs[i]) {
+				apiKey = k
+				apiKeys = append(apiKeys[:j], apiKeys[j+1:]...) // remove the key from the list
+				break
+			}
+		}
+		// reconcile the api key
+		o, _ := w.reconcileApiKey(ctx, in.Specs[i], apiKey)
+		out.Results = append(out.Results, o)
+	}
+	// whats left in the list is only the unaccounted keys
+	for _, k := range apiKeys {
+		if in.DeleteUnaccounted && owners[k.GetSpec().GetOwnerId()] {
+			o := &ReconcileApiKeyOutput{
+				ApiKey:  k,
+				Outcome: ReconcileOutcomeDeleted,
+			}
+			_, err := w.DeleteApiKey(ctx, &cloudservice.DeleteApiKeyRequest{
+				KeyId:           k.Id,
+				ResourceVersion: k.ResourceVersion,
+			})
+			if err != nil {
+				o.setError(err)
+			}
+			out.Results = append(out.Results, o)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,192 @@
+package workflows
+
+import (
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"bechols/temcp/internal/cabundle"
+	"bechols/temcp/internal/preflight"
+)
+
+const (
+	// CARotationReportQuery returns a CA rotation's report so far
+	CARotationReportQuery = "report"
+
+	// DefaultCARotationOverlap is how long both CAs are accepted when no overlap is given
+	DefaultCARotationOverlap = time.Hour
+
+	// CA rotation phases
+	CARotationPhaseAppending   = "appending"
+	CARotationPhaseOverlapping = "overlapping"
+	CARotationPhaseRemoving    = "removing"
+	CARotationPhaseCompleted   = "completed"
+	CARotationPhaseFailed      = "failed"
+)
+
+type (
+	// CARotationReport is the report of a CA rotation, also returned by the report query while it runs
+	CARotationReport struct {
+		Target    string        `json:"target"`
+		Phase     string        `json:"phase"`
+		StartTime time.Time     `json:"start_time"`
+		EndTime   time.Time     `json:"end_time,omitempty"`
+		Overlap   time.Duration `json:"overlap"`
+		// Checks are the findings on the new CA; errors fail the rotation before anything changes
+		Checks *preflight.Report `json:"checks,omitempty"`
+		// CurrentChecks are the findings on the bundle the rotation starts from. Errors on certificates the
+		// rotation keeps fail it before anything changes; expired certificates it removes don't. A rotation that
+		// only appends leaves them as they were, and only reports them.
+		CurrentChecks *preflight.Report `json:"current_checks,omitempty"`
+		// Confirmed is set when a confirmation signal ended the overlap before its timer
+		Confirmed   bool   `json:"confirmed,omitempty"`
+		ConfirmNote string `json:"confirm_note,omitempty"`
+		// Added are the certificates of the new CA that the bundle didn't hold yet
+		Added []cabundle.Certificate `json:"added"`
+		// PendingRemoval are the fingerprints of the old certificates, removed after the overlap
+		PendingRemoval    []string               `json:"pending_removal"`
+		Removed           []cabundle.Certificate `json:"removed"`
+		AppendOperationID string                 `json:"append_operation_id,omitempty"`
+		RemoveOperationID string                 `json:"remove_operation_id,omitempty"`
+		Error             string                 `json:"error,omitempty"`
+	}
+
+	// caBundleTarget reads and writes the CA bundle a rotation works on. put returns the async operation ID
+	// of the update, if any.
+	caBundleTarget struct {
+		get func(ctx workflow.Context) (bundle []byte, resourceVersion string, err error)
+		put func(ctx workflow.Context, bundle []byte, resourceVersion string) (string, error)
+	}
+)
+
+func newCARotationReport(ctx workflow.Context, target string, overlap time.Duration) *CARotationReport {
+	return &CARotationReport{
+		Target:         target,
+		Phase:          CARotationPhaseAppending,
+		StartTime:      workflow.Now(ctx),
+		Overlap:        overlap,
+		Added:          []cabundle.Certificate{},
+		PendingRemoval: []string{},
+		Removed:        []cabundle.Certificate{},
+	}
+}
+
+// rotateCA appends the new CA to the target's bundle, waits for the overlap, then removes the old certificates:
+// the ones given, or every certificate the bundle held before that isn't part of the new CA. Certificates
+// added by others during the overlap are kept. With appendOnly, it stops once the new CA is appended.
+func (w *workflows) rotateCA(ctx workflow.Context, report *CARotationReport, target caBundleTarget, newCA []byte, remove []string,
+	appendOnly bool, overlap func(ctx workflow.Context), timeout time.Duration) error {
+	invalid := func(format string, args ...any) error {
+		return temporal.NewNonRetryableApplicationError(fmt.Sprintf(format, args...), "InvalidInput", nil)
+	}
+	if report.Checks = preflight.CheckCABundle("new_ca", newCA, workflow.Now(ctx)); !report.Checks.Valid {
+		return invalid("new CA: %s", report.Checks.FirstError())
+	}
+	newCerts, err := cabundle.Parse(newCA)
+	if err != nil {
+		return invalid("new CA: %s", err)
+	}
+	isNew := map[string]bool{}
+	for _, cert := range newCerts {
+		isNew[cert.Fingerprint] = true
+	}
+
+	bundle, resourceVersion, err := target.get(ctx)
+	if err != nil {
+		return err
+	}
+	report.CurrentChecks = preflight.CheckCABundle("current_ca", bundle, workflow.Now(ctx))
+	current, err := cabundle.Parse(bundle)
+	if err != nil {
+		return invalid("current CA bundle of %s: %s", report.Target, err)
+	}
+	switch {
+	case appendOnly:
+		// nothing is removed
+	case len(remove) == 0:
+		for _, cert := range current {
+			if !isNew[cert.Fingerprint] {
+				report.PendingRemoval = append(report.PendingRemoval, cert.Fingerprint)
+			}
+		}
+	default:
+		for _, fingerprint := range remove {
+			fingerprint = cabundle.NormalizeFingerprint(fingerprint)
+			if isNew[fingerprint] {
+				return invalid("certificate %s is part of the new CA, it can't be removed", fingerprint)
+			}
+			report.PendingRemoval = append(report.PendingRemoval, fingerprint)
+		}
+	}
+
+	// The certificates a rotation keeps must be as sound as the new CA, since clients keep relying on them.
+	// Appending leaves the bundle's certificates as they were, so it only reports on them.
+	removing := map[string]bool{}
+	for _, fingerprint := range report.PendingRemoval {
+		removing[fingerprint] = true
+	}
+	var kept []cabundle.Certificate
+	for _, cert := range current {
+		if !removing[cert.Fingerprint] && !isNew[cert.Fingerprint] {
+			kept = append(kept, cert)
+		}
+	}
+	if len(kept) > 0 && !appendOnly {
+		if check := preflight.CheckCABundle("current_ca", cabundle.Encode(kept), workflow.Now(ctx)); !check.Valid {
+			return invalid("current CA bundle of %s keeps a certificate that isn't valid: %s", report.Target, check.FirstError())
+		}
+	}
+
+	appended, added, err := cabundle.Append(bundle, newCA)
+	if err != nil {
+		return invalid("%s", err)
+	}
+	report.Added = added
+	if len(added) > 0 {
+		if report.AppendOperationID, err = w.putCABundle(ctx, target, appended, resourceVersion, timeout); err != nil {
+			return err
+		}
+	}
+
+	if appendOnly {
+		report.Phase = CARotationPhaseCompleted
+		report.EndTime = workflow.Now(ctx)
+		return nil
+	}
+
+	report.Phase = CARotationPhaseOverlapping
+	overlap(ctx)
+
+	report.Phase = CARotationPhaseRemoving
+	if bundle, resourceVersion, err = target.get(ctx); err != nil {
+		return err
+	}
+	trimmed, removed, err := cabundle.Remove(bundle, report.PendingRemoval)
+	if err != nil {
+		return invalid("%s", err)
+	}
+	report.Removed = removed
+	if len(removed) > 0 {
+		if report.RemoveOperationID, err = w.putCABundle(ctx, target, trimmed, resourceVersion, timeout); err != nil {
+			return err
+		}
+	}
+	report.Phase = CARotationPhaseCompleted
+	report.EndTime = workflow.Now(ctx)
+	return nil
+}
+
+// putCABundle writes the bundle and waits for the update's async operation
+func (w *workflows) putCABundle(ctx workflow.Context, target caBundleTarget, bundle []byte, resourceVersion string, timeout time.Duration) (string, error) {
+	asyncOpID, err := target.put(ctx, bundle, resourceVersion)
+	if err != nil || asyncOpID == "" {
+		return asyncOpID, err
+	}
+	_, err = w.WaitForAsyncOperation(ctx, &WaitForAsyncOperationInput{
+		AsyncOperationID: asyncOpID,
+		Timeout:          timeout,
+	})
+	return asyncOpID, err
+}
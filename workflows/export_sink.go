@@ -0,0 +1,74 @@
+package workflows
+
+import (
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+
+	"bechols/temcp/workflows/activities"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+)
+
+const (
+	// export sink workflow types
+	CreateNamespaceExportSinkWorkflowType   = workflowPrefix + "create-namespace-export-sink"
+	GetNamespaceExportSinkWorkflowType      = workflowPrefix + "get-namespace-export-sink"
+	GetNamespaceExportSinksWorkflowType     = workflowPrefix + "get-namespace-export-sinks"
+	UpdateNamespaceExportSinkWorkflowType   = workflowPrefix + "update-namespace-export-sink"
+	DeleteNamespaceExportSinkWorkflowType   = workflowPrefix + "delete-namespace-export-sink"
+	ValidateNamespaceExportSinkWorkflowType = workflowPrefix + "validate-namespace-export-sink"
+)
+
+type (
+	ExportSinkWorkflows interface {
+		// Export Sink Workflows
+		CreateNamespaceExportSink(ctx workflow.Context, in *cloudservice.CreateNamespaceExportSinkRequest) (*cloudservice.CreateNamespaceExportSinkResponse, error)
+		GetNamespaceExportSink(ctx workflow.Context, in *cloudservice.GetNamespaceExportSinkRequest) (*cloudservice.GetNamespaceExportSinkResponse, error)
+		GetNamespaceExportSinks(ctx workflow.Context, in *cloudservice.GetNamespaceExportSinksRequest) (*cloudservice.GetNamespaceExportSinksResponse, error)
+		UpdateNamespaceExportSink(ctx workflow.Context, in *cloudservice.UpdateNamespaceExportSinkRequest) (*cloudservice.UpdateNamespaceExportSinkResponse, error)
+		DeleteNamespaceExportSink(ctx workflow.Context, in *cloudservice.DeleteNamespaceExportSinkRequest) (*cloudservice.DeleteNamespaceExportSinkResponse, error)
+		ValidateNamespaceExportSink(ctx workflow.Context, in *cloudservice.ValidateNamespaceExportSinkRequest) (*cloudservice.ValidateNamespaceExportSinkResponse, error)
+	}
+)
+
+func registerExportSinkWorkflows(w worker.Worker, wf ExportSinkWorkflows) {
+	for k, v := range map[string]any{
+		CreateNamespaceExportSinkWorkflowType:   wf.CreateNamespaceExportSink,
+		GetNamespaceExportSinkWorkflowType:      wf.GetNamespaceExportSink,
+		GetNamespaceExportSinksWorkflowType:     wf.GetNamespaceExportSinks,
+		UpdateNamespaceExportSinkWorkflowType:   wf.UpdateNamespaceExportSink,
+		DeleteNamespaceExportSinkWorkflowType:   wf.DeleteNamespaceExportSink,
+		ValidateNamespaceExportSinkWorkflowType: wf.ValidateNamespaceExportSink,
+	} {
+		w.RegisterWorkflowWithOptions(v, workflow.RegisterOptions{Name: k})
+	}
+}
+
+// Create an export sink for a namespace
+func (w *workflows) CreateNamespaceExportSink(ctx workflow.Context, in *cloudservice.CreateNamespaceExportSinkRequest) (*cloudservice.CreateNamespaceExportSinkResponse, error) {
+	return activities.CreateNamespaceExportSink(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Get an export sink of a namespace
+func (w *workflows) GetNamespaceExportSink(ctx workflow.Context, in *cloudservice.GetNamespaceExportSinkRequest) (*cloudservice.GetNamespaceExportSinkResponse, error) {
+	return activities.GetNamespaceExportSink(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Get the export sinks of a namespace
+func (w *workflows) GetNamespaceExportSinks(ctx workflow.Context, in *cloudservice.GetNamespaceExportSinksRequest) (*cloudservice.GetNamespaceExportSinksResponse, error) {
+	return activities.GetNamespaceExportSinks(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Update an export sink of a namespace
+func (w *workflows) UpdateNamespaceExportSink(ctx workflow.Context, in *cloudservice.UpdateNamespaceExportSinkRequest) (*cloudservice.UpdateNamespaceExportSinkResponse, error) {
+	return activities.UpdateNamespaceExportSink(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Delete an export sink of a namespace
+func (w *workflows) DeleteNamespaceExportSink(ctx workflow.Context, in *cloudservice.DeleteNamespaceExportSinkRequest) (*cloudservice.DeleteNamespaceExportSinkResponse, error) {
+	return activities.DeleteNamespaceExportSink(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Validate an export sink spec by having Temporal Cloud write a test file to it
+func (w *workflows) ValidateNamespaceExportSink(ctx workflow.Context, in *cloudservice.ValidateNamespaceExportSinkRequest) (*cloudservice.ValidateNamespaceExportSinkResponse, error) {
+	return activities.ValidateNamespaceExportSink(withInfiniteRetryActivityOptions(ctx), in)
+}
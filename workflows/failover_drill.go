@@ -0,0 +1,274 @@
+package workflows
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+
+	"bechols/temcp/internal/apierrors"
+	"bechols/temcp/internal/preflight"
+	"bechols/temcp/internal/validator"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+	"go.temporal.io/cloud-sdk/api/operation/v1"
+)
+
+const (
+	// failover drill workflow types
+	FailoverDrillWorkflowType = workflowPrefix + "failover-drill"
+
+	// FailoverDrillAbortSignal ends the soak early, or skips it if the failover is still running. The
+	// namespace is always failed back. The signal's payload is an optional reason string.
+	FailoverDrillAbortSignal = "abort"
+	// FailoverDrillReportQuery returns the drill's report so far
+	FailoverDrillReportQuery = "report"
+
+	// DefaultFailoverDrillSoak is how long the namespace stays in the replica region when no soak is given
+	DefaultFailoverDrillSoak = 10 * time.Minute
+	// failoverDrillPollInterval is how often a failover the drill stopped waiting for is polled, when the
+	// async operation doesn't say
+	failoverDrillPollInterval = 10 * time.Second
+
+	// Failover drill phases
+	FailoverDrillPhaseFailingOver = "failing_over"
+	FailoverDrillPhaseSoaking     = "soaking"
+	FailoverDrillPhaseFailingBack = "failing_back"
+	FailoverDrillPhaseCompleted   = "completed"
+	FailoverDrillPhaseFailed      = "failed"
+)
+
+type (
+	FailoverDrillInput struct {
+		Namespace string `required:"true" json:"namespace"`
+		// Region is the replica to fail over to. It may be left out when the namespace has one replica.
+		Region string `json:"region"`
+		// Soak is how long the namespace stays in the replica region before failing back
+		Soak time.Duration `json:"soak"`
+		// OperationTimeout bounds the wait for each failover, namespaceUpdateTimeout by default
+		OperationTimeout time.Duration `json:"operation_timeout"`
+	}
+
+	// FailoverDrillReport is the timing report of a drill, also returned by the report query while it runs
+	FailoverDrillReport struct {
+		Namespace      string               `json:"namespace"`
+		OriginalRegion string               `json:"original_region"`
+		DrillRegion    string               `json:"drill_region"`
+		Phase          string               `json:"phase"`
+		StartTime      time.Time            `json:"start_time"`
+		EndTime        time.Time            `json:"end_time,omitempty"`
+		PlannedSoak    time.Duration        `json:"planned_soak"`
+		Soak           time.Duration        `json:"soak"`
+		Aborted        bool                 `json:"aborted"`
+		AbortReason    string               `json:"abort_reason,omitempty"`
+		Steps          []*FailoverDrillStep `json:"steps"`
+		Error          string               `json:"error,omitempty"`
+	}
+
+	// FailoverDrillStep is one failover of the drill
+	FailoverDrillStep struct {
+		Name             string        `json:"name"`
+		FromRegion       string        `json:"from_region"`
+		ToRegion         string        `json:"to_region"`
+		AsyncOperationID string        `json:"async_operation_id"`
+		StartTime        time.Time     `json:"start_time"`
+		EndTime          time.Time     `json:"end_time,omitempty"`
+		Duration         time.Duration `json:"duration"`
+	}
+
+	FailoverDrillWorkflows interface {
+		// Failover Drill Workflows
+		FailoverDrill(ctx workflow.Context, in *FailoverDrillInput) (*FailoverDrillReport, error)
+	}
+)
+
+func registerFailoverDrillWorkflows(w worker.Worker, wf FailoverDrillWorkflows) {
+	for k, v := range map[string]any{
+		FailoverDrillWorkflowType: wf.FailoverDrill,
+	} {
+		w.RegisterWorkflowWithOptions(v, workflow.RegisterOptions{Name: k})
+	}
+}
+
+// Rehearse a failover of a multi-region namespace: fail over to the replica, soak, then fail back to the
+// original active region. Once the failover is submitted, the namespace is failed back even if the drill is
+// aborted, cancelled or the failover fails part way.
+func (w *workflows) FailoverDrill(ctx workflow.Context, in *FailoverDrillInput) (*FailoverDrillReport, error) {
+	if err := validator.ValidateStruct(in); err != nil {
+		return nil, fmt.Errorf("invalid input: %s", err)
+	}
+	if in.Namespace == "" {
+		return nil, temporal.NewNonRetryableApplicationError("namespace is required", "InvalidInput", nil)
+	}
+	if in.Soak <= 0 {
+		in.Soak = DefaultFailoverDrillSoak
+	}
+	if in.OperationTimeout <= 0 {
+		in.OperationTimeout = namespaceUpdateTimeout
+	}
+
+	report := &FailoverDrillReport{
+		Namespace:   in.Namespace,
+		Phase:       FailoverDrillPhaseFailingOver,
+		StartTime:   workflow.Now(ctx),
+		PlannedSoak: in.Soak,
+		Steps:       []*FailoverDrillStep{},
+	}
+	if err := workflow.SetQueryHandler(ctx, FailoverDrillReportQuery, func() (*FailoverDrillReport, error) {
+		return report, nil
+	}); err != nil {
+		return nil, err
+	}
+	fail := func(err error) (*FailoverDrillReport, error) {
+		report.Phase = FailoverDrillPhaseFailed
+		report.Error = err.Error()
+		report.EndTime = workflow.Now(ctx)
+		return report, err
+	}
+
+	abortCh := workflow.GetSignalChannel(ctx, FailoverDrillAbortSignal)
+	receiveAbort := func(c workflow.ReceiveChannel, more bool) {
+		var reason string
+		c.Receive(ctx, &reason)
+		report.Aborted = true
+		report.AbortReason = reason
+	}
+
+	getResp, err := w.GetNamespace(ctx, &cloudservice.GetNamespaceRequest{Namespace: in.Namespace})
+	if err != nil {
+		return fail(err)
+	}
+	ns := getResp.Namespace
+	report.OriginalRegion = ns.GetActiveRegion()
+	report.DrillRegion = in.Region
+	if report.DrillRegion == "" {
+		for _, region := range ns.GetSpec().GetRegions() {
+			if region != report.OriginalRegion {
+				report.DrillRegion = region
+				break
+			}
+		}
+	}
+	if report.OriginalRegion == "" || report.DrillRegion == "" {
+		return fail(temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("namespace %s is not a multi-region namespace", in.Namespace), "InvalidInput", nil))
+	}
+	if check := preflight.CheckFailover(ns, report.DrillRegion); !check.Valid {
+		return fail(temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("namespace %s can't fail over: %s", in.Namespace, check.FirstError()), "InvalidInput", nil))
+	}
+
+	submitted, drillErr := w.failoverDrillStep(ctx, report, "failover", report.OriginalRegion, report.DrillRegion, in.OperationTimeout)
+	if drillErr != nil && !submitted {
+		return fail(drillErr)
+	}
+
+	if drillErr == nil {
+		// An abort that came in during the failover skips the soak
+		if abortCh.ReceiveAsync(&report.AbortReason) {
+			report.Aborted = true
+		} else {
+			report.Phase = FailoverDrillPhaseSoaking
+			soakStart := workflow.Now(ctx)
+			timerCtx, cancelTimer := workflow.WithCancel(ctx)
+			selector := workflow.NewSelector(ctx)
+			selector.AddFuture(workflow.NewTimer(timerCtx, in.Soak), func(workflow.Future) {})
+			selector.AddReceive(abortCh, receiveAbort)
+			selector.Select(ctx)
+			cancelTimer()
+			report.Soak = workflow.Now(ctx).Sub(soakStart)
+		}
+	}
+
+	// Fail back on a context the drill's cancellation doesn't reach, so a cancelled drill still restores the namespace
+	failbackCtx := ctx
+	if errors.Is(ctx.Err(), workflow.ErrCanceled) {
+		failbackCtx, _ = workflow.NewDisconnectedContext(ctx)
+		report.Aborted = true
+		report.AbortReason = "drill cancelled"
+	}
+	if drillErr != nil {
+		// The failover was submitted but the drill stopped waiting for it, so it may still move the namespace.
+		// Wait for it to end, however the drill ends, and only skip the failback if it never applied.
+		waitCtx, _ := workflow.NewDisconnectedContext(ctx)
+		if !w.failoverDrillStepApplied(waitCtx, report.Steps[0]) {
+			return fail(drillErr)
+		}
+	}
+	report.Phase = FailoverDrillPhaseFailingBack
+	if _, err := w.failoverDrillStep(failbackCtx, report, "failback", report.DrillRegion, report.OriginalRegion, in.OperationTimeout); err != nil {
+		return fail(fmt.Errorf("failback to %s failed, namespace %s is still active in %s: %w",
+			report.OriginalRegion, in.Namespace, report.DrillRegion, err))
+	}
+	if drillErr != nil {
+		return fail(fmt.Errorf("failover to %s failed, namespace %s was failed back to %s: %w",
+			report.DrillRegion, in.Namespace, report.OriginalRegion, drillErr))
+	}
+
+	report.Phase = FailoverDrillPhaseCompleted
+	report.EndTime = workflow.Now(ctx)
+	return report, nil
+}
+
+// failoverDrillStep fails the namespace over to the region, waits for the async operation, and records the step's
+// timing. submitted reports whether the failover may have reached the Cloud API, which is the case once the request
+// succeeded or was cancelled in flight.
+func (w *workflows) failoverDrillStep(ctx workflow.Context, report *FailoverDrillReport, name, from, to string, timeout time.Duration) (submitted bool, err error) {
+	step := &FailoverDrillStep{
+		Name:       name,
+		FromRegion: from,
+		ToRegion:   to,
+		StartTime:  workflow.Now(ctx),
+	}
+	report.Steps = append(report.Steps, step)
+
+	// The operation ID is fixed per run and step, so an activity retry after a lost response resubmits the same
+	// operation rather than starting a second failover
+	step.AsyncOperationID = fmt.Sprintf("%s-%s", workflow.GetInfo(ctx).WorkflowExecution.RunID, name)
+	resp, err := w.FailoverNamespaceRegion(ctx, &cloudservice.FailoverNamespaceRegionRequest{
+		Namespace:        report.Namespace,
+		Region:           to,
+		AsyncOperationId: step.AsyncOperationID,
+	})
+	if err != nil {
+		return errors.Is(ctx.Err(), workflow.ErrCanceled), err
+	}
+	if id := resp.GetAsyncOperation().GetId(); id != "" {
+		step.AsyncOperationID = id
+		if _, err := w.WaitForAsyncOperation(ctx, &WaitForAsyncOperationInput{
+			AsyncOperationID: step.AsyncOperationID,
+			Timeout:          timeout,
+		}); err != nil {
+			return true, err
+		}
+	}
+	step.EndTime = workflow.Now(ctx)
+	step.Duration = step.EndTime.Sub(step.StartTime)
+	return true, nil
+}
+
+// failoverDrillStepApplied polls the step's async operation until it reaches a final state, and reports whether it
+// may have moved the namespace: only a failed or cancelled operation, or one the Cloud API never received, didn't.
+func (w *workflows) failoverDrillStepApplied(ctx workflow.Context, step *FailoverDrillStep) bool {
+	for {
+		resp, err := w.GetAsyncOperation(ctx, &cloudservice.GetAsyncOperationRequest{AsyncOperationId: step.AsyncOperationID})
+		if err != nil {
+			return apierrors.Translate(err, "").Category != apierrors.CategoryNotFound
+		}
+		switch resp.GetAsyncOperation().GetState() {
+		case operation.AsyncOperation_STATE_FAILED, operation.AsyncOperation_STATE_CANCELLED:
+			return false
+		case operation.AsyncOperation_STATE_FULFILLED:
+			return true
+		}
+		interval := resp.GetAsyncOperation().GetCheckDuration().AsDuration()
+		if interval <= 0 {
+			interval = failoverDrillPollInterval
+		}
+		if err := workflow.Sleep(ctx, interval); err != nil {
+			return true
+		}
+	}
+}
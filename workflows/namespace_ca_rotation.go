@@ -0,0 +1,151 @@
+package workflows
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+	"google.golang.org/protobuf/proto"
+
+	"bechols/temcp/internal/validator"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+	"go.temporal.io/cloud-sdk/api/namespace/v1"
+)
+
+const (
+	// namespace CA rotation workflow types
+	RotateNamespaceCAWorkflowType = workflowPrefix + "rotate-namespace-ca"
+
+	// NamespaceCARotationConfirmSignal ends the grace period early, once every client uses a certificate the
+	// new CA issued. The signal's payload is an optional note recorded in the report. Confirmations sent before
+	// the grace period starts are ignored.
+	NamespaceCARotationConfirmSignal = "confirm"
+)
+
+type (
+	RotateNamespaceCAInput struct {
+		Namespace string `required:"true" json:"namespace"`
+		// NewCA is the PEM bundle of the CA to rotate to
+		NewCA []byte `required:"true" json:"new_ca"`
+		// RemoveFingerprints are the SHA-256 fingerprints of the certificates to remove after the grace period.
+		// If empty, every certificate that isn't part of the new CA is removed.
+		RemoveFingerprints []string `json:"remove_fingerprints"`
+		// AppendOnly adds the new CA and keeps every certificate the namespace accepts, without a grace period.
+		// RemoveFingerprints is ignored.
+		AppendOnly bool `json:"append_only"`
+		// GracePeriod is how long both CAs are accepted unless a confirmation comes in first,
+		// DefaultCARotationOverlap by default
+		GracePeriod time.Duration `json:"grace_period"`
+		// OperationTimeout bounds the wait for each namespace update, namespaceUpdateTimeout by default
+		OperationTimeout time.Duration `json:"operation_timeout"`
+	}
+
+	NamespaceCARotationWorkflows interface {
+		// Namespace CA Rotation Workflows
+		RotateNamespaceCA(ctx workflow.Context, in *RotateNamespaceCAInput) (*CARotationReport, error)
+	}
+)
+
+func registerNamespaceCARotationWorkflows(w worker.Worker, wf NamespaceCARotationWorkflows) {
+	for k, v := range map[string]any{
+		RotateNamespaceCAWorkflowType: wf.RotateNamespaceCA,
+	} {
+		w.RegisterWorkflowWithOptions(v, workflow.RegisterOptions{Name: k})
+	}
+}
+
+// Rotate the CA that an mTLS namespace accepts client certificates from: append the new CA, wait for the
+// confirm signal or the grace period so workers can switch to certificates it issued, then remove the old CA.
+// Cancelling the rotation before the old CA is removed leaves both CAs in place.
+func (w *workflows) RotateNamespaceCA(ctx workflow.Context, in *RotateNamespaceCAInput) (*CARotationReport, error) {
+	if err := validator.ValidateStruct(in); err != nil {
+		return nil, fmt.Errorf("invalid input: %s", err)
+	}
+	if in.Namespace == "" || len(in.NewCA) == 0 {
+		return nil, temporal.NewNonRetryableApplicationError("namespace and new CA are required", "InvalidInput", nil)
+	}
+	if in.GracePeriod <= 0 {
+		in.GracePeriod = DefaultCARotationOverlap
+	}
+	if in.OperationTimeout <= 0 {
+		in.OperationTimeout = namespaceUpdateTimeout
+	}
+
+	report := newCARotationReport(ctx, in.Namespace, in.GracePeriod)
+	if err := workflow.SetQueryHandler(ctx, CARotationReportQuery, func() (*CARotationReport, error) {
+		return report, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// spec is the namespace spec last read, which the update writes back with the new bundle
+	var spec *namespace.NamespaceSpec
+	target := caBundleTarget{
+		get: func(ctx workflow.Context) ([]byte, string, error) {
+			resp, err := w.GetNamespace(ctx, &cloudservice.GetNamespaceRequest{Namespace: in.Namespace})
+			if err != nil {
+				return nil, "", err
+			}
+			ns := resp.GetNamespace()
+			spec = ns.GetSpec()
+			mtls := spec.GetMtlsAuth()
+			bundle := mtls.GetAcceptedClientCa()
+			if len(bundle) == 0 && mtls.GetAcceptedClientCaDeprecated() != "" {
+				if bundle, err = base64.StdEncoding.DecodeString(mtls.GetAcceptedClientCaDeprecated()); err != nil {
+					return nil, "", temporal.NewNonRetryableApplicationError(
+						fmt.Sprintf("the CA bundle of namespace %s isn't valid base64: %s", in.Namespace, err), "InvalidInput", nil)
+				}
+			}
+			if !mtls.GetEnabled() && len(bundle) == 0 {
+				return nil, "", temporal.NewNonRetryableApplicationError(
+					fmt.Sprintf("namespace %s doesn't use mTLS auth, there is no CA to rotate", in.Namespace), "InvalidInput", nil)
+			}
+			return bundle, ns.GetResourceVersion(), nil
+		},
+		put: func(ctx workflow.Context, bundle []byte, resourceVersion string) (string, error) {
+			updated := proto.Clone(spec).(*namespace.NamespaceSpec)
+			if updated.MtlsAuth == nil {
+				updated.MtlsAuth = &namespace.MtlsAuthSpec{}
+			}
+			updated.MtlsAuth.AcceptedClientCa = bundle
+			updated.MtlsAuth.AcceptedClientCaDeprecated = ""
+			resp, err := w.UpdateNamespace(ctx, &cloudservice.UpdateNamespaceRequest{
+				Namespace:       in.Namespace,
+				Spec:            updated,
+				ResourceVersion: resourceVersion,
+			})
+			if err != nil {
+				return "", err
+			}
+			return resp.GetAsyncOperation().GetId(), nil
+		},
+	}
+
+	confirmCh := workflow.GetSignalChannel(ctx, NamespaceCARotationConfirmSignal)
+	grace := func(ctx workflow.Context) {
+		// Confirmations sent while the new CA was being added can't vouch for clients using it, so they are dropped
+		// and only ones sent during the grace period count
+		var early string
+		for confirmCh.ReceiveAsync(&early) {
+		}
+		timerCtx, cancelTimer := workflow.WithCancel(ctx)
+		selector := workflow.NewSelector(ctx)
+		selector.AddFuture(workflow.NewTimer(timerCtx, in.GracePeriod), func(workflow.Future) {})
+		selector.AddReceive(confirmCh, func(c workflow.ReceiveChannel, more bool) {
+			c.Receive(ctx, &report.ConfirmNote)
+			report.Confirmed = true
+		})
+		selector.Select(ctx)
+		cancelTimer()
+	}
+	if err := w.rotateCA(ctx, report, target, in.NewCA, in.RemoveFingerprints, in.AppendOnly, grace, in.OperationTimeout); err != nil {
+		report.Phase = CARotationPhaseFailed
+		report.Error = err.Error()
+		report.EndTime = workflow.Now(ctx)
+		return report, err
+	}
+	return report, nil
+}
@@ -0,0 +1,50 @@
+package workflows
+
+import (
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+
+	"bechols/temcp/workflows/activities"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+)
+
+const (
+	// namespace region workflow types
+	AddNamespaceRegionWorkflowType      = workflowPrefix + "add-namespace-region"
+	DeleteNamespaceRegionWorkflowType   = workflowPrefix + "delete-namespace-region"
+	FailoverNamespaceRegionWorkflowType = workflowPrefix + "failover-namespace-region"
+)
+
+type (
+	NamespaceRegionWorkflows interface {
+		// Namespace Region (High Availability) Workflows
+		AddNamespaceRegion(ctx workflow.Context, in *cloudservice.AddNamespaceRegionRequest) (*cloudservice.AddNamespaceRegionResponse, error)
+		DeleteNamespaceRegion(ctx workflow.Context, in *cloudservice.DeleteNamespaceRegionRequest) (*cloudservice.DeleteNamespaceRegionResponse, error)
+		FailoverNamespaceRegion(ctx workflow.Context, in *cloudservice.FailoverNamespaceRegionRequest) (*cloudservice.FailoverNamespaceRegionResponse, error)
+	}
+)
+
+func registerNamespaceRegionWorkflows(w worker.Worker, wf NamespaceRegionWorkflows) {
+	for k, v := range map[string]any{
+		AddNamespaceRegionWorkflowType:      wf.AddNamespaceRegion,
+		DeleteNamespaceRegionWorkflowType:   wf.DeleteNamespaceRegion,
+		FailoverNamespaceRegionWorkflowType: wf.FailoverNamespaceRegion,
+	} {
+		w.RegisterWorkflowWithOptions(v, workflow.RegisterOptions{Name: k})
+	}
+}
+
+// Add a standby region to a namespace
+func (w *workflows) AddNamespaceRegion(ctx workflow.Context, in *cloudservice.AddNamespaceRegionRequest) (*cloudservice.AddNamespaceRegionResponse, error) {
+	return activities.AddNamespaceRegion(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Remove a standby region from a namespace
+func (w *workflows) DeleteNamespaceRegion(ctx workflow.Context, in *cloudservice.DeleteNamespaceRegionRequest) (*cloudservice.DeleteNamespaceRegionResponse, error) {
+	return activities.DeleteNamespaceRegion(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Fail a namespace over to another of its regions
+func (w *workflows) FailoverNamespaceRegion(ctx workflow.Context, in *cloudservice.FailoverNamespaceRegionRequest) (*cloudservice.FailoverNamespaceRegionResponse, error) {
+	return activities.FailoverNamespaceRegion(withInfiniteRetryActivityOptions(ctx), in)
+}
@@ -0,0 +1,185 @@
+package workflows
+
+import (
+	"fmt"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+
+	"bechols/temcp/internal/nexusendpoint"
+	"bechols/temcp/internal/preflight"
+	"bechols/temcp/internal/validator"
+	"bechols/temcp/workflows/activities"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+	"go.temporal.io/cloud-sdk/api/nexus/v1"
+)
+
+const (
+	// nexus endpoint workflow types
+	GetNexusEndpointWorkflowType       = workflowPrefix + "get-nexus-endpoint"
+	GetNexusEndpointsWorkflowType      = workflowPrefix + "get-nexus-endpoints"
+	CreateNexusEndpointWorkflowType    = workflowPrefix + "create-nexus-endpoint"
+	UpdateNexusEndpointWorkflowType    = workflowPrefix + "update-nexus-endpoint"
+	DeleteNexusEndpointWorkflowType    = workflowPrefix + "delete-nexus-endpoint"
+	ReconcileNexusEndpointWorkflowType = workflowPrefix + "reconcile-nexus-endpoint"
+)
+
+type (
+	ReconcileNexusEndpointInput struct {
+		Endpoint nexusendpoint.Config `required:"true" json:"endpoint"`
+	}
+	ReconcileNexusEndpointOutput struct {
+		EndpointID      string               `json:"endpoint_id"`
+		ResourceVersion string               `json:"resource_version"`
+		Endpoint        nexusendpoint.Config `json:"endpoint"`
+		Outcome         ReconcileOutcome     `json:"outcome"`
+		Checks          *preflight.Report    `json:"checks"`
+	}
+
+	// NexusEndpointWorkflows takes and returns Cloud API messages, except for reconcile: endpoint specs
+	// hold oneofs, which only survive the trip through a workflow inside a proto message.
+	NexusEndpointWorkflows interface {
+		// Nexus Endpoint Workflows
+		GetNexusEndpoint(ctx workflow.Context, in *cloudservice.GetNexusEndpointRequest) (*cloudservice.GetNexusEndpointResponse, error)
+		GetNexusEndpoints(ctx workflow.Context, in *cloudservice.GetNexusEndpointsRequest) (*cloudservice.GetNexusEndpointsResponse, error)
+		CreateNexusEndpoint(ctx workflow.Context, in *cloudservice.CreateNexusEndpointRequest) (*cloudservice.CreateNexusEndpointResponse, error)
+		UpdateNexusEndpoint(ctx workflow.Context, in *cloudservice.UpdateNexusEndpointRequest) (*cloudservice.UpdateNexusEndpointResponse, error)
+		DeleteNexusEndpoint(ctx workflow.Context, in *cloudservice.DeleteNexusEndpointRequest) (*cloudservice.DeleteNexusEndpointResponse, error)
+		ReconcileNexusEndpoint(ctx workflow.Context, in *ReconcileNexusEndpointInput) (*ReconcileNexusEndpointOutput, error)
+	}
+)
+
+func registerNexusEndpointWorkflows(w worker.Worker, wf NexusEndpointWorkflows) {
+	for k, v := range map[string]any{
+		GetNexusEndpointWorkflowType:       wf.GetNexusEndpoint,
+		GetNexusEndpointsWorkflowType:      wf.GetNexusEndpoints,
+		CreateNexusEndpointWorkflowType:    wf.CreateNexusEndpoint,
+		UpdateNexusEndpointWorkflowType:    wf.UpdateNexusEndpoint,
+		DeleteNexusEndpointWorkflowType:    wf.DeleteNexusEndpoint,
+		ReconcileNexusEndpointWorkflowType: wf.ReconcileNexusEndpoint,
+	} {
+		w.RegisterWorkflowWithOptions(v, workflow.RegisterOptions{Name: k})
+	}
+}
+
+// Get a nexus endpoint
+func (w *workflows) GetNexusEndpoint(ctx workflow.Context, in *cloudservice.GetNexusEndpointRequest) (*cloudservice.GetNexusEndpointResponse, error) {
+	return activities.GetNexusEndpoint(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Get multiple nexus endpoints
+func (w *workflows) GetNexusEndpoints(ctx workflow.Context, in *cloudservice.GetNexusEndpointsRequest) (*cloudservice.GetNexusEndpointsResponse, error) {
+	return activities.GetNexusEndpoints(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Create a nexus endpoint
+func (w *workflows) CreateNexusEndpoint(ctx workflow.Context, in *cloudservice.CreateNexusEndpointRequest) (*cloudservice.CreateNexusEndpointResponse, error) {
+	return activities.CreateNexusEndpoint(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Update a nexus endpoint
+func (w *workflows) UpdateNexusEndpoint(ctx workflow.Context, in *cloudservice.UpdateNexusEndpointRequest) (*cloudservice.UpdateNexusEndpointResponse, error) {
+	return activities.UpdateNexusEndpoint(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Delete a nexus endpoint
+func (w *workflows) DeleteNexusEndpoint(ctx workflow.Context, in *cloudservice.DeleteNexusEndpointRequest) (*cloudservice.DeleteNexusEndpointResponse, error) {
+	return activities.DeleteNexusEndpoint(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// getNexusEndpointWithName returns the endpoint with the name, or nil if there is none
+func (w *workflows) getNexusEndpointWithName(ctx workflow.Context, name string) (*nexus.Endpoint, error) {
+	resp, err := w.GetNexusEndpoints(ctx, &cloudservice.GetNexusEndpointsRequest{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	for _, endpoint := range resp.Endpoints {
+		if endpoint.GetSpec().GetName() == name {
+			return endpoint, nil
+		}
+	}
+	return nil, nil
+}
+
+// Reconcile a nexus endpoint by name: create the endpoint if none has the name, or update it if its spec differs.
+// The target and caller namespaces must exist in the account.
+func (w *workflows) ReconcileNexusEndpoint(ctx workflow.Context, in *ReconcileNexusEndpointInput) (*ReconcileNexusEndpointOutput, error) {
+	if err := validator.ValidateStruct(in); err != nil {
+		return nil, fmt.Errorf("invalid input: %s", err)
+	}
+	spec, err := in.Endpoint.Spec()
+	if err != nil {
+		return nil, temporal.NewNonRetryableApplicationError(err.Error(), "InvalidInput", nil)
+	}
+	namespaces, err := w.GetAllNamespaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	namespaceIDs := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		namespaceIDs = append(namespaceIDs, ns.Namespace)
+	}
+	out := &ReconcileNexusEndpointOutput{Checks: preflight.CheckNexusEndpoint(spec, namespaceIDs)}
+	if !out.Checks.Valid {
+		return nil, temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("invalid nexus endpoint %s: %s", in.Endpoint.Name, out.Checks.FirstError()), "InvalidInput", nil)
+	}
+
+	existing, err := w.getNexusEndpointWithName(ctx, in.Endpoint.Name)
+	if err != nil {
+		return nil, err
+	}
+	// The operation IDs are fixed per run, so an activity retry after a lost response resubmits the same operation
+	// rather than creating a second endpoint
+	runID := workflow.GetInfo(ctx).WorkflowExecution.RunID
+	var asyncOpID string
+	switch {
+	case existing == nil:
+		createResp, err := w.CreateNexusEndpoint(ctx, &cloudservice.CreateNexusEndpointRequest{
+			Spec:             spec,
+			AsyncOperationId: runID + "-create",
+		})
+		if err != nil {
+			return nil, err
+		}
+		out.EndpointID = createResp.EndpointId
+		asyncOpID = createResp.GetAsyncOperation().GetId()
+		out.Outcome = ReconcileOutcomeCreated
+	case !nexusendpoint.FromSpec(existing.Spec).Equal(in.Endpoint):
+		updateResp, err := w.UpdateNexusEndpoint(ctx, &cloudservice.UpdateNexusEndpointRequest{
+			EndpointId:       existing.Id,
+			Spec:             spec,
+			ResourceVersion:  existing.ResourceVersion,
+			AsyncOperationId: runID + "-update",
+		})
+		if err != nil {
+			return nil, err
+		}
+		out.EndpointID = existing.Id
+		asyncOpID = updateResp.GetAsyncOperation().GetId()
+		out.Outcome = ReconcileOutcomeUpdated
+	default:
+		out.EndpointID = existing.Id
+		out.ResourceVersion = existing.ResourceVersion
+		out.Endpoint = nexusendpoint.FromSpec(existing.Spec)
+		out.Outcome = ReconcileOutcomeUnchanged
+		return out, nil
+	}
+
+	if asyncOpID != "" {
+		if _, err := w.WaitForAsyncOperation(ctx, &WaitForAsyncOperationInput{
+			AsyncOperationID: asyncOpID,
+			Timeout:          namespaceUpdateTimeout,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	getResp, err := w.GetNexusEndpoint(ctx, &cloudservice.GetNexusEndpointRequest{EndpointId: out.EndpointID})
+	if err != nil {
+		return nil, err
+	}
+	out.ResourceVersion = getResp.GetEndpoint().GetResourceVersion()
+	out.Endpoint = nexusendpoint.FromSpec(getResp.GetEndpoint().GetSpec())
+	return out, nil
+}
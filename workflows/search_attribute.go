@@ -0,0 +1,143 @@
+package workflows
+
+import (
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+	"google.golang.org/protobuf/proto"
+
+	"bechols/temcp/internal/apierrors"
+	"bechols/temcp/internal/preflight"
+	"bechols/temcp/internal/validator"
+	"bechols/temcp/workflows/activities"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+	"go.temporal.io/cloud-sdk/api/namespace/v1"
+)
+
+const (
+	// search attribute workflow types
+	AddNamespaceSearchAttributeWorkflowType = workflowPrefix + "add-namespace-search-attribute"
+	RenameCustomSearchAttributeWorkflowType = workflowPrefix + "rename-custom-search-attribute"
+
+	// addSearchAttributeAttempts is how many times the spec is re-read after a resource version conflict
+	addSearchAttributeAttempts = 3
+)
+
+type (
+	AddNamespaceSearchAttributeInput struct {
+		Namespace string                                      `required:"true" json:"namespace"`
+		Name      string                                      `required:"true" json:"name"`
+		Type      namespace.NamespaceSpec_SearchAttributeType `required:"true" json:"type"`
+	}
+	AddNamespaceSearchAttributeOutput struct {
+		Namespace        *namespace.Namespace `json:"namespace"`
+		AsyncOperationID string               `json:"async_operation_id"`
+		Attempts         int                  `json:"attempts"`
+	}
+
+	SearchAttributeWorkflows interface {
+		// Search Attribute Workflows
+		AddNamespaceSearchAttribute(ctx workflow.Context, in *AddNamespaceSearchAttributeInput) (*AddNamespaceSearchAttributeOutput, error)
+		RenameCustomSearchAttribute(ctx workflow.Context, in *cloudservice.RenameCustomSearchAttributeRequest) (*cloudservice.RenameCustomSearchAttributeResponse, error)
+	}
+)
+
+func registerSearchAttributeWorkflows(w worker.Worker, wf SearchAttributeWorkflows) {
+	for k, v := range map[string]any{
+		AddNamespaceSearchAttributeWorkflowType: wf.AddNamespaceSearchAttribute,
+		RenameCustomSearchAttributeWorkflowType: wf.RenameCustomSearchAttribute,
+	} {
+		w.RegisterWorkflowWithOptions(v, workflow.RegisterOptions{Name: k})
+	}
+}
+
+// AddSearchAttributeToSpec returns a copy of the spec with the search attribute added, after checking the
+// name and type against the spec. The tools use it too when no Temporal client is available.
+func AddSearchAttributeToSpec(spec *namespace.NamespaceSpec, name string, saType namespace.NamespaceSpec_SearchAttributeType) (*namespace.NamespaceSpec, *preflight.Report) {
+	report := preflight.CheckAddSearchAttribute(spec, name, saType)
+	if !report.Valid {
+		return nil, report
+	}
+	updated := proto.Clone(spec).(*namespace.NamespaceSpec)
+	if updated.SearchAttributes == nil {
+		updated.SearchAttributes = map[string]namespace.NamespaceSpec_SearchAttributeType{}
+	}
+	updated.SearchAttributes[name] = saType
+	return updated, report
+}
+
+// HasSearchAttribute reports whether the spec already has the search attribute with the type, which makes adding
+// it a no-op
+func HasSearchAttribute(spec *namespace.NamespaceSpec, name string, saType namespace.NamespaceSpec_SearchAttributeType) bool {
+	existing, ok := spec.GetSearchAttributes()[name]
+	return ok && existing == saType
+}
+
+// Add a custom search attribute to a namespace by reading its spec, adding the attribute and writing the spec
+// back with the read resource version. The spec is re-read if the namespace changed in between. An attribute
+// that already exists with the same type is left as it is.
+func (w *workflows) AddNamespaceSearchAttribute(ctx workflow.Context, in *AddNamespaceSearchAttributeInput) (*AddNamespaceSearchAttributeOutput, error) {
+	if err := validator.ValidateStruct(in); err != nil {
+		return nil, fmt.Errorf("invalid input: %s", err)
+	}
+	out := &AddNamespaceSearchAttributeOutput{}
+	for {
+		out.Attempts++
+		getResp, err := w.GetNamespace(ctx, &cloudservice.GetNamespaceRequest{Namespace: in.Namespace})
+		if err != nil {
+			return nil, err
+		}
+		ns := getResp.Namespace
+		if HasSearchAttribute(ns.GetSpec(), in.Name, in.Type) {
+			out.Namespace = ns
+			return out, nil
+		}
+		spec, report := AddSearchAttributeToSpec(ns.GetSpec(), in.Name, in.Type)
+		if !report.Valid {
+			return nil, temporal.NewNonRetryableApplicationError(
+				fmt.Sprintf("can't add search attribute %s: %s", in.Name, report.FirstError()), "InvalidInput", nil)
+		}
+
+		// The operation ID is fixed per run and attempt, so an activity retry after a lost response resubmits the
+		// same update
+		updateResp, err := w.UpdateNamespace(ctx, &cloudservice.UpdateNamespaceRequest{
+			Namespace:        ns.Namespace,
+			Spec:             spec,
+			ResourceVersion:  ns.ResourceVersion,
+			AsyncOperationId: fmt.Sprintf("%s-add-search-attribute-%d", workflow.GetInfo(ctx).WorkflowExecution.RunID, out.Attempts),
+		})
+		if apierrors.IsConflict(err) && out.Attempts < addSearchAttributeAttempts {
+			if err := workflow.Sleep(ctx, time.Duration(out.Attempts)*2*time.Second); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		out.AsyncOperationID = updateResp.GetAsyncOperation().GetId()
+		if out.AsyncOperationID != "" {
+			if _, err := w.WaitForAsyncOperation(ctx, &WaitForAsyncOperationInput{
+				AsyncOperationID: out.AsyncOperationID,
+				Timeout:          namespaceUpdateTimeout,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		getResp, err = w.GetNamespace(ctx, &cloudservice.GetNamespaceRequest{Namespace: in.Namespace})
+		if err != nil {
+			return nil, err
+		}
+		out.Namespace = getResp.Namespace
+		return out, nil
+	}
+}
+
+// Rename a custom search attribute of a namespace
+func (w *workflows) RenameCustomSearchAttribute(ctx workflow.Context, in *cloudservice.RenameCustomSearchAttributeRequest) (*cloudservice.RenameCustomSearchAttributeResponse, error) {
+	return activities.RenameCustomSearchAttribute(withInfiniteRetryActivityOptions(ctx), in)
+}
@@ -0,0 +1,286 @@
+package workflows
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+	"google.golang.org/protobuf/proto"
+
+	"bechols/temcp/internal/validator"
+	"bechols/temcp/workflows/activities"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+	"go.temporal.io/cloud-sdk/api/identity/v1"
+)
+
+const (
+	serviceAccountUpdateTimeout = 10 * time.Minute
+
+	// service account management workflow types
+	GetServiceAccountWorkflowType        = workflowPrefix + "get-service-account"
+	GetServiceAccountsWorkflowType       = workflowPrefix + "get-service-accounts"
+	GetAllServiceAccountsWorkflowType    = workflowPrefix + "get-all-service-accounts"
+	CreateServiceAccountWorkflowType     = workflowPrefix + "create-service-account"
+	UpdateServiceAccountWorkflowType     = workflowPrefix + "update-service-account"
+	DeleteServiceAccountWorkflowType     = workflowPrefix + "delete-service-account"
+	ReconcileServiceAccountWorkflowType  = workflowPrefix + "reconcile-service-account"
+	ReconcileServiceAccountsWorkflowType = workflowPrefix + "reconcile-service-accounts"
+)
+
+type (
+	ReconcileServiceAccountInput struct {
+		Spec *identity.ServiceAccountSpec `required:"true" json:"spec"`
+	}
+	ReconcileServiceAccountOutput struct {
+		ServiceAccount *identity.ServiceAccount `json:"service_account"`
+		Outcome        ReconcileOutcome         `json:"outcome"`
+		Error          string                   `json:"error"`
+	}
+
+	ReconcileServiceAccountsInput struct {
+		Specs             []*identity.ServiceAccountSpec `required:"true" json:"specs"`
+		DeleteUnaccounted bool                           `json:"delete_unaccounted"`
+	}
+	ReconcileServiceAccountsOutput struct {
+		Results []*ReconcileServiceAccountOutput `json:"results"`
+	}
+
+	ServiceAccountWorkflows interface {
+		// Service Account Management Workflows
+		GetServiceAccount(ctx workflow.Context, in *cloudservice.GetServiceAccountRequest) (*cloudservice.GetServiceAccountResponse, error)
+		GetServiceAccounts(ctx workflow.Context, in *cloudservice.GetServiceAccountsRequest) (*cloudservice.GetServiceAccountsResponse, error)
+		GetAllServiceAccounts(ctx workflow.Context) ([]*identity.ServiceAccount, error)
+		CreateServiceAccount(ctx workflow.Context, in *cloudservice.CreateServiceAccountRequest) (*cloudservice.CreateServiceAccountResponse, error)
+		UpdateServiceAccount(ctx workflow.Context, in *cloudservice.UpdateServiceAccountRequest) (*cloudservice.UpdateServiceAccountResponse, error)
+		DeleteServiceAccount(ctx workflow.Context, in *cloudservice.DeleteServiceAccountRequest) (*cloudservice.DeleteServiceAccountResponse, error)
+		ReconcileServiceAccount(ctx workflow.Context, in *ReconcileServiceAccountInput) (*ReconcileServiceAccountOutput, error)
+		ReconcileServiceAccounts(ctx workflow.Context, in *ReconcileServiceAccountsInput) (*ReconcileServiceAccountsOutput, error)
+	}
+)
+
+func (o *ReconcileServiceAccountOutput) setError(err error) {
+	var applicationErr *temporal.ApplicationError
+	if errors.As(err, &applicationErr) {
+		o.Error = applicationErr.Error()
+		o.Outcome = ReconcileOutcomeError
+	}
+}
+
+func registerServiceAccountWorkflows(w worker.Worker, wf ServiceAccountWorkflows) {
+	for k, v := range map[string]any{
+		GetServiceAccountWorkflowType:        wf.GetServiceAccount,
+		GetServiceAccountsWorkflowType:       wf.GetServiceAccounts,
+		GetAllServiceAccountsWorkflowType:    wf.GetAllServiceAccounts,
+		CreateServiceAccountWorkflowType:     wf.CreateServiceAccount,
+		UpdateServiceAccountWorkflowType:     wf.UpdateServiceAccount,
+		DeleteServiceAccountWorkflowType:     wf.DeleteServiceAccount,
+		ReconcileServiceAccountWorkflowType:  wf.ReconcileServiceAccount,
+		ReconcileServiceAccountsWorkflowType: wf.ReconcileServiceAccounts,
+	} {
+		w.RegisterWorkflowWithOptions(v, workflow.RegisterOptions{Name: k})
+	}
+}
+
+// Get a service account
+func (w *workflows) GetServiceAccount(ctx workflow.Context, in *cloudservice.GetServiceAccountRequest) (*cloudservice.GetServiceAccountResponse, error) {
+	return activities.GetServiceAccount(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Get multiple service accounts
+func (w *workflows) GetServiceAccounts(ctx workflow.Context, in *cloudservice.GetServiceAccountsRequest) (*cloudservice.GetServiceAccountsResponse, error) {
+	return activities.GetServiceAccounts(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Get all known service accounts
+func (w *workflows) GetAllServiceAccounts(ctx workflow.Context) ([]*identity.ServiceAccount, error) {
+	var (
+		serviceAccounts = make([]*identity.ServiceAccount, 0)
+		pageToken       = ""
+	)
+	for {
+		resp, err := w.GetServiceAccounts(ctx, &cloudservice.GetServiceAccountsRequest{
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		serviceAccounts = append(serviceAccounts, resp.ServiceAccount...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	return serviceAccounts, nil
+}
+
+// Create a service account
+func (w *workflows) CreateServiceAccount(ctx workflow.Context, in *cloudservice.CreateServiceAccountRequest) (*cloudservice.CreateServiceAccountResponse, error) {
+	return activities.CreateServiceAccount(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Update a service account
+func (w *workflows) UpdateServiceAccount(ctx workflow.Context, in *cloudservice.UpdateServiceAccountRequest) (*cloudservice.UpdateServiceAccountResponse, error) {
+	return activities.UpdateServiceAccount(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Delete a service account
+func (w *workflows) DeleteServiceAccount(ctx workflow.Context, in *cloudservice.DeleteServiceAccountRequest) (*cloudservice.DeleteServiceAccountResponse, error) {
+	return activities.DeleteServiceAccount(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Get the service account with name, service account names are expected to be unique in an account
+func (w *workflows) getServiceAccountWithName(ctx workflow.Context, name string) (*identity.ServiceAccount, error) {
+	serviceAccounts, err := w.GetAllServiceAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var found *identity.ServiceAccount
+	for _, sa := range serviceAccounts {
+		if sa.GetSpec().GetName() != name {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("multiple service accounts found with name %s", name)
+		}
+		found = sa
+	}
+	return found, nil
+}
+
+func (w *workflows) reconcileServiceAccount(ctx workflow.Context, spec *identity.ServiceAccountSpec, serviceAccount *identity.ServiceAccount) (*ReconcileServiceAccountOutput, error) {
+	var (
+		serviceAccountID string
+		asyncOpID        string
+		out              = &ReconcileServiceAccountOutput{}
+		err              error
+	)
+	defer func() {
+		if err != nil {
+			out.setError(err)
+		}
+		if serviceAccount != nil {
+			out.ServiceAccount = serviceAccount
+		} else if spec != nil {
+			out.ServiceAccount = &identity.ServiceAccount{
+				Id:   serviceAccountID,
+				Spec: spec,
+			}
+		}
+	}()
+	if serviceAccount == nil {
+		var createResp *cloudservice.CreateServiceAccountResponse
+		// no service account found, create one
+		createResp, err = w.CreateServiceAccount(ctx, &cloudservice.CreateServiceAccountRequest{
+			Spec: spec,
+		})
+		if err != nil {
+			return out, err
+		}
+		serviceAccountID = createResp.ServiceAccountId
+		asyncOpID = createResp.AsyncOperation.Id
+		out.Outcome = ReconcileOutcomeCreated
+
+	} else if !proto.Equal(serviceAccount.Spec, spec) {
+		var updateResp *cloudservice.UpdateServiceAccountResponse
+		// service account found, and specs don't match, update it
+		updateResp, err = w.UpdateServiceAccount(ctx, &cloudservice.UpdateServiceAccountRequest{
+			ServiceAccountId: serviceAccount.Id,
+			Spec:             spec,
+			ResourceVersion:  serviceAccount.ResourceVersion,
+		})
+		if err != nil {
+			return out, err
+		}
+		serviceAccountID = serviceAccount.Id
+		asyncOpID = updateResp.AsyncOperation.Id
+		out.Outcome = ReconcileOutcomeUpdated
+
+	} else {
+		// nothing to change
+		out.Outcome = ReconcileOutcomeUnchanged
+		return out, nil
+	}
+
+	if asyncOpID != "" {
+		// wait for the operation to complete
+		_, err = w.WaitForAsyncOperation(ctx, &WaitForAsyncOperationInput{
+			AsyncOperationID: asyncOpID,
+			Timeout:          serviceAccountUpdateTimeout,
+		})
+		if err != nil {
+			return out, err
+		}
+	}
+	var getResp *cloudservice.GetServiceAccountResponse
+	getResp, err = w.GetServiceAccount(ctx, &cloudservice.GetServiceAccountRequest{
+		ServiceAccountId: serviceAccountID,
+	})
+	if err != nil {
+		return out, err
+	}
+	serviceAccount = getResp.ServiceAccount
+	return out, nil
+}
+
+// Reconcile a service account, create the service account if none has the spec's name, or update the one that does.
+func (w *workflows) ReconcileServiceAccount(ctx workflow.Context, in *ReconcileServiceAccountInput) (*ReconcileServiceAccountOutput, error) {
+	if err := validator.ValidateStruct(in); err != nil {
+		return nil, fmt.Errorf("invalid input: %s", err)
+	}
+	serviceAccount, err := w.getServiceAccountWithName(ctx, in.Spec.Name)
+	if err != nil {
+		return nil, err
+	}
+	out, err := w.reconcileServiceAccount(ctx, in.Spec, serviceAccount)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Reconcile multiple service accounts by name, create missing service accounts, update existing service accounts,
+// and optionally delete unaccounted service accounts.
+func (w *workflows) ReconcileServiceAccounts(ctx workflow.Context, in *ReconcileServiceAccountsInput) (*ReconcileServiceAccountsOutput, error) {
+	if err := validator.ValidateStruct(in); err != nil {
+		return nil, fmt.Errorf("invalid input: %s", err)
+	}
+	serviceAccounts, err := w.GetAllServiceAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := &ReconcileServiceAccountsOutput{}
+	for i := range in.Specs {
+		var serviceAccount *identity.ServiceAccount
+		for j, sa := range serviceAccounts {
+			if sa.GetSpec().GetName() == in.Specs[i].Name {
+				serviceAccount = sa
+				serviceAccounts = append(serviceAccounts[:j], serviceAccounts[j+1:]...) // remove the service account from the list
+				break
+			}
+		}
+		// reconcile the service account
+		o, _ := w.reconcileServiceAccount(ctx, in.Specs[i], serviceAccount)
+		out.Results = append(out.Results, o)
+	}
+	// whats left in the list is only the unaccounted service accounts
+	for _, sa := range serviceAccounts {
+		if in.DeleteUnaccounted {
+			o := &ReconcileServiceAccountOutput{
+				ServiceAccount: sa,
+				Outcome:        ReconcileOutcomeDeleted,
+			}
+			_, err := w.DeleteServiceAccount(ctx, &cloudservice.DeleteServiceAccountRequest{
+				ServiceAccountId: sa.Id,
+				ResourceVersion:  sa.ResourceVersion,
+			})
+			if err != nil {
+				o.setError(err)
+			}
+			out.Results = append(out.Results, o)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,88 @@
+package workflows
+
+import (
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+
+	"bechols/temcp/internal/usage"
+	"bechols/temcp/workflows/activities"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+	usagev1 "go.temporal.io/cloud-sdk/api/usage/v1"
+)
+
+const (
+	// usage workflow types
+	GetUsageWorkflowType    = workflowPrefix + "get-usage"
+	GetAllUsageWorkflowType = workflowPrefix + "get-all-usage"
+	UsageReportWorkflowType = workflowPrefix + "usage-report"
+)
+
+type (
+	UsageReportInput struct {
+		Options usage.Options `json:"options"`
+	}
+
+	UsageWorkflows interface {
+		// Usage Workflows
+		GetUsage(ctx workflow.Context, in *cloudservice.GetUsageRequest) (*cloudservice.GetUsageResponse, error)
+		GetAllUsage(ctx workflow.Context, in *cloudservice.GetUsageRequest) ([]*usagev1.Summary, error)
+		UsageReport(ctx workflow.Context, in *UsageReportInput) (*usage.Report, error)
+	}
+)
+
+func registerUsageWorkflows(w worker.Worker, wf UsageWorkflows) {
+	for k, v := range map[string]any{
+		GetUsageWorkflowType:    wf.GetUsage,
+		GetAllUsageWorkflowType: wf.GetAllUsage,
+		UsageReportWorkflowType: wf.UsageReport,
+	} {
+		w.RegisterWorkflowWithOptions(v, workflow.RegisterOptions{Name: k})
+	}
+}
+
+// Get a page of daily usage summaries
+func (w *workflows) GetUsage(ctx workflow.Context, in *cloudservice.GetUsageRequest) (*cloudservice.GetUsageResponse, error) {
+	return activities.GetUsage(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Get all daily usage summaries of the time range, paging through the usage records
+func (w *workflows) GetAllUsage(ctx workflow.Context, in *cloudservice.GetUsageRequest) ([]*usagev1.Summary, error) {
+	req := &cloudservice.GetUsageRequest{
+		StartTimeInclusive: in.StartTimeInclusive,
+		EndTimeExclusive:   in.EndTimeExclusive,
+		PageSize:           in.PageSize,
+	}
+	var summaries []*usagev1.Summary
+	for {
+		resp, err := w.GetUsage(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, resp.Summaries...)
+		if resp.NextPageToken == "" {
+			return summaries, nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+// Report usage per namespace, day and metric over a period, with totals and, optionally, the change
+// from the previous period
+func (w *workflows) UsageReport(ctx workflow.Context, in *UsageReportInput) (*usage.Report, error) {
+	period := in.Options.Period
+	if !period.End.After(period.Start) {
+		return nil, temporal.NewNonRetryableApplicationError("usage period must end after it starts", "InvalidInput", nil)
+	}
+	current, err := w.GetAllUsage(ctx, usage.Request(period))
+	if err != nil {
+		return nil, err
+	}
+	var previous []*usagev1.Summary
+	if in.Options.ComparePrevious {
+		if previous, err = w.GetAllUsage(ctx, usage.Request(period.Previous())); err != nil {
+			return nil, err
+		}
+	}
+	return usage.Build(in.Options, current, previous), nil
+}
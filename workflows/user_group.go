@@ -0,0 +1,100 @@
+package workflows
+
+import (
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+
+	"bechols/temcp/workflows/activities"
+	"go.temporal.io/cloud-sdk/api/cloudservice/v1"
+)
+
+const (
+	// user group management workflow types
+	GetUserGroupWorkflowType                = workflowPrefix + "get-user-group"
+	GetUserGroupsWorkflowType               = workflowPrefix + "get-user-groups"
+	CreateUserGroupWorkflowType             = workflowPrefix + "create-user-group"
+	UpdateUserGroupWorkflowType             = workflowPrefix + "update-user-group"
+	DeleteUserGroupWorkflowType             = workflowPrefix + "delete-user-group"
+	SetUserGroupNamespaceAccessWorkflowType = workflowPrefix + "set-user-group-namespace-access"
+	AddUserGroupMemberWorkflowType          = workflowPrefix + "add-user-group-member"
+	RemoveUserGroupMemberWorkflowType       = workflowPrefix + "remove-user-group-member"
+	GetUserGroupMembersWorkflowType         = workflowPrefix + "get-user-group-members"
+)
+
+type (
+	// UserGroupWorkflows only takes and returns Cloud API messages. Group specs and member ids are oneofs,
+	// which only survive the trip through a workflow inside a proto message.
+	UserGroupWorkflows interface {
+		// User Group Management Workflows
+		GetUserGroup(ctx workflow.Context, in *cloudservice.GetUserGroupRequest) (*cloudservice.GetUserGroupResponse, error)
+		GetUserGroups(ctx workflow.Context, in *cloudservice.GetUserGroupsRequest) (*cloudservice.GetUserGroupsResponse, error)
+		CreateUserGroup(ctx workflow.Context, in *cloudservice.CreateUserGroupRequest) (*cloudservice.CreateUserGroupResponse, error)
+		UpdateUserGroup(ctx workflow.Context, in *cloudservice.UpdateUserGroupRequest) (*cloudservice.UpdateUserGroupResponse, error)
+		DeleteUserGroup(ctx workflow.Context, in *cloudservice.DeleteUserGroupRequest) (*cloudservice.DeleteUserGroupResponse, error)
+		SetUserGroupNamespaceAccess(ctx workflow.Context, in *cloudservice.SetUserGroupNamespaceAccessRequest) (*cloudservice.SetUserGroupNamespaceAccessResponse, error)
+		AddUserGroupMember(ctx workflow.Context, in *cloudservice.AddUserGroupMemberRequest) (*cloudservice.AddUserGroupMemberResponse, error)
+		RemoveUserGroupMember(ctx workflow.Context, in *cloudservice.RemoveUserGroupMemberRequest) (*cloudservice.RemoveUserGroupMemberResponse, error)
+		GetUserGroupMembers(ctx workflow.Context, in *cloudservice.GetUserGroupMembersRequest) (*cloudservice.GetUserGroupMembersResponse, error)
+	}
+)
+
+func registerUserGroupWorkflows(w worker.Worker, wf UserGroupWorkflows) {
+	for k, v := range map[string]any{
+		GetUserGroupWorkflowType:                wf.GetUserGroup,
+		GetUserGroupsWorkflowType:               wf.GetUserGroups,
+		CreateUserGroupWorkflowType:             wf.CreateUserGroup,
+		UpdateUserGroupWorkflowType:             wf.UpdateUserGroup,
+		DeleteUserGroupWorkflowType:             wf.DeleteUserGroup,
+		SetUserGroupNamespaceAccessWorkflowType: wf.SetUserGroupNamespaceAccess,
+		AddUserGroupMemberWorkflowType:          wf.AddUserGroupMember,
+		RemoveUserGroupMemberWorkflowType:       wf.RemoveUserGroupMember,
+		GetUserGroupMembersWorkflowType:         wf.GetUserGroupMembers,
+	} {
+		w.RegisterWorkflowWithOptions(v, workflow.RegisterOptions{Name: k})
+	}
+}
+
+// Get a user group
+func (w *workflows) GetUserGroup(ctx workflow.Context, in *cloudservice.GetUserGroupRequest) (*cloudservice.GetUserGroupResponse, error) {
+	return activities.GetUserGroup(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Get multiple user groups
+func (w *workflows) GetUserGroups(ctx workflow.Context, in *cloudservice.GetUserGroupsRequest) (*cloudservice.GetUserGroupsResponse, error) {
+	return activities.GetUserGroups(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Create a user group
+func (w *workflows) CreateUserGroup(ctx workflow.Context, in *cloudservice.CreateUserGroupRequest) (*cloudservice.CreateUserGroupResponse, error) {
+	return activities.CreateUserGroup(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Update a user group
+func (w *workflows) UpdateUserGroup(ctx workflow.Context, in *cloudservice.UpdateUserGroupRequest) (*cloudservice.UpdateUserGroupResponse, error) {
+	return activities.UpdateUserGroup(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Delete a user group
+func (w *workflows) DeleteUserGroup(ctx workflow.Context, in *cloudservice.DeleteUserGroupRequest) (*cloudservice.DeleteUserGroupResponse, error) {
+	return activities.DeleteUserGroup(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Set user group namespace access
+func (w *workflows) SetUserGroupNamespaceAccess(ctx workflow.Context, in *cloudservice.SetUserGroupNamespaceAccessRequest) (*cloudservice.SetUserGroupNamespaceAccessResponse, error) {
+	return activities.SetUserGroupNamespaceAccess(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Add a member to a user group
+func (w *workflows) AddUserGroupMember(ctx workflow.Context, in *cloudservice.AddUserGroupMemberRequest) (*cloudservice.AddUserGroupMemberResponse, error) {
+	return activities.AddUserGroupMember(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Remove a member from a user group
+func (w *workflows) RemoveUserGroupMember(ctx workflow.Context, in *cloudservice.RemoveUserGroupMemberRequest) (*cloudservice.RemoveUserGroupMemberResponse, error) {
+	return activities.RemoveUserGroupMember(withInfiniteRetryActivityOptions(ctx), in)
+}
+
+// Get the members of a user group
+func (w *workflows) GetUserGroupMembers(ctx workflow.Context, in *cloudservice.GetUserGroupMembersRequest) (*cloudservice.GetUserGroupMembersResponse, error) {
+	return activities.GetUserGroupMembers(withInfiniteRetryActivityOptions(ctx), in)
+}
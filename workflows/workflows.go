@@ -16,8 +16,20 @@ type (
 	Workflows interface {
 		UserWorkflows
 		NamespaceWorkflows
+		NamespaceRegionWorkflows
+		FailoverDrillWorkflows
+		SearchAttributeWorkflows
+		ExportSinkWorkflows
+		NexusEndpointWorkflows
+		UsageWorkflows
+		AccountWorkflows
+		NamespaceCARotationWorkflows
 		RegionWorkflows
 		AsyncOperationWorkflows
+		ServiceAccountWorkflows
+		ApiKeyWorkflows
+		UserGroupWorkflows
+		AccessReportWorkflows
 	}
 	workflows struct{}
 )
@@ -34,8 +46,20 @@ func Register(w worker.Worker, wf Workflows, a *activities.Activities) {
 	// Register the workflows that we want to be able to use.
 	registerUserWorkflows(w, wf)
 	registerNamespaceWorkflows(w, wf)
+	registerNamespaceRegionWorkflows(w, wf)
+	registerFailoverDrillWorkflows(w, wf)
+	registerSearchAttributeWorkflows(w, wf)
+	registerExportSinkWorkflows(w, wf)
+	registerNexusEndpointWorkflows(w, wf)
+	registerUsageWorkflows(w, wf)
+	registerAccountWorkflows(w, wf)
+	registerNamespaceCARotationWorkflows(w, wf)
 	registerRegionWorkflows(w, wf)
 	registerAsyncOperationWorkflows(w, wf)
+	registerServiceAccountWorkflows(w, wf)
+	registerApiKeyWorkflows(w, wf)
+	registerUserGroupWorkflows(w, wf)
+	registerAccessReportWorkflows(w, wf)
 
 	// Register the activities that the workflows will use.
 	activities.Register(w, a)